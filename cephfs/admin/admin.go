@@ -0,0 +1,40 @@
+// Package admin manages CephFS file systems through the commands of the
+// monitors and of the volumes, mirroring and snap_schedule manager modules,
+// rather than through a mounted file system.
+package admin
+
+import (
+	"github.com/noahdesu/go-ceph/internal/commands"
+	"github.com/noahdesu/go-ceph/rados"
+)
+
+// FSAdmin sends CephFS administration commands over a connected cluster
+// handle.
+type FSAdmin struct {
+	conn *rados.Conn
+}
+
+// NewFromConn returns an FSAdmin that uses the connected conn.
+func NewFromConn(conn *rados.Conn) *FSAdmin {
+	return &FSAdmin{conn: conn}
+}
+
+func (fsa *FSAdmin) monCommand(args map[string]interface{}, out interface{}) error {
+	return commands.Mon(fsa.conn, args, out)
+}
+
+func (fsa *FSAdmin) mgrCommand(args map[string]interface{}, out interface{}) error {
+	return commands.Mgr(fsa.conn, args, out)
+}
+
+func (fsa *FSAdmin) mgrPlainCommand(args map[string]interface{}) (string, error) {
+	return commands.MgrPlain(fsa.conn, args)
+}
+
+// withGroup adds the subvolume group to args unless it is the default group.
+func withGroup(args map[string]interface{}, group string) map[string]interface{} {
+	if group != NoGroup {
+		args["group_name"] = group
+	}
+	return args
+}
@@ -0,0 +1,328 @@
+package admin_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/noahdesu/go-ceph/cephfs/admin"
+	"github.com/noahdesu/go-ceph/internal/admintest"
+	"github.com/noahdesu/go-ceph/rados"
+	"github.com/stretchr/testify/assert"
+)
+
+// the tests expect a file system of this name, and the volumes, mirroring
+// and snap_schedule manager modules to be enabled, as ci/micro-osd.sh sets
+// up; they are skipped otherwise
+const testFS = "cephfs"
+
+// getFSAdmin connects to the cluster, or skips the test if it lacks testFS or
+// any of the manager modules.
+func getFSAdmin(t *testing.T, modules ...string) *admin.FSAdmin {
+	conn, err := rados.NewConn()
+	assert.NoError(t, err)
+	err = conn.ReadDefaultConfigFile()
+	assert.NoError(t, err)
+	err = conn.Connect()
+	assert.NoError(t, err)
+	admintest.RequireMgr(t, conn, append([]string{"volumes"}, modules...)...)
+	admintest.RequireFS(t, conn, testFS)
+	return admin.NewFromConn(conn)
+}
+
+func TestMirroring(t *testing.T) {
+	fsa := getFSAdmin(t, "mirroring")
+
+	err := fsa.EnableMirroring(testFS)
+	assert.NoError(t, err)
+
+	peers, err := fsa.ListMirrorPeers(testFS)
+	assert.NoError(t, err)
+	assert.Len(t, peers, 0)
+
+	err = fsa.AddMirrorDirectory(testFS, "/")
+	assert.NoError(t, err)
+
+	err = fsa.AddMirrorDirectory(testFS, "/")
+	assert.Error(t, err)
+
+	_, err = fsa.MirrorDaemonStatus()
+	assert.NoError(t, err)
+
+	err = fsa.RemoveMirrorDirectory(testFS, "/")
+	assert.NoError(t, err)
+
+	err = fsa.RemoveMirrorPeer(testFS, "00000000-0000-0000-0000-000000000000")
+	assert.Error(t, err)
+
+	err = fsa.DisableMirroring(testFS)
+	assert.NoError(t, err)
+
+	err = fsa.EnableMirroring("no-such-fs")
+	assert.Error(t, err)
+}
+
+func TestVolumes(t *testing.T) {
+	fsa := getFSAdmin(t)
+
+	volumes, err := fsa.ListVolumes()
+	assert.NoError(t, err)
+	assert.Contains(t, volumes, testFS)
+
+	err = fsa.CreateVolume("govol")
+	assert.NoError(t, err)
+
+	volumes, err = fsa.ListVolumes()
+	assert.NoError(t, err)
+	assert.Contains(t, volumes, "govol")
+
+	err = fsa.RemoveVolume("govol")
+	assert.NoError(t, err)
+
+	volumes, err = fsa.ListVolumes()
+	assert.NoError(t, err)
+	assert.NotContains(t, volumes, "govol")
+}
+
+func TestSubVolumes(t *testing.T) {
+	fsa := getFSAdmin(t)
+
+	err := fsa.CreateSubVolume(testFS, admin.NoGroup, "gosub", &admin.SubVolumeOptions{
+		Size:              20 << 20,
+		Mode:              0750,
+		NamespaceIsolated: true,
+	})
+	assert.NoError(t, err)
+
+	names, err := fsa.ListSubVolumes(testFS, admin.NoGroup)
+	assert.NoError(t, err)
+	assert.Contains(t, names, "gosub")
+
+	info, err := fsa.SubVolumeInfo(testFS, admin.NoGroup, "gosub")
+	assert.NoError(t, err)
+	assert.Equal(t, admin.QuotaSize(20<<20), info.BytesQuota)
+	assert.Equal(t, 0750, info.Mode&0777)
+	assert.NotEmpty(t, info.PoolNamespace)
+	assert.False(t, info.CreatedAt.IsZero())
+
+	path, err := fsa.SubVolumePath(testFS, admin.NoGroup, "gosub")
+	assert.NoError(t, err)
+	assert.Equal(t, info.Path, path)
+
+	result, err := fsa.ResizeSubVolume(testFS, admin.NoGroup, "gosub", 40<<20, false)
+	assert.NoError(t, err)
+	assert.Equal(t, admin.QuotaSize(40<<20), result.BytesQuota)
+
+	result, err = fsa.ResizeSubVolume(testFS, admin.NoGroup, "gosub", admin.Infinite, false)
+	assert.NoError(t, err)
+	assert.Equal(t, admin.Infinite, result.BytesQuota)
+
+	err = fsa.RemoveSubVolume(testFS, admin.NoGroup, "gosub", admin.SubVolumeRemoveFlags{})
+	assert.NoError(t, err)
+
+	err = fsa.RemoveSubVolume(testFS, admin.NoGroup, "gosub", admin.SubVolumeRemoveFlags{})
+	assert.Error(t, err)
+	err = fsa.RemoveSubVolume(testFS, admin.NoGroup, "gosub", admin.SubVolumeRemoveFlags{Force: true})
+	assert.NoError(t, err)
+}
+
+func TestSubVolumeGroups(t *testing.T) {
+	fsa := getFSAdmin(t)
+
+	err := fsa.CreateSubVolumeGroup(testFS, "gogroup", &admin.SubVolumeGroupOptions{
+		Size: 100 << 20,
+		Mode: 0770,
+	})
+	assert.NoError(t, err)
+
+	groups, err := fsa.ListSubVolumeGroups(testFS)
+	assert.NoError(t, err)
+	assert.Contains(t, groups, "gogroup")
+
+	path, err := fsa.SubVolumeGroupPath(testFS, "gogroup")
+	assert.NoError(t, err)
+	assert.Contains(t, path, "gogroup")
+
+	err = fsa.ResizeSubVolumeGroup(testFS, "gogroup", 200<<20, true)
+	assert.NoError(t, err)
+
+	err = fsa.CreateSubVolume(testFS, "gogroup", "grouped", nil)
+	assert.NoError(t, err)
+
+	subPath, err := fsa.SubVolumePath(testFS, "gogroup", "grouped")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(subPath, path+"/"))
+
+	err = fsa.RemoveSubVolumeGroup(testFS, "gogroup", false)
+	assert.Error(t, err)
+
+	err = fsa.RemoveSubVolume(testFS, "gogroup", "grouped", admin.SubVolumeRemoveFlags{})
+	assert.NoError(t, err)
+	err = fsa.RemoveSubVolumeGroup(testFS, "gogroup", false)
+	assert.NoError(t, err)
+	err = fsa.RemoveSubVolumeGroup(testFS, "gogroup", true)
+	assert.NoError(t, err)
+}
+
+func TestSubVolumeSnapshots(t *testing.T) {
+	fsa := getFSAdmin(t)
+
+	err := fsa.CreateSubVolume(testFS, admin.NoGroup, "snapped", nil)
+	assert.NoError(t, err)
+	defer fsa.RemoveSubVolume(testFS, admin.NoGroup, "snapped", admin.SubVolumeRemoveFlags{Force: true})
+
+	err = fsa.CreateSubVolumeSnapshot(testFS, admin.NoGroup, "snapped", "snap1")
+	assert.NoError(t, err)
+
+	snaps, err := fsa.ListSubVolumeSnapshots(testFS, admin.NoGroup, "snapped")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"snap1"}, snaps)
+
+	info, err := fsa.SubVolumeSnapshotInfo(testFS, admin.NoGroup, "snapped", "snap1")
+	assert.NoError(t, err)
+	assert.Equal(t, "no", info.HasPendingClones)
+	assert.False(t, info.CreatedAt.IsZero())
+
+	err = fsa.CloneSubVolumeSnapshot(testFS, admin.NoGroup, "snapped", "snap1", "cloned", nil)
+	assert.NoError(t, err)
+
+	_, err = fsa.WaitForClone(context.Background(), testFS, admin.NoGroup, "cloned", 0)
+	assert.Equal(t, admin.ErrInvalidInterval, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	status, err := fsa.WaitForClone(ctx, testFS, admin.NoGroup, "cloned", 100*time.Millisecond)
+	assert.NoError(t, err)
+	assert.Equal(t, admin.CloneComplete, status.State)
+	assert.Nil(t, status.Failure)
+	assert.Equal(t, "snapped", status.Source.SubVolume)
+	assert.Equal(t, "snap1", status.Source.Snapshot)
+
+	err = fsa.CancelClone(testFS, admin.NoGroup, "cloned")
+	assert.Error(t, err)
+
+	err = fsa.RemoveSubVolume(testFS, admin.NoGroup, "cloned", admin.SubVolumeRemoveFlags{})
+	assert.NoError(t, err)
+
+	err = fsa.RemoveSubVolumeSnapshot(testFS, admin.NoGroup, "snapped", "snap1", false)
+	assert.NoError(t, err)
+	err = fsa.RemoveSubVolumeSnapshot(testFS, admin.NoGroup, "snapped", "snap1", false)
+	assert.Error(t, err)
+	err = fsa.RemoveSubVolumeSnapshot(testFS, admin.NoGroup, "snapped", "snap1", true)
+	assert.NoError(t, err)
+}
+
+func TestSnapSchedules(t *testing.T) {
+	fsa := getFSAdmin(t, "snap_schedule")
+
+	err := fsa.AddSnapSchedule(testFS, "/", "1h", "")
+	assert.NoError(t, err)
+	err = fsa.AddSnapRetention(testFS, "/", "h", 24)
+	assert.NoError(t, err)
+
+	schedules, err := fsa.ListSnapSchedules(testFS, "/", false)
+	assert.NoError(t, err)
+	if assert.Len(t, schedules, 1) {
+		assert.Equal(t, "1h", schedules[0].Schedule)
+		assert.Equal(t, 24, schedules[0].Retention["h"])
+		assert.True(t, schedules[0].Active)
+	}
+
+	err = fsa.DeactivateSnapSchedule(testFS, "/", "1h")
+	assert.NoError(t, err)
+	schedules, err = fsa.SnapScheduleStatus(testFS, "/")
+	assert.NoError(t, err)
+	if assert.Len(t, schedules, 1) {
+		assert.False(t, schedules[0].Active)
+	}
+	err = fsa.ActivateSnapSchedule(testFS, "/", "")
+	assert.NoError(t, err)
+
+	err = fsa.RemoveSnapRetention(testFS, "/", "h", 24)
+	assert.NoError(t, err)
+	err = fsa.RemoveSnapSchedule(testFS, "/", "1h", "")
+	assert.NoError(t, err)
+
+	_, err = fsa.SnapScheduleStatus(testFS, "/")
+	assert.Error(t, err)
+}
+
+func TestAuthorize(t *testing.T) {
+	fsa := getFSAdmin(t)
+
+	key, err := fsa.Authorize(testFS, "gotenant", "/", admin.ReadWrite)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, key)
+
+	again, err := fsa.Authorize(testFS, "gotenant", "/", admin.ReadWrite)
+	assert.NoError(t, err)
+	assert.Equal(t, key, again)
+
+	_, err = fsa.Authorize(testFS, "gotenant", "/", admin.ReadOnly)
+	assert.Error(t, err)
+
+	conn, err := rados.NewConn()
+	assert.NoError(t, err)
+	err = conn.ReadDefaultConfigFile()
+	assert.NoError(t, err)
+	err = conn.Connect()
+	assert.NoError(t, err)
+	defer conn.Shutdown()
+	_, _, err = conn.MonCommand([]byte(`{"prefix": "auth rm", "entity": "client.gotenant"}`))
+	assert.NoError(t, err)
+}
+
+func TestFSStatusStandbyRank(t *testing.T) {
+	reply := `{"mdsmap": [
+		{"rank": 0, "name": "a", "state": "active", "rate": 0, "dns": 10, "inos": 13, "dirs": 12, "caps": 0},
+		{"name": "b", "state": "standby"}
+	]}`
+	status := admin.FSStatus{}
+	err := json.Unmarshal([]byte(reply), &status)
+	assert.NoError(t, err)
+	if assert.Len(t, status.MDSMap, 2) {
+		assert.Equal(t, 0, status.MDSMap[0].Rank)
+		assert.Equal(t, uint64(10), status.MDSMap[0].Dns)
+		assert.Equal(t, -1, status.MDSMap[1].Rank)
+		assert.Equal(t, "standby", status.MDSMap[1].State)
+	}
+}
+
+func TestMDSStatus(t *testing.T) {
+	fsa := getFSAdmin(t)
+
+	status, err := fsa.FSStatus(testFS)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, status.MDSMap)
+	assert.NotEmpty(t, status.Pools)
+	assert.NotEmpty(t, status.MDSVersion)
+
+	ranks, err := fsa.FSRanks(testFS)
+	assert.NoError(t, err)
+	if assert.NotEmpty(t, ranks) {
+		assert.Equal(t, 0, ranks[0].Rank)
+		assert.Equal(t, "up:active", ranks[0].State)
+
+		md, err := fsa.MDSMetadata(ranks[0].Name)
+		assert.NoError(t, err)
+		assert.Equal(t, ranks[0].Name, md.Name)
+		assert.NotEmpty(t, md.Hostname)
+		assert.NotEmpty(t, md.CephVersion)
+		assert.NotEmpty(t, md.CPU)
+	}
+
+	all, err := fsa.ListMDSMetadata()
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, len(all), len(ranks))
+
+	standbys, err := fsa.Standbys()
+	assert.NoError(t, err)
+	for _, standby := range standbys {
+		assert.Equal(t, -1, standby.Rank)
+	}
+
+	_, err = fsa.MDSMetadata("nonexistent")
+	assert.Error(t, err)
+}
@@ -0,0 +1,38 @@
+package admin
+
+import (
+	"errors"
+)
+
+// ErrNoKey is returned by Authorize if the reply holds no key.
+var ErrNoKey = errors.New("fs authorize returned no key")
+
+// Access modes of Authorize.
+const (
+	ReadOnly  = "r"
+	ReadWrite = "rw"
+)
+
+// Authorize creates the client client.<id> with the capabilities to mount
+// path of the file system fs with access perm, ReadOnly or ReadWrite, and
+// returns its secret key. If the client exists, its capabilities must match.
+// It returns ErrNoKey if the monitor replies without a key.
+func (fsa *FSAdmin) Authorize(fs, id, path, perm string) (string, error) {
+	var keyring []struct {
+		Entity string `json:"entity"`
+		Key    string `json:"key"`
+	}
+	err := fsa.monCommand(map[string]interface{}{
+		"prefix":     "fs authorize",
+		"filesystem": fs,
+		"entity":     "client." + id,
+		"caps":       []string{path, perm},
+	}, &keyring)
+	if err != nil {
+		return "", err
+	}
+	if len(keyring) == 0 || keyring[0].Key == "" {
+		return "", ErrNoKey
+	}
+	return keyring[0].Key, nil
+}
@@ -0,0 +1,133 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// CloneState is the state of a clone of a subvolume snapshot.
+type CloneState string
+
+const (
+	ClonePending    = CloneState("pending")
+	CloneInProgress = CloneState("in-progress")
+	CloneComplete   = CloneState("complete")
+	CloneFailed     = CloneState("failed")
+	CloneCanceled   = CloneState("canceled")
+)
+
+// Done reports whether the clone has reached a final state.
+func (s CloneState) Done() bool {
+	return s == CloneComplete || s == CloneFailed || s == CloneCanceled
+}
+
+// CloneOptions are the parameters of CloneSubVolumeSnapshot.
+type CloneOptions struct {
+	// TargetGroup is the group the clone is created in.
+	TargetGroup string
+	// PoolLayout is the data pool of the clone, by default that of the
+	// source subvolume.
+	PoolLayout string
+}
+
+// CloneSubVolumeSnapshot starts cloning the snapshot snap of the subvolume
+// name into the new subvolume target. The data is copied in the background;
+// the clone can be used once CloneStatus reports CloneComplete.
+func (fsa *FSAdmin) CloneSubVolumeSnapshot(volume, group, name, snap, target string, o *CloneOptions) error {
+	args := withGroup(map[string]interface{}{
+		"prefix":          "fs subvolume snapshot clone",
+		"vol_name":        volume,
+		"sub_name":        name,
+		"snap_name":       snap,
+		"target_sub_name": target,
+	}, group)
+	if o != nil {
+		if o.TargetGroup != NoGroup {
+			args["target_group_name"] = o.TargetGroup
+		}
+		if o.PoolLayout != "" {
+			args["pool_layout"] = o.PoolLayout
+		}
+	}
+	return fsa.mgrCommand(args, nil)
+}
+
+// CloneSource is the snapshot a clone is made from.
+type CloneSource struct {
+	Volume    string `json:"volume"`
+	Group     string `json:"group"`
+	SubVolume string `json:"subvolume"`
+	Snapshot  string `json:"snapshot"`
+}
+
+// CloneFailure explains why a clone failed.
+type CloneFailure struct {
+	Errno    string `json:"errno"`
+	ErrorMsg string `json:"error_msg"`
+}
+
+// CloneStatus is the progress of a clone.
+type CloneStatus struct {
+	State  CloneState  `json:"state"`
+	Source CloneSource `json:"source"`
+	// Failure is set if State is CloneFailed.
+	Failure *CloneFailure `json:"failure"`
+}
+
+// CloneStatus returns the progress of the clone name in the group of the
+// volume.
+func (fsa *FSAdmin) CloneStatus(volume, group, name string) (*CloneStatus, error) {
+	var reply struct {
+		Status CloneStatus `json:"status"`
+	}
+	err := fsa.mgrCommand(withGroup(map[string]interface{}{
+		"prefix":     "fs clone status",
+		"vol_name":   volume,
+		"clone_name": name,
+	}, group), &reply)
+	if err != nil {
+		return nil, err
+	}
+	return &reply.Status, nil
+}
+
+// ErrInvalidInterval is returned by WaitForClone for an interval that is
+// not positive.
+var ErrInvalidInterval = errors.New("poll interval must be positive")
+
+// WaitForClone polls the status of the clone name every interval until it
+// has reached a final state, which it returns, or until ctx is done.
+func (fsa *FSAdmin) WaitForClone(ctx context.Context, volume, group, name string, interval time.Duration) (*CloneStatus, error) {
+	if interval <= 0 {
+		return nil, ErrInvalidInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		status, err := fsa.CloneStatus(volume, group, name)
+		if err != nil {
+			return nil, err
+		}
+		if status.State.Done() {
+			return status, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// CancelClone stops the pending or in progress clone name. The clone is left
+// in the CloneCanceled state and must be removed with RemoveSubVolume and
+// its Force flag.
+func (fsa *FSAdmin) CancelClone(volume, group, name string) error {
+	return fsa.mgrCommand(withGroup(map[string]interface{}{
+		"prefix":     "fs clone cancel",
+		"vol_name":   volume,
+		"clone_name": name,
+	}, group), nil)
+}
@@ -0,0 +1,170 @@
+package admin
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// FSStatusMDS is an MDS daemon serving a file system, or a standby daemon,
+// as reported by FSStatus.
+type FSStatusMDS struct {
+	// Rank is -1 for standby daemons, like MDSInfo.Rank.
+	Rank  int     `json:"rank"`
+	Name  string  `json:"name"`
+	State string  `json:"state"`
+	Rate  float64 `json:"rate"`
+	Dns   uint64  `json:"dns"`
+	Inos  uint64  `json:"inos"`
+	Dirs  uint64  `json:"dirs"`
+	Caps  uint64  `json:"caps"`
+}
+
+// UnmarshalJSON decodes the daemon, setting Rank to -1 if the daemon holds
+// no rank.
+func (m *FSStatusMDS) UnmarshalJSON(data []byte) error {
+	type fsStatusMDS FSStatusMDS
+	mds := fsStatusMDS{Rank: -1}
+	if err := json.Unmarshal(data, &mds); err != nil {
+		return err
+	}
+	*m = FSStatusMDS(mds)
+	return nil
+}
+
+// FSStatusPool is a pool of a file system as reported by FSStatus.
+type FSStatusPool struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Used  uint64 `json:"used"`
+	Avail uint64 `json:"avail"`
+}
+
+// FSStatusClients is the number of clients of a file system.
+type FSStatusClients struct {
+	FS      string `json:"fs"`
+	Clients int    `json:"clients"`
+}
+
+// FSStatusVersion is a Ceph version run by MDS daemons.
+type FSStatusVersion struct {
+	Version string   `json:"version"`
+	Daemons []string `json:"daemon"`
+}
+
+// FSStatus is the summary of a file system printed by "ceph fs status".
+type FSStatus struct {
+	Clients    []FSStatusClients `json:"clients"`
+	MDSMap     []FSStatusMDS     `json:"mdsmap"`
+	Pools      []FSStatusPool    `json:"pools"`
+	MDSVersion []FSStatusVersion `json:"mds_version"`
+}
+
+// FSStatus returns the MDS daemons, pools and clients of the file system fs.
+func (fsa *FSAdmin) FSStatus(fs string) (*FSStatus, error) {
+	status := &FSStatus{}
+	err := fsa.mgrCommand(map[string]interface{}{
+		"prefix": "fs status",
+		"fs":     fs,
+	}, status)
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// MDSMetadata describes the host and build of an MDS daemon.
+type MDSMetadata struct {
+	Name        string `json:"name"`
+	Addr        string `json:"addr"`
+	Hostname    string `json:"hostname"`
+	Arch        string `json:"arch"`
+	CephVersion string `json:"ceph_version"`
+	CephRelease string `json:"ceph_release"`
+	Distro      string `json:"distro"`
+	Kernel      string `json:"kernel_version"`
+	MemTotalKb  string `json:"mem_total_kb"`
+	// CPU is the model of the CPU of the host.
+	CPU string `json:"cpu"`
+}
+
+// MDSMetadata returns the metadata of the MDS daemon name.
+func (fsa *FSAdmin) MDSMetadata(name string) (*MDSMetadata, error) {
+	md := &MDSMetadata{}
+	err := fsa.monCommand(map[string]interface{}{
+		"prefix": "mds metadata",
+		"who":    name,
+	}, md)
+	if err != nil {
+		return nil, err
+	}
+	return md, nil
+}
+
+// ListMDSMetadata returns the metadata of all MDS daemons.
+func (fsa *FSAdmin) ListMDSMetadata() ([]MDSMetadata, error) {
+	var mds []MDSMetadata
+	err := fsa.monCommand(map[string]interface{}{
+		"prefix": "mds metadata",
+	}, &mds)
+	if err != nil {
+		return nil, err
+	}
+	return mds, nil
+}
+
+// MDSInfo is an MDS daemon in the MDS map.
+type MDSInfo struct {
+	Gid  uint64 `json:"gid"`
+	Name string `json:"name"`
+	// Rank is -1 for standby daemons.
+	Rank int `json:"rank"`
+	// State is the state of the daemon, like "up:active" or
+	// "up:standby-replay".
+	State string `json:"state"`
+	Addr  string `json:"addr"`
+}
+
+// FSRanks returns the MDS daemons of the file system fs, ordered by rank.
+// Standby-replay daemons follow the rank they replay the journal of.
+func (fsa *FSAdmin) FSRanks(fs string) ([]MDSInfo, error) {
+	var reply struct {
+		MDSMap struct {
+			Info map[string]MDSInfo `json:"info"`
+		} `json:"mdsmap"`
+	}
+	err := fsa.monCommand(map[string]interface{}{
+		"prefix":  "fs get",
+		"fs_name": fs,
+	}, &reply)
+	if err != nil {
+		return nil, err
+	}
+
+	ranks := make([]MDSInfo, 0, len(reply.MDSMap.Info))
+	for _, info := range reply.MDSMap.Info {
+		ranks = append(ranks, info)
+	}
+	sort.Slice(ranks, func(i, j int) bool {
+		if ranks[i].Rank != ranks[j].Rank {
+			return ranks[i].Rank < ranks[j].Rank
+		}
+		return ranks[i].State < ranks[j].State
+	})
+	return ranks, nil
+}
+
+// Standbys returns the standby MDS daemons that are not assigned to a file
+// system.
+func (fsa *FSAdmin) Standbys() ([]MDSInfo, error) {
+	var reply struct {
+		Standbys []MDSInfo `json:"standbys"`
+	}
+	err := fsa.monCommand(map[string]interface{}{
+		"prefix": "fs dump",
+	}, &reply)
+	if err != nil {
+		return nil, err
+	}
+	return reply.Standbys, nil
+}
@@ -0,0 +1,166 @@
+package admin
+
+// The mirroring manager module drives the cephfs-mirror daemons, which
+// replicate the snapshots of selected directories to peer file systems.
+
+// MirrorPeer is a remote file system that snapshots are mirrored to.
+type MirrorPeer struct {
+	ClientName string `json:"client_name"`
+	SiteName   string `json:"site_name"`
+	FSName     string `json:"fs_name"`
+	MonHost    string `json:"mon_host"`
+}
+
+// EnableMirroring enables snapshot mirroring for the file system fsName.
+func (fsa *FSAdmin) EnableMirroring(fsName string) error {
+	return fsa.mgrCommand(map[string]interface{}{
+		"prefix":  "fs snapshot mirror enable",
+		"fs_name": fsName,
+	}, nil)
+}
+
+// DisableMirroring disables snapshot mirroring for the file system fsName.
+// The configured peers and directories are removed.
+func (fsa *FSAdmin) DisableMirroring(fsName string) error {
+	return fsa.mgrCommand(map[string]interface{}{
+		"prefix":  "fs snapshot mirror disable",
+		"fs_name": fsName,
+	}, nil)
+}
+
+// AddMirrorPeer adds the file system remoteFSName of the cluster described by
+// remoteClusterSpec, in the form "client.<id>@<cluster>", as a peer of the
+// file system fsName. monHost and cephxKey may be empty if the remote
+// cluster is configured locally. Prefer BootstrapImportMirrorPeer, which
+// needs no credentials to be passed around by hand.
+func (fsa *FSAdmin) AddMirrorPeer(fsName, remoteClusterSpec, remoteFSName, monHost, cephxKey string) error {
+	args := map[string]interface{}{
+		"prefix":              "fs snapshot mirror peer_add",
+		"fs_name":             fsName,
+		"remote_cluster_spec": remoteClusterSpec,
+		"remote_fs_name":      remoteFSName,
+	}
+	if monHost != "" {
+		args["remote_mon_host"] = monHost
+	}
+	if cephxKey != "" {
+		args["cephx_key"] = cephxKey
+	}
+	return fsa.mgrCommand(args, nil)
+}
+
+// RemoveMirrorPeer removes the peer with the given uuid from the file system
+// fsName.
+func (fsa *FSAdmin) RemoveMirrorPeer(fsName, uuid string) error {
+	return fsa.mgrCommand(map[string]interface{}{
+		"prefix":    "fs snapshot mirror peer_remove",
+		"fs_name":   fsName,
+		"peer_uuid": uuid,
+	}, nil)
+}
+
+// ListMirrorPeers returns the peers of the file system fsName by uuid.
+func (fsa *FSAdmin) ListMirrorPeers(fsName string) (map[string]MirrorPeer, error) {
+	peers := map[string]MirrorPeer{}
+	err := fsa.mgrCommand(map[string]interface{}{
+		"prefix":  "fs snapshot mirror peer_list",
+		"fs_name": fsName,
+	}, &peers)
+	if err != nil {
+		return nil, err
+	}
+	return peers, nil
+}
+
+// BootstrapCreateMirrorPeer creates the user clientName, in the form
+// "client.<id>", that a peer cluster named siteName mirrors into the file
+// system fsName with. It returns a token for BootstrapImportMirrorPeer on
+// the sending cluster, which holds the credentials of the user.
+func (fsa *FSAdmin) BootstrapCreateMirrorPeer(fsName, clientName, siteName string) (string, error) {
+	var reply struct {
+		Token string `json:"token"`
+	}
+	err := fsa.mgrCommand(map[string]interface{}{
+		"prefix":      "fs snapshot mirror peer_bootstrap create",
+		"fs_name":     fsName,
+		"client_name": clientName,
+		"site_name":   siteName,
+	}, &reply)
+	if err != nil {
+		return "", err
+	}
+	return reply.Token, nil
+}
+
+// BootstrapImportMirrorPeer adds the peer described by a token of
+// BootstrapCreateMirrorPeer to the file system fsName.
+func (fsa *FSAdmin) BootstrapImportMirrorPeer(fsName, token string) error {
+	return fsa.mgrCommand(map[string]interface{}{
+		"prefix":  "fs snapshot mirror peer_bootstrap import",
+		"fs_name": fsName,
+		"token":   token,
+	}, nil)
+}
+
+// AddMirrorDirectory starts mirroring the snapshots of the directory at path
+// of the file system fsName to its peers.
+func (fsa *FSAdmin) AddMirrorDirectory(fsName, path string) error {
+	return fsa.mgrCommand(map[string]interface{}{
+		"prefix":  "fs snapshot mirror add",
+		"fs_name": fsName,
+		"path":    path,
+	}, nil)
+}
+
+// RemoveMirrorDirectory stops mirroring the directory at path of the file
+// system fsName.
+func (fsa *FSAdmin) RemoveMirrorDirectory(fsName, path string) error {
+	return fsa.mgrCommand(map[string]interface{}{
+		"prefix":  "fs snapshot mirror remove",
+		"fs_name": fsName,
+		"path":    path,
+	}, nil)
+}
+
+// MirrorPeerStatus is the state of mirroring to one peer, as seen by a
+// cephfs-mirror daemon.
+type MirrorPeerStatus struct {
+	UUID   string `json:"uuid"`
+	Remote struct {
+		ClientName  string `json:"client_name"`
+		ClusterName string `json:"cluster_name"`
+		FSName      string `json:"fs_name"`
+	} `json:"remote"`
+	Stats struct {
+		FailureCount  int `json:"failure_count"`
+		RecoveryCount int `json:"recovery_count"`
+	} `json:"stats"`
+}
+
+// MirrorFilesystemStatus is the state of mirroring one file system, as seen
+// by a cephfs-mirror daemon.
+type MirrorFilesystemStatus struct {
+	FilesystemId   int64              `json:"filesystem_id"`
+	Name           string             `json:"name"`
+	DirectoryCount int                `json:"directory_count"`
+	Peers          []MirrorPeerStatus `json:"peers"`
+}
+
+// MirrorDaemonStatus is the state of a cephfs-mirror daemon.
+type MirrorDaemonStatus struct {
+	DaemonId    int64                    `json:"daemon_id"`
+	Filesystems []MirrorFilesystemStatus `json:"filesystems"`
+}
+
+// MirrorDaemonStatus returns the state of the running cephfs-mirror
+// daemons.
+func (fsa *FSAdmin) MirrorDaemonStatus() ([]MirrorDaemonStatus, error) {
+	status := []MirrorDaemonStatus{}
+	err := fsa.mgrCommand(map[string]interface{}{
+		"prefix": "fs snapshot mirror daemon status",
+	}, &status)
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
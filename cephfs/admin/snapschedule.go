@@ -0,0 +1,143 @@
+package admin
+
+import (
+	"strconv"
+)
+
+// SnapSchedule is a snapshot schedule of the snap_schedule manager module.
+type SnapSchedule struct {
+	FS      string `json:"fs"`
+	SubVol  string `json:"subvol"`
+	Path    string `json:"path"`
+	RelPath string `json:"rel_path"`
+	// Schedule is the interval between snapshots, a number followed by
+	// one of the units m, h, d, w, M and y, like "1h".
+	Schedule string `json:"schedule"`
+	// Retention maps the units of the retention rules to the number of
+	// snapshots kept for them, like {"h": 24, "d": 7}.
+	Retention    map[string]int `json:"retention"`
+	Start        string         `json:"start"`
+	Created      string         `json:"created"`
+	First        string         `json:"first"`
+	Last         string         `json:"last"`
+	LastPruned   string         `json:"last_pruned"`
+	CreatedCount int            `json:"created_count"`
+	PrunedCount  int            `json:"pruned_count"`
+	Active       bool           `json:"active"`
+}
+
+// AddSnapSchedule schedules snapshots of path in the file system fs every
+// schedule, like "1h", starting at start, an ISO 8601 time like
+// "2023-01-01T00:00:00". An empty start starts at the next full hour.
+func (fsa *FSAdmin) AddSnapSchedule(fs, path, schedule, start string) error {
+	args := map[string]interface{}{
+		"prefix":        "fs snap-schedule add",
+		"fs":            fs,
+		"path":          path,
+		"snap_schedule": schedule,
+	}
+	if start != "" {
+		args["start"] = start
+	}
+	return fsa.mgrCommand(args, nil)
+}
+
+// RemoveSnapSchedule removes the schedule of path in the file system fs that
+// repeats every schedule and starts at start. With an empty start, all
+// schedules of path with that interval are removed, and with an empty
+// schedule all schedules of path.
+func (fsa *FSAdmin) RemoveSnapSchedule(fs, path, schedule, start string) error {
+	args := map[string]interface{}{
+		"prefix": "fs snap-schedule remove",
+		"fs":     fs,
+		"path":   path,
+	}
+	if schedule != "" {
+		args["repeat"] = schedule
+	}
+	if start != "" {
+		args["start"] = start
+	}
+	return fsa.mgrCommand(args, nil)
+}
+
+// ActivateSnapSchedule resumes the schedules of path in the file system fs,
+// or only the one repeating every schedule if it is not empty.
+func (fsa *FSAdmin) ActivateSnapSchedule(fs, path, schedule string) error {
+	return fsa.snapScheduleState("fs snap-schedule activate", fs, path, schedule)
+}
+
+// DeactivateSnapSchedule pauses the schedules of path in the file system fs,
+// or only the one repeating every schedule if it is not empty.
+func (fsa *FSAdmin) DeactivateSnapSchedule(fs, path, schedule string) error {
+	return fsa.snapScheduleState("fs snap-schedule deactivate", fs, path, schedule)
+}
+
+func (fsa *FSAdmin) snapScheduleState(prefix, fs, path, schedule string) error {
+	args := map[string]interface{}{
+		"prefix": prefix,
+		"fs":     fs,
+		"path":   path,
+	}
+	if schedule != "" {
+		args["repeat"] = schedule
+	}
+	return fsa.mgrCommand(args, nil)
+}
+
+// AddSnapRetention keeps count snapshots of path in the file system fs for
+// the period unit, one of m, h, d, w, M and y, or n for the last count
+// snapshots regardless of their age. Snapshots not kept by any rule of path
+// are pruned.
+func (fsa *FSAdmin) AddSnapRetention(fs, path, period string, count int) error {
+	return fsa.snapRetention("fs snap-schedule retention add", fs, path, period, count)
+}
+
+// RemoveSnapRetention removes the retention rule of path in the file system
+// fs for the period unit and count.
+func (fsa *FSAdmin) RemoveSnapRetention(fs, path, period string, count int) error {
+	return fsa.snapRetention("fs snap-schedule retention remove", fs, path, period, count)
+}
+
+func (fsa *FSAdmin) snapRetention(prefix, fs, path, period string, count int) error {
+	return fsa.mgrCommand(map[string]interface{}{
+		"prefix":                   prefix,
+		"fs":                       fs,
+		"path":                     path,
+		"retention_spec_or_period": period,
+		"retention_count":          strconv.Itoa(count),
+	}, nil)
+}
+
+// ListSnapSchedules returns the schedules of path in the file system fs, and
+// with recursive those of all directories below path.
+func (fsa *FSAdmin) ListSnapSchedules(fs, path string, recursive bool) ([]SnapSchedule, error) {
+	args := map[string]interface{}{
+		"prefix": "fs snap-schedule list",
+		"fs":     fs,
+		"path":   path,
+	}
+	if recursive {
+		args["recursive"] = true
+	}
+	var schedules []SnapSchedule
+	if err := fsa.mgrCommand(args, &schedules); err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// SnapScheduleStatus returns the schedules of path in the file system fs
+// along with the times and counts of the snapshots taken and pruned.
+func (fsa *FSAdmin) SnapScheduleStatus(fs, path string) ([]SnapSchedule, error) {
+	var schedules []SnapSchedule
+	err := fsa.mgrCommand(map[string]interface{}{
+		"prefix": "fs snap-schedule status",
+		"fs":     fs,
+		"path":   path,
+	}, &schedules)
+	if err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
@@ -0,0 +1,75 @@
+package admin
+
+// CreateSubVolumeSnapshot creates the snapshot snap of the subvolume name.
+func (fsa *FSAdmin) CreateSubVolumeSnapshot(volume, group, name, snap string) error {
+	return fsa.mgrCommand(withGroup(map[string]interface{}{
+		"prefix":    "fs subvolume snapshot create",
+		"vol_name":  volume,
+		"sub_name":  name,
+		"snap_name": snap,
+	}, group), nil)
+}
+
+// RemoveSubVolumeSnapshot removes the snapshot snap of the subvolume name.
+// With force, a snapshot that does not exist is ignored. Snapshots that are
+// being cloned can not be removed.
+func (fsa *FSAdmin) RemoveSubVolumeSnapshot(volume, group, name, snap string, force bool) error {
+	args := withGroup(map[string]interface{}{
+		"prefix":    "fs subvolume snapshot rm",
+		"vol_name":  volume,
+		"sub_name":  name,
+		"snap_name": snap,
+	}, group)
+	if force {
+		args["force"] = true
+	}
+	return fsa.mgrCommand(args, nil)
+}
+
+// ListSubVolumeSnapshots returns the names of the snapshots of the
+// subvolume name.
+func (fsa *FSAdmin) ListSubVolumeSnapshots(volume, group, name string) ([]string, error) {
+	var snaps []struct {
+		Name string `json:"name"`
+	}
+	err := fsa.mgrCommand(withGroup(map[string]interface{}{
+		"prefix":   "fs subvolume snapshot ls",
+		"vol_name": volume,
+		"sub_name": name,
+	}, group), &snaps)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(snaps))
+	for i, snap := range snaps {
+		names[i] = snap.Name
+	}
+	return names, nil
+}
+
+// SubVolumeSnapshotInfo describes a snapshot of a subvolume.
+type SubVolumeSnapshotInfo struct {
+	CreatedAt TimeStamp `json:"created_at"`
+	DataPool  string    `json:"data_pool"`
+	// HasPendingClones is "yes" while clones of the snapshot are pending
+	// or in progress, "no" otherwise.
+	HasPendingClones string `json:"has_pending_clones"`
+	Size             uint64 `json:"size"`
+}
+
+// SubVolumeSnapshotInfo returns the description of the snapshot snap of the
+// subvolume name.
+func (fsa *FSAdmin) SubVolumeSnapshotInfo(volume, group, name, snap string) (*SubVolumeSnapshotInfo, error) {
+	info := &SubVolumeSnapshotInfo{}
+	err := fsa.mgrCommand(withGroup(map[string]interface{}{
+		"prefix":    "fs subvolume snapshot info",
+		"vol_name":  volume,
+		"sub_name":  name,
+		"snap_name": snap,
+	}, group), info)
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
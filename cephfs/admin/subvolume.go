@@ -0,0 +1,198 @@
+package admin
+
+import (
+	"strconv"
+	"strings"
+)
+
+// NoGroup selects the default subvolume group, which holds the subvolumes
+// that were not created in a group.
+const NoGroup = ""
+
+// SubVolumeOptions are the parameters of a new subvolume. Fields left at
+// their zero value take the defaults of the volumes module.
+type SubVolumeOptions struct {
+	// Size is the quota of the subvolume, Infinite for none.
+	Size QuotaSize
+	Uid  int
+	Gid  int
+	Mode uint32
+	// PoolLayout is the data pool of the subvolume.
+	PoolLayout string
+	// NamespaceIsolated stores the data of the subvolume in a RADOS
+	// namespace of its own.
+	NamespaceIsolated bool
+}
+
+// CreateSubVolume creates the subvolume name in the group of the volume.
+// Creating a subvolume that exists already succeeds without changes.
+func (fsa *FSAdmin) CreateSubVolume(volume, group, name string, o *SubVolumeOptions) error {
+	args := withGroup(map[string]interface{}{
+		"prefix":   "fs subvolume create",
+		"vol_name": volume,
+		"sub_name": name,
+	}, group)
+	if o != nil {
+		if o.Size != Infinite {
+			args["size"] = uint64(o.Size)
+		}
+		if o.Uid != 0 {
+			args["uid"] = o.Uid
+		}
+		if o.Gid != 0 {
+			args["gid"] = o.Gid
+		}
+		if o.Mode != 0 {
+			args["mode"] = strconv.FormatUint(uint64(o.Mode), 8)
+		}
+		if o.PoolLayout != "" {
+			args["pool_layout"] = o.PoolLayout
+		}
+		if o.NamespaceIsolated {
+			args["namespace_isolated"] = true
+		}
+	}
+	return fsa.mgrCommand(args, nil)
+}
+
+// ListSubVolumes returns the names of the subvolumes in the group of the
+// volume.
+func (fsa *FSAdmin) ListSubVolumes(volume, group string) ([]string, error) {
+	var subvolumes []struct {
+		Name string `json:"name"`
+	}
+	err := fsa.mgrCommand(withGroup(map[string]interface{}{
+		"prefix":   "fs subvolume ls",
+		"vol_name": volume,
+	}, group), &subvolumes)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(subvolumes))
+	for i, subvolume := range subvolumes {
+		names[i] = subvolume.Name
+	}
+	return names, nil
+}
+
+// SubVolumeRemoveFlags modify how RemoveSubVolume removes a subvolume.
+type SubVolumeRemoveFlags struct {
+	// Force ignores that the subvolume does not exist.
+	Force bool
+	// RetainSnapshots removes the data of the subvolume but keeps its
+	// snapshots, for example to clone them later.
+	RetainSnapshots bool
+}
+
+// RemoveSubVolume removes the subvolume name from the group of the volume.
+// The data is purged in the background.
+func (fsa *FSAdmin) RemoveSubVolume(volume, group, name string, flags SubVolumeRemoveFlags) error {
+	args := withGroup(map[string]interface{}{
+		"prefix":   "fs subvolume rm",
+		"vol_name": volume,
+		"sub_name": name,
+	}, group)
+	if flags.Force {
+		args["force"] = true
+	}
+	if flags.RetainSnapshots {
+		args["retain_snapshots"] = true
+	}
+	return fsa.mgrCommand(args, nil)
+}
+
+// SubVolumeResizeResult is the usage of a subvolume after a resize.
+type SubVolumeResizeResult struct {
+	BytesUsed    uint64
+	BytesQuota   QuotaSize
+	BytesPercent string
+}
+
+// ResizeSubVolume changes the quota of the subvolume name to newSize, or
+// removes it with Infinite. With noShrink, the quota is not set below the
+// current usage.
+func (fsa *FSAdmin) ResizeSubVolume(volume, group, name string, newSize QuotaSize, noShrink bool) (*SubVolumeResizeResult, error) {
+	args := withGroup(map[string]interface{}{
+		"prefix":   "fs subvolume resize",
+		"vol_name": volume,
+		"sub_name": name,
+		"new_size": newSize.resizeArg(),
+	}, group)
+	if noShrink {
+		args["no_shrink"] = true
+	}
+
+	// the usage is reported as a list of single entry objects
+	var reply []struct {
+		BytesUsed    *uint64    `json:"bytes_used"`
+		BytesQuota   *QuotaSize `json:"bytes_quota"`
+		BytesPercent *string    `json:"bytes_pcent"`
+	}
+	if err := fsa.mgrCommand(args, &reply); err != nil {
+		return nil, err
+	}
+
+	result := &SubVolumeResizeResult{}
+	for _, r := range reply {
+		if r.BytesUsed != nil {
+			result.BytesUsed = *r.BytesUsed
+		}
+		if r.BytesQuota != nil {
+			result.BytesQuota = *r.BytesQuota
+		}
+		if r.BytesPercent != nil {
+			result.BytesPercent = *r.BytesPercent
+		}
+	}
+	return result, nil
+}
+
+// SubVolumeInfo describes a subvolume.
+type SubVolumeInfo struct {
+	Type          string    `json:"type"`
+	Path          string    `json:"path"`
+	State         string    `json:"state"`
+	Uid           int       `json:"uid"`
+	Gid           int       `json:"gid"`
+	Mode          int       `json:"mode"`
+	BytesPercent  string    `json:"bytes_pcent"`
+	BytesUsed     uint64    `json:"bytes_used"`
+	BytesQuota    QuotaSize `json:"bytes_quota"`
+	DataPool      string    `json:"data_pool"`
+	PoolNamespace string    `json:"pool_namespace"`
+	Features      []string  `json:"features"`
+	MonAddrs      []string  `json:"mon_addrs"`
+	CreatedAt     TimeStamp `json:"created_at"`
+	Atime         TimeStamp `json:"atime"`
+	Mtime         TimeStamp `json:"mtime"`
+	Ctime         TimeStamp `json:"ctime"`
+}
+
+// SubVolumeInfo returns the description of the subvolume name.
+func (fsa *FSAdmin) SubVolumeInfo(volume, group, name string) (*SubVolumeInfo, error) {
+	info := &SubVolumeInfo{}
+	err := fsa.mgrCommand(withGroup(map[string]interface{}{
+		"prefix":   "fs subvolume info",
+		"vol_name": volume,
+		"sub_name": name,
+	}, group), info)
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// SubVolumePath returns the path of the subvolume name in the file system,
+// which clients mount to access it.
+func (fsa *FSAdmin) SubVolumePath(volume, group, name string) (string, error) {
+	path, err := fsa.mgrPlainCommand(withGroup(map[string]interface{}{
+		"prefix":   "fs subvolume getpath",
+		"vol_name": volume,
+		"sub_name": name,
+	}, group))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(path), nil
+}
@@ -0,0 +1,112 @@
+package admin
+
+import (
+	"strconv"
+	"strings"
+)
+
+// SubVolumeGroupOptions are the parameters of a new subvolume group. Fields
+// left at their zero value take the defaults of the volumes module.
+type SubVolumeGroupOptions struct {
+	// Size is the quota shared by all subvolumes of the group, Infinite
+	// for none.
+	Size QuotaSize
+	Uid  int
+	Gid  int
+	Mode uint32
+	// PoolLayout is the data pool that subvolumes of the group use by
+	// default.
+	PoolLayout string
+}
+
+// CreateSubVolumeGroup creates the subvolume group name in the volume.
+func (fsa *FSAdmin) CreateSubVolumeGroup(volume, name string, o *SubVolumeGroupOptions) error {
+	args := map[string]interface{}{
+		"prefix":     "fs subvolumegroup create",
+		"vol_name":   volume,
+		"group_name": name,
+	}
+	if o != nil {
+		if o.Size != Infinite {
+			args["size"] = uint64(o.Size)
+		}
+		if o.Uid != 0 {
+			args["uid"] = o.Uid
+		}
+		if o.Gid != 0 {
+			args["gid"] = o.Gid
+		}
+		if o.Mode != 0 {
+			args["mode"] = strconv.FormatUint(uint64(o.Mode), 8)
+		}
+		if o.PoolLayout != "" {
+			args["pool_layout"] = o.PoolLayout
+		}
+	}
+	return fsa.mgrCommand(args, nil)
+}
+
+// ListSubVolumeGroups returns the names of the subvolume groups of the
+// volume.
+func (fsa *FSAdmin) ListSubVolumeGroups(volume string) ([]string, error) {
+	var groups []struct {
+		Name string `json:"name"`
+	}
+	err := fsa.mgrCommand(map[string]interface{}{
+		"prefix":   "fs subvolumegroup ls",
+		"vol_name": volume,
+	}, &groups)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(groups))
+	for i, group := range groups {
+		names[i] = group.Name
+	}
+	return names, nil
+}
+
+// RemoveSubVolumeGroup removes the empty subvolume group name from the
+// volume. With force, a group that does not exist is ignored.
+func (fsa *FSAdmin) RemoveSubVolumeGroup(volume, name string, force bool) error {
+	args := map[string]interface{}{
+		"prefix":     "fs subvolumegroup rm",
+		"vol_name":   volume,
+		"group_name": name,
+	}
+	if force {
+		args["force"] = true
+	}
+	return fsa.mgrCommand(args, nil)
+}
+
+// ResizeSubVolumeGroup changes the quota shared by the subvolumes of the
+// group name to newSize, or removes it with Infinite. With noShrink, the
+// quota is not set below the current usage.
+func (fsa *FSAdmin) ResizeSubVolumeGroup(volume, name string, newSize QuotaSize, noShrink bool) error {
+	args := map[string]interface{}{
+		"prefix":     "fs subvolumegroup resize",
+		"vol_name":   volume,
+		"group_name": name,
+		"new_size":   newSize.resizeArg(),
+	}
+	if noShrink {
+		args["no_shrink"] = true
+	}
+	return fsa.mgrCommand(args, nil)
+}
+
+// SubVolumeGroupPath returns the path of the subvolume group name in the
+// file system.
+func (fsa *FSAdmin) SubVolumeGroupPath(volume, name string) (string, error) {
+	path, err := fsa.mgrPlainCommand(map[string]interface{}{
+		"prefix":     "fs subvolumegroup getpath",
+		"vol_name":   volume,
+		"group_name": name,
+	})
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(path), nil
+}
@@ -0,0 +1,66 @@
+package admin
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// QuotaSize is the size limit of a subvolume or group in bytes. The volumes
+// module reports subvolumes without a limit as "infinite", which decodes as
+// Infinite.
+type QuotaSize uint64
+
+// Infinite is the QuotaSize of a subvolume without a size limit.
+const Infinite QuotaSize = 0
+
+// UnmarshalJSON decodes a size in bytes or "infinite".
+func (q *QuotaSize) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		if s == "infinite" {
+			*q = Infinite
+			return nil
+		}
+		v, err := strconv.ParseUint(s, 10, 64)
+		*q = QuotaSize(v)
+		return err
+	}
+
+	var v uint64
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*q = QuotaSize(v)
+	return nil
+}
+
+// resizeArg is the value of a new size for the resize commands.
+func (q QuotaSize) resizeArg() string {
+	if q == Infinite {
+		return "inf"
+	}
+	return strconv.FormatUint(uint64(q), 10)
+}
+
+// timeStampFormat is the format of the times the volumes module reports.
+const timeStampFormat = "2006-01-02 15:04:05"
+
+// TimeStamp is a time reported by the volumes module.
+type TimeStamp struct {
+	time.Time
+}
+
+// UnmarshalJSON decodes a time in the format of the volumes module.
+func (ts *TimeStamp) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	t, err := time.Parse(timeStampFormat, s)
+	if err != nil {
+		return err
+	}
+	ts.Time = t
+	return nil
+}
@@ -0,0 +1,40 @@
+package admin
+
+// ListVolumes returns the names of the CephFS volumes. A volume is a file
+// system together with its metadata and data pools.
+func (fsa *FSAdmin) ListVolumes() ([]string, error) {
+	var volumes []struct {
+		Name string `json:"name"`
+	}
+	err := fsa.mgrCommand(map[string]interface{}{
+		"prefix": "fs volume ls",
+	}, &volumes)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(volumes))
+	for i, volume := range volumes {
+		names[i] = volume.Name
+	}
+	return names, nil
+}
+
+// CreateVolume creates the volume name, with new pools and MDS daemons
+// deployed by the orchestrator if one is configured.
+func (fsa *FSAdmin) CreateVolume(name string) error {
+	return fsa.mgrCommand(map[string]interface{}{
+		"prefix": "fs volume create",
+		"name":   name,
+	}, nil)
+}
+
+// RemoveVolume removes the volume name including its pools and all data in
+// it. The monitors must allow removing pools (mon_allow_pool_delete).
+func (fsa *FSAdmin) RemoveVolume(name string) error {
+	return fsa.mgrCommand(map[string]interface{}{
+		"prefix":               "fs volume rm",
+		"vol_name":             name,
+		"yes-i-really-mean-it": "--yes-i-really-mean-it",
+	}, nil)
+}
@@ -0,0 +1,143 @@
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <errno.h>
+#include <stdint.h>
+#include <stdlib.h>
+#include <stdbool.h>
+#include <sys/uio.h>
+#include <cephfs/libcephfs.h>
+
+extern void asyncIOCallback(struct ceph_ll_io_info *info);
+
+static inline struct ceph_ll_io_info *new_io_info(struct Fh *fh,
+		struct iovec *iov, int iovcnt, int64_t off, bool write,
+		uintptr_t index) {
+	struct ceph_ll_io_info *info = calloc(1, sizeof(*info));
+	if (info == NULL) {
+		return NULL;
+	}
+	info->callback = asyncIOCallback;
+	info->priv = (void *)index;
+	info->fh = fh;
+	info->iov = iov;
+	info->iovcnt = iovcnt;
+	info->off = off;
+	info->write = write;
+	return info;
+}
+*/
+import "C"
+
+import (
+	"github.com/noahdesu/go-ceph/internal/callbacks"
+	"io"
+	"unsafe"
+)
+
+// AsyncIO is a read or write started with FileHandle.PreadvAsync or
+// PwritevAsync. The buffers passed in must not be used until it is done.
+type AsyncIO struct {
+	done chan struct{}
+	info *C.struct_ceph_ll_io_info
+	iov  *iovecs
+	read bool
+	n    int
+	err  error
+}
+
+var asyncIOs = callbacks.New()
+
+//export asyncIOCallback
+func asyncIOCallback(info *C.struct_ceph_ll_io_info) {
+	index := uintptr(info.priv)
+	aio, ok := asyncIOs.Lookup(index).(*AsyncIO)
+	asyncIOs.Remove(index)
+	if ok {
+		aio.complete(int64(info.result))
+	}
+}
+
+func (aio *AsyncIO) complete(result int64) {
+	if result < 0 {
+		aio.err = CephError(result)
+	} else if aio.read {
+		aio.iov.toGo(int(result))
+		if result == 0 && totalLen(aio.iov.bufs) > 0 {
+			aio.err = io.EOF
+		}
+	}
+	if result > 0 {
+		aio.n = int(result)
+	}
+
+	aio.iov.free()
+	C.free(unsafe.Pointer(aio.info))
+	aio.info = nil
+	close(aio.done)
+}
+
+// Done returns a channel that is closed once the I/O has completed.
+func (aio *AsyncIO) Done() <-chan struct{} {
+	return aio.done
+}
+
+// Wait waits for the I/O to complete and returns the number of bytes read
+// or written. A read returns io.EOF if nothing was read at the end of the
+// file.
+func (aio *AsyncIO) Wait() (int, error) {
+	<-aio.done
+	return aio.n, aio.err
+}
+
+func (fh *FileHandle) startAsync(data [][]byte, offset int64, write bool) (*AsyncIO, error) {
+	if fh.fh == nil {
+		return nil, CephError(-C.EBADF)
+	}
+	if offset < 0 {
+		return nil, CephError(-C.EINVAL)
+	}
+
+	aio := &AsyncIO{
+		done: make(chan struct{}),
+		iov:  newIovecs(data),
+		read: !write,
+	}
+	if write {
+		aio.iov.fromGo()
+	}
+
+	index := asyncIOs.Add(aio)
+	aio.info = C.new_io_info(fh.fh, aio.iov.pointer(), aio.iov.count(),
+		C.int64_t(offset), C.bool(write), C.uintptr_t(index))
+	if aio.info == nil {
+		asyncIOs.Remove(index)
+		aio.iov.free()
+		return nil, CephError(-C.ENOMEM)
+	}
+
+	// the callback is only called if the I/O has been started
+	ret := C.ceph_ll_nonblocking_readv_writev(fh.mount.mount, aio.info)
+	if ret < 0 {
+		asyncIOs.Remove(index)
+		aio.iov.free()
+		C.free(unsafe.Pointer(aio.info))
+		return nil, CephError(ret)
+	}
+	return aio, nil
+}
+
+// PreadvAsync starts reading from the file starting at offset into the
+// buffers in order, and returns without waiting for the data. Many reads
+// and writes can be in flight at the same time.
+func (fh *FileHandle) PreadvAsync(data [][]byte, offset int64) (*AsyncIO, error) {
+	return fh.startAsync(data, offset, false)
+}
+
+// PwritevAsync starts writing the buffers in order to the file starting at
+// offset, and returns without waiting for the write to complete.
+func (fh *FileHandle) PwritevAsync(data [][]byte, offset int64) (*AsyncIO, error) {
+	return fh.startAsync(data, offset, true)
+}
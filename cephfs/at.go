@@ -0,0 +1,68 @@
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <stdlib.h>
+#include <fcntl.h>
+#include <cephfs/libcephfs.h>
+*/
+import "C"
+
+import (
+	"path"
+	"unsafe"
+)
+
+// The *At methods resolve name relative to the directory opened as dir,
+// like openat(2) and its relatives. The directory is found by its handle,
+// so the calls are not affected by concurrent renames of the directory or
+// of its parents. Open the directory with os.O_DIRECTORY.
+
+// OpenAt opens the file name in the directory like MountInfo.Open.
+func (dir *File) OpenAt(name string, flags int, mode uint32) (*File, error) {
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	ret := C.ceph_openat(dir.mount.mount, dir.fd, c_name, C.int(flags),
+		C.mode_t(mode))
+	if ret < 0 {
+		return nil, CephError(ret)
+	}
+	return &File{mount: dir.mount, fd: ret, name: path.Join(dir.name, name)}, nil
+}
+
+// UnlinkAt removes the file name from the directory, or the empty directory
+// name if flags contains AtRemoveDir.
+func (dir *File) UnlinkAt(name string, flags AtFlags) error {
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	return getError(C.ceph_unlinkat(dir.mount.mount, dir.fd, c_name,
+		C.int(flags)))
+}
+
+// MkdirAt creates the directory name in the directory with the permissions
+// mode.
+func (dir *File) MkdirAt(name string, mode uint32) error {
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	return getError(C.ceph_mkdirat(dir.mount.mount, dir.fd, c_name,
+		C.mode_t(mode)))
+}
+
+// StatxAt returns the attributes selected by want of the file name in the
+// directory like MountInfo.Statx.
+func (dir *File) StatxAt(name string, want StatxMask, flags AtFlags) (*CephStatx, error) {
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	var stx C.struct_ceph_statx
+	ret := C.ceph_statxat(dir.mount.mount, dir.fd, c_name, &stx, C.uint(want),
+		C.uint(flags))
+	if ret < 0 {
+		return nil, CephError(ret)
+	}
+	return newCephStatx(&stx), nil
+}
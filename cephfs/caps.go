@@ -0,0 +1,90 @@
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <stdlib.h>
+#include <cephfs/libcephfs.h>
+*/
+import "C"
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+	"unsafe"
+)
+
+// GetFileCaps returns the capabilities, a mask of the CEPH_CAP_* bits, that
+// the client holds for the file at path. Without capabilities, the client
+// has to ask the MDS for the attributes and may not cache data.
+func (mount *MountInfo) GetFileCaps(path string) (int, error) {
+	c_path := C.CString(path)
+	defer C.free(unsafe.Pointer(c_path))
+
+	ret := C.ceph_debug_get_file_caps(mount.mount, c_path)
+	if ret < 0 {
+		return 0, CephError(ret)
+	}
+	return int(ret), nil
+}
+
+// GetCaps returns the capabilities the client holds for the open file, like
+// GetFileCaps.
+func (f *File) GetCaps() (int, error) {
+	ret := C.ceph_debug_get_fd_caps(f.mount.mount, f.fd)
+	if ret < 0 {
+		return 0, CephError(ret)
+	}
+	return int(ret), nil
+}
+
+// CapCount returns the number of capabilities the client holds across its
+// sessions with all active MDS daemons, as counted by the daemons.
+func (mount *MountInfo) CapCount() (int, error) {
+	sessions, err := mount.OwnSessions("*")
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, session := range sessions {
+		count += session.NumCaps
+	}
+	return count, nil
+}
+
+// SetCacheSize limits the number of inodes the client caches. The client
+// trims its cache to the limit shortly after it is lowered, returning the
+// capabilities of the inodes that are dropped. This bounds the memory of
+// long running clients. To have the MDS recall the capabilities at once, use
+// DropMDSCache.
+func (mount *MountInfo) SetCacheSize(inodes int) error {
+	return mount.SetConfigOption("client_cache_size", strconv.Itoa(inodes))
+}
+
+// ForceStatx returns the attributes want of path, fetching them from the
+// MDS even if the client holds capabilities that would let it use its
+// cached ones. It is only a forced stat: the cached dentries and file data
+// of path are not invalidated, libcephfs has no call for that.
+func (mount *MountInfo) ForceStatx(path string, want StatxMask) (*CephStatx, error) {
+	return mount.Statx(path, want, AtStatxForceSync)
+}
+
+// DropMDSCache asks the MDS daemons selected by mdsSpec, as accepted by
+// MdsCommand, to recall capabilities from their clients and trim their
+// cache, waiting up to timeout for the clients to release them. The clients
+// drop the inodes they hold no more capabilities for. This affects every
+// client of the daemons, not only this mount; libcephfs has no call to trim
+// the cache of a single mount.
+func (mount *MountInfo) DropMDSCache(mdsSpec string, timeout time.Duration) error {
+	cmd, err := json.Marshal(map[string]interface{}{
+		"prefix":  "cache drop",
+		"timeout": int64(timeout / time.Second),
+	})
+	if err != nil {
+		return err
+	}
+	_, _, err = mount.MdsCommand(mdsSpec, cmd)
+	return err
+}
@@ -3,13 +3,21 @@ package cephfs
 /*
 #cgo LDFLAGS: -lcephfs
 #cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <errno.h>
 #include <stdlib.h>
 #include <cephfs/libcephfs.h>
 */
 import "C"
+import "errors"
 import "fmt"
+import "strconv"
+import "time"
 import "unsafe"
 
+// ErrMountTimeout is returned by MountWithTimeout if the cluster could not be
+// reached in time.
+var ErrMountTimeout = errors.New("cephfs: mount timed out")
+
 //
 type CephError int
 
@@ -17,6 +25,13 @@ func (e CephError) Error() string {
 	return fmt.Sprintf("cephfs: ret=%d", e)
 }
 
+func getError(e C.int) error {
+	if e < 0 {
+		return CephError(e)
+	}
+	return nil
+}
+
 //
 type MountInfo struct {
 	mount *C.struct_ceph_mount_info
@@ -32,6 +47,28 @@ func CreateMount() (*MountInfo, error) {
 	}
 }
 
+// CreateMountWithId creates a mount handle that authenticates as the client
+// with the given id, for example "admin" for client.admin.
+func CreateMountWithId(id string) (*MountInfo, error) {
+	c_id := C.CString(id)
+	defer C.free(unsafe.Pointer(c_id))
+
+	mount := &MountInfo{}
+	ret := C.ceph_create(&mount.mount, c_id)
+	if ret != 0 {
+		return nil, CephError(ret)
+	}
+	return mount, nil
+}
+
+// ReadConfigFile configures the mount using a Ceph configuration file.
+func (mount *MountInfo) ReadConfigFile(path string) error {
+	c_path := C.CString(path)
+	defer C.free(unsafe.Pointer(c_path))
+
+	return getError(C.ceph_conf_read_file(mount.mount, c_path))
+}
+
 func (mount *MountInfo) ReadDefaultConfigFile() error {
 	ret := C.ceph_conf_read_file(mount.mount, nil)
 	if ret == 0 {
@@ -50,6 +87,104 @@ func (mount *MountInfo) Mount() error {
 	}
 }
 
+// MountWithTimeout mounts the file system like Mount, but fails with
+// ErrMountTimeout if the monitors and MDS daemons can not be reached within
+// timeout, for example because of a wrong monitor address. It sets the
+// client_mount_timeout option, which otherwise defaults to five minutes.
+// A mount in progress can not be interrupted, so no context is taken.
+func (mount *MountInfo) MountWithTimeout(timeout time.Duration) error {
+	err := mount.SetConfigOption("client_mount_timeout",
+		strconv.FormatFloat(timeout.Seconds(), 'f', -1, 64))
+	if err != nil {
+		return err
+	}
+
+	err = mount.Mount()
+	if err == CephError(-C.ETIMEDOUT) {
+		return ErrMountTimeout
+	}
+	return err
+}
+
+// MountWithRoot mounts the file system with root as the root directory, so
+// that no path outside of it can be reached through the mount. libcephfs has
+// no read-only mount option; confine a client to read-only access of its
+// subtree with its cephx capabilities instead, for example those created by
+// "ceph fs authorize <fs> client.<id> <root> r", and use CreateMountWithId.
+func (mount *MountInfo) MountWithRoot(root string) error {
+	c_root := C.CString(root)
+	defer C.free(unsafe.Pointer(c_root))
+
+	return getError(C.ceph_mount(mount.mount, c_root))
+}
+
+// SetConfigOption sets the value of the configuration option identified by
+// the given name. Options that the client reads at startup, like client_oc,
+// must be set before Mount. Runtime options, like client_readahead_max_bytes,
+// can also be changed while mounted and only affect this mount.
+func (mount *MountInfo) SetConfigOption(option, value string) error {
+	c_opt, c_val := C.CString(option), C.CString(value)
+	defer C.free(unsafe.Pointer(c_opt))
+	defer C.free(unsafe.Pointer(c_val))
+
+	return getError(C.ceph_conf_set(mount.mount, c_opt, c_val))
+}
+
+// GetConfigOption returns the value of the configuration option identified
+// by the given name, as currently in effect for the mount.
+func (mount *MountInfo) GetConfigOption(option string) (string, error) {
+	c_opt := C.CString(option)
+	defer C.free(unsafe.Pointer(c_opt))
+
+	buf := make([]byte, 4096)
+	for {
+		ret := C.ceph_conf_get(mount.mount, c_opt,
+			(*C.char)(unsafe.Pointer(&buf[0])), C.size_t(len(buf)))
+		if ret == -C.ENAMETOOLONG {
+			buf = make([]byte, len(buf)*2)
+			continue
+		} else if ret < 0 {
+			return "", CephError(ret)
+		}
+
+		return C.GoString((*C.char)(unsafe.Pointer(&buf[0]))), nil
+	}
+}
+
+// SelectFilesystem selects the file system with the given name to be
+// mounted, on clusters with more than one file system. It must be called
+// before Mount; without it the default file system is mounted.
+func (mount *MountInfo) SelectFilesystem(name string) error {
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	return getError(C.ceph_select_filesystem(mount.mount, c_name))
+}
+
+// IsMounted reports whether the mount handle is mounted.
+func (mount *MountInfo) IsMounted() bool {
+	return C.ceph_is_mounted(mount.mount) == 1
+}
+
+// Unmount unmounts the file system. Open files and directories are closed.
+// The mount handle can be mounted again.
+func (mount *MountInfo) Unmount() error {
+	return getError(C.ceph_unmount(mount.mount))
+}
+
+// Release destroys the mount handle, which must be unmounted. The handle can
+// not be used afterwards.
+func (mount *MountInfo) Release() error {
+	ret := C.ceph_release(mount.mount)
+	if ret != 0 {
+		return CephError(ret)
+	}
+	mount.mount = nil
+	return nil
+}
+
+// SyncFs writes the buffered data and metadata of all files of the mount to
+// the cluster.
 func (mount *MountInfo) SyncFs() error {
 	ret := C.ceph_sync_fs(mount.mount)
 	if ret == 0 {
@@ -59,11 +194,15 @@ func (mount *MountInfo) SyncFs() error {
 	}
 }
 
+// CurrentDir returns the current working directory of the mount, against
+// which relative paths are resolved.
 func (mount *MountInfo) CurrentDir() string {
 	c_dir := C.ceph_getcwd(mount.mount)
 	return C.GoString(c_dir)
 }
 
+// ChangeDir changes the current working directory of the mount. It is shared
+// by all users of the mount.
 func (mount *MountInfo) ChangeDir(path string) error {
 	c_path := C.CString(path)
 	defer C.free(unsafe.Pointer(c_path))
@@ -87,3 +226,20 @@ func (mount *MountInfo) MakeDir(path string, mode uint32) error {
 		return CephError(ret)
 	}
 }
+
+// MakeDirs creates the directory at path along with any missing parent
+// directories, like mkdir -p.
+func (mount *MountInfo) MakeDirs(path string, mode uint32) error {
+	c_path := C.CString(path)
+	defer C.free(unsafe.Pointer(c_path))
+
+	return getError(C.ceph_mkdirs(mount.mount, c_path, C.mode_t(mode)))
+}
+
+// RemoveDir removes the empty directory at path.
+func (mount *MountInfo) RemoveDir(path string) error {
+	c_path := C.CString(path)
+	defer C.free(unsafe.Pointer(c_path))
+
+	return getError(C.ceph_rmdir(mount.mount, c_path))
+}
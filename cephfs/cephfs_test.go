@@ -1,6 +1,17 @@
 package cephfs_test
 
+import "bytes"
+import "encoding/json"
+import "errors"
+import "io"
+import "io/fs"
+import "os"
+import "strings"
+import "sync"
+import "syscall"
 import "testing"
+import "testing/fstest"
+import "time"
 import "github.com/noahdesu/go-ceph/cephfs"
 import "github.com/stretchr/testify/assert"
 
@@ -64,3 +75,1953 @@ func TestChangeDir(t *testing.T) {
 	assert.Equal(t, dir1, "/")
 	assert.Equal(t, dir2, "/asdf")
 }
+
+func fsConnect(t *testing.T) *cephfs.MountInfo {
+	mount, err := cephfs.CreateMount()
+	assert.NoError(t, err)
+	assert.NotNil(t, mount)
+
+	err = mount.ReadDefaultConfigFile()
+	assert.NoError(t, err)
+
+	err = mount.Mount()
+	assert.NoError(t, err)
+
+	return mount
+}
+
+func fsDisconnect(t *testing.T, mount *cephfs.MountInfo) {
+	err := mount.Unmount()
+	assert.NoError(t, err)
+
+	err = mount.Release()
+	assert.NoError(t, err)
+}
+
+func TestMountLifecycle(t *testing.T) {
+	mount, err := cephfs.CreateMountWithId("admin")
+	assert.NoError(t, err)
+	assert.NotNil(t, mount)
+
+	err = mount.ReadDefaultConfigFile()
+	assert.NoError(t, err)
+
+	err = mount.SetConfigOption("client_mount_timeout", "30")
+	assert.NoError(t, err)
+
+	value, err := mount.GetConfigOption("client_mount_timeout")
+	assert.NoError(t, err)
+	assert.Equal(t, value, "30")
+
+	err = mount.SetConfigOption("no_such_option", "1")
+	assert.Error(t, err)
+
+	assert.False(t, mount.IsMounted())
+
+	err = mount.Mount()
+	assert.NoError(t, err)
+	assert.True(t, mount.IsMounted())
+
+	// a mounted handle can not be released
+	err = mount.Release()
+	assert.Error(t, err)
+
+	err = mount.Unmount()
+	assert.NoError(t, err)
+	assert.False(t, mount.IsMounted())
+
+	err = mount.Mount()
+	assert.NoError(t, err)
+
+	fsDisconnect(t, mount)
+}
+
+func TestMountWithRoot(t *testing.T) {
+	mount := fsConnect(t)
+	err := mount.MakeDir("/subtree", 0755)
+	assert.NoError(t, err)
+	err = mount.MakeDir("/subtree/inner", 0755)
+	assert.NoError(t, err)
+	fsDisconnect(t, mount)
+
+	mount, err = cephfs.CreateMount()
+	assert.NoError(t, err)
+
+	err = mount.ReadDefaultConfigFile()
+	assert.NoError(t, err)
+
+	err = mount.MountWithRoot("/subtree")
+	assert.NoError(t, err)
+
+	err = mount.ChangeDir("/inner")
+	assert.NoError(t, err)
+	assert.Equal(t, mount.CurrentDir(), "/inner")
+
+	err = mount.ChangeDir("/subtree")
+	assert.Error(t, err)
+
+	fsDisconnect(t, mount)
+
+	mount, err = cephfs.CreateMount()
+	assert.NoError(t, err)
+
+	err = mount.ReadDefaultConfigFile()
+	assert.NoError(t, err)
+
+	err = mount.MountWithRoot("/no-such-dir")
+	assert.Error(t, err)
+
+	err = mount.Release()
+	assert.NoError(t, err)
+}
+
+func TestSelectFilesystem(t *testing.T) {
+	mount, err := cephfs.CreateMount()
+	assert.NoError(t, err)
+
+	err = mount.ReadDefaultConfigFile()
+	assert.NoError(t, err)
+
+	err = mount.SelectFilesystem("no-such-fs")
+	assert.NoError(t, err)
+
+	// the file system is only looked up when mounting
+	err = mount.Mount()
+	assert.Error(t, err)
+
+	err = mount.Release()
+	assert.NoError(t, err)
+
+	mount, err = cephfs.CreateMount()
+	assert.NoError(t, err)
+
+	err = mount.ReadDefaultConfigFile()
+	assert.NoError(t, err)
+
+	err = mount.SelectFilesystem("cephfs")
+	assert.NoError(t, err)
+
+	err = mount.Mount()
+	assert.NoError(t, err)
+
+	fsDisconnect(t, mount)
+}
+
+func TestWriteAtWritesAll(t *testing.T) {
+	mount := fsConnect(t)
+	defer fsDisconnect(t, mount)
+
+	f, err := mount.Open("/writeat", os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	assert.NoError(t, err)
+
+	data := bytes.Repeat([]byte("0123456789abcdef"), 2<<20)
+	n, err := f.WriteAt(data, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, len(data), n)
+
+	st, err := f.Fstatx(cephfs.StatxSize, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(len(data)), st.Size)
+
+	err = f.Close()
+	assert.NoError(t, err)
+
+	f, err = mount.Open("/writeat", os.O_RDONLY, 0)
+	assert.NoError(t, err)
+	n, err = f.WriteAt(data, 0)
+	assert.Error(t, err)
+	assert.Equal(t, 0, n)
+
+	err = f.Close()
+	assert.NoError(t, err)
+	err = mount.Unlink("/writeat")
+	assert.NoError(t, err)
+}
+
+func TestFileReadWrite(t *testing.T) {
+	mount := fsConnect(t)
+	defer fsDisconnect(t, mount)
+
+	f, err := mount.Open("/file.txt", os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	assert.NoError(t, err)
+
+	n, err := f.Write([]byte("hello "))
+	assert.NoError(t, err)
+	assert.Equal(t, n, 6)
+
+	n, err = f.Write([]byte("world"))
+	assert.NoError(t, err)
+	assert.Equal(t, n, 5)
+
+	n, err = f.WriteAt([]byte("W"), 6)
+	assert.NoError(t, err)
+	assert.Equal(t, n, 1)
+
+	pos, err := f.Seek(0, io.SeekStart)
+	assert.NoError(t, err)
+	assert.Equal(t, pos, int64(0))
+
+	buf := make([]byte, 32)
+	n, err = f.Read(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, string(buf[:n]), "hello World")
+
+	_, err = f.Read(buf)
+	assert.Equal(t, err, io.EOF)
+
+	buf = make([]byte, 5)
+	n, err = f.ReadAt(buf, 6)
+	assert.NoError(t, err)
+	assert.Equal(t, string(buf[:n]), "World")
+
+	n, err = f.ReadAt(buf, 8)
+	assert.Equal(t, err, io.EOF)
+	assert.Equal(t, string(buf[:n]), "rld")
+
+	pos, err = f.Seek(-5, io.SeekEnd)
+	assert.NoError(t, err)
+	assert.Equal(t, pos, int64(6))
+
+	err = f.Close()
+	assert.NoError(t, err)
+
+	_, err = f.Read(buf)
+	assert.Error(t, err)
+
+	_, err = mount.Open("/no-such-file", os.O_RDONLY, 0)
+	assert.Error(t, err)
+}
+
+func TestFileVectoredIO(t *testing.T) {
+	mount := fsConnect(t)
+	defer fsDisconnect(t, mount)
+
+	f, err := mount.Open("/vectored.txt", os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	assert.NoError(t, err)
+	defer f.Close()
+
+	n, err := f.Pwritev([][]byte{
+		[]byte("one "),
+		[]byte(""),
+		[]byte("two "),
+		[]byte("three"),
+	}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, n, 13)
+
+	b1 := make([]byte, 4)
+	b2 := make([]byte, 4)
+	b3 := make([]byte, 8)
+	n, err = f.Preadv([][]byte{b1, b2, b3}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, n, 13)
+	assert.Equal(t, string(b1), "one ")
+	assert.Equal(t, string(b2), "two ")
+	assert.Equal(t, string(b3[:5]), "three")
+
+	n, err = f.Preadv([][]byte{b1}, 13)
+	assert.Equal(t, err, io.EOF)
+	assert.Equal(t, n, 0)
+}
+
+func TestReadDir(t *testing.T) {
+	mount := fsConnect(t)
+	defer fsDisconnect(t, mount)
+
+	err := mount.MakeDir("/readdir", 0755)
+	assert.NoError(t, err)
+	err = mount.MakeDir("/readdir/subdir", 0755)
+	assert.NoError(t, err)
+	f, err := mount.Open("/readdir/file", os.O_WRONLY|os.O_CREATE, 0644)
+	assert.NoError(t, err)
+	err = f.Close()
+	assert.NoError(t, err)
+
+	dir, err := mount.OpenDir("/readdir")
+	assert.NoError(t, err)
+
+	entries := map[string]cephfs.DType{}
+	for {
+		entry, err := dir.ReadDir()
+		assert.NoError(t, err)
+		if entry == nil {
+			break
+		}
+		assert.NotEqual(t, entry.Inode, uint64(0))
+		entries[entry.Name] = entry.Type
+	}
+	assert.Equal(t, entries, map[string]cephfs.DType{
+		".":      cephfs.DTypeDir,
+		"..":     cephfs.DTypeDir,
+		"subdir": cephfs.DTypeDir,
+		"file":   cephfs.DTypeReg,
+	})
+
+	dir.RewindDir()
+	entry, err := dir.ReadDir()
+	assert.NoError(t, err)
+	assert.NotNil(t, entry)
+
+	err = dir.Close()
+	assert.NoError(t, err)
+
+	_, err = dir.ReadDir()
+	assert.Error(t, err)
+
+	_, err = mount.OpenDir("/readdir/file")
+	assert.Error(t, err)
+}
+
+func TestReadDirPlus(t *testing.T) {
+	mount := fsConnect(t)
+	defer fsDisconnect(t, mount)
+
+	err := mount.MakeDir("/readdirplus", 0755)
+	assert.NoError(t, err)
+	f, err := mount.Open("/readdirplus/file", os.O_WRONLY|os.O_CREATE, 0600)
+	assert.NoError(t, err)
+	_, err = f.Write([]byte("0123456789"))
+	assert.NoError(t, err)
+	err = f.Close()
+	assert.NoError(t, err)
+
+	dir, err := mount.OpenDir("/readdirplus")
+	assert.NoError(t, err)
+	defer dir.Close()
+
+	found := false
+	for {
+		entry, err := dir.ReadDirPlus(cephfs.StatxBasicStats, 0)
+		assert.NoError(t, err)
+		if entry == nil {
+			break
+		}
+		assert.Equal(t, entry.Inode, entry.Statx.Inode)
+		if entry.Name == "file" {
+			found = true
+			assert.Equal(t, entry.Type, cephfs.DTypeReg)
+			assert.Equal(t, entry.Statx.Size, uint64(10))
+			assert.Equal(t, entry.Statx.Mode&0777, uint16(0600))
+			assert.True(t, entry.Statx.Mask&cephfs.StatxSize != 0)
+		}
+	}
+	assert.True(t, found)
+}
+
+func TestMakeDirs(t *testing.T) {
+	mount := fsConnect(t)
+	defer fsDisconnect(t, mount)
+
+	err := mount.MakeDirs("/mkdirs/a/b/c", 0755)
+	assert.NoError(t, err)
+
+	err = mount.ChangeDir("/mkdirs/a/b/c")
+	assert.NoError(t, err)
+
+	err = mount.ChangeDir("/")
+	assert.NoError(t, err)
+
+	// a non-empty directory can not be removed
+	err = mount.RemoveDir("/mkdirs/a/b")
+	assert.Error(t, err)
+
+	for _, path := range []string{"/mkdirs/a/b/c", "/mkdirs/a/b", "/mkdirs/a", "/mkdirs"} {
+		err = mount.RemoveDir(path)
+		assert.NoError(t, err)
+	}
+
+	err = mount.ChangeDir("/mkdirs")
+	assert.Error(t, err)
+}
+
+func writeFile(t *testing.T, mount *cephfs.MountInfo, path string, data []byte) {
+	f, err := mount.Open(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	assert.NoError(t, err)
+	_, err = f.Write(data)
+	assert.NoError(t, err)
+	err = f.Close()
+	assert.NoError(t, err)
+}
+
+func readFile(t *testing.T, mount *cephfs.MountInfo, path string) []byte {
+	f, err := mount.Open(path, os.O_RDONLY, 0)
+	assert.NoError(t, err)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	buf := make([]byte, 4096)
+	n, err := f.Read(buf)
+	if err != io.EOF {
+		assert.NoError(t, err)
+	}
+	return buf[:n]
+}
+
+func TestUnlinkRename(t *testing.T) {
+	mount := fsConnect(t)
+	defer fsDisconnect(t, mount)
+
+	err := mount.MakeDirs("/rename", 0755)
+	assert.NoError(t, err)
+
+	writeFile(t, mount, "/rename/a", []byte("a"))
+	writeFile(t, mount, "/rename/b", []byte("b"))
+
+	// renaming over an existing file replaces it
+	err = mount.Rename("/rename/a", "/rename/b")
+	assert.NoError(t, err)
+	assert.Equal(t, readFile(t, mount, "/rename/b"), []byte("a"))
+
+	_, err = mount.Open("/rename/a", os.O_RDONLY, 0)
+	assert.Error(t, err)
+
+	// a directory can not replace a file
+	err = mount.MakeDir("/rename/dir", 0755)
+	assert.NoError(t, err)
+	err = mount.Rename("/rename/dir", "/rename/b")
+	assert.Error(t, err)
+
+	err = mount.Unlink("/rename/b")
+	assert.NoError(t, err)
+
+	err = mount.Unlink("/rename/b")
+	assert.Error(t, err)
+
+	// directories are removed with RemoveDir
+	err = mount.Unlink("/rename/dir")
+	assert.Error(t, err)
+
+	err = mount.RemoveDir("/rename/dir")
+	assert.NoError(t, err)
+	err = mount.RemoveDir("/rename")
+	assert.NoError(t, err)
+}
+
+func TestLinks(t *testing.T) {
+	mount := fsConnect(t)
+	defer fsDisconnect(t, mount)
+
+	err := mount.MakeDirs("/links", 0755)
+	assert.NoError(t, err)
+	writeFile(t, mount, "/links/file", []byte("linked"))
+
+	err = mount.Link("/links/file", "/links/hardlink")
+	assert.NoError(t, err)
+	assert.Equal(t, readFile(t, mount, "/links/hardlink"), []byte("linked"))
+
+	err = mount.Symlink("file", "/links/symlink")
+	assert.NoError(t, err)
+	assert.Equal(t, readFile(t, mount, "/links/symlink"), []byte("linked"))
+
+	target, err := mount.Readlink("/links/symlink")
+	assert.NoError(t, err)
+	assert.Equal(t, target, "file")
+
+	longTarget := strings.Repeat("x", 1000)
+	err = mount.Symlink(longTarget, "/links/dangling")
+	assert.NoError(t, err)
+	target, err = mount.Readlink("/links/dangling")
+	assert.NoError(t, err)
+	assert.Equal(t, target, longTarget)
+
+	_, err = mount.Readlink("/links/file")
+	assert.Error(t, err)
+
+	err = mount.Unlink("/links/file")
+	assert.NoError(t, err)
+	assert.Equal(t, readFile(t, mount, "/links/hardlink"), []byte("linked"))
+
+	for _, name := range []string{"hardlink", "symlink", "dangling"} {
+		err = mount.Unlink("/links/" + name)
+		assert.NoError(t, err)
+	}
+	err = mount.RemoveDir("/links")
+	assert.NoError(t, err)
+}
+
+func TestStatx(t *testing.T) {
+	mount := fsConnect(t)
+	defer fsDisconnect(t, mount)
+
+	err := mount.MakeDirs("/statx", 0755)
+	assert.NoError(t, err)
+
+	before := time.Now().Add(-time.Minute).Unix()
+	f, err := mount.Open("/statx/file", os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0640)
+	assert.NoError(t, err)
+	_, err = f.Write([]byte("statx"))
+	assert.NoError(t, err)
+
+	stx, err := f.Fstatx(cephfs.StatxAllStats, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, stx.Size, uint64(5))
+	assert.Equal(t, stx.Mode&0777, uint16(0640))
+	assert.Equal(t, stx.Nlink, uint32(1))
+	assert.True(t, stx.Mask&cephfs.StatxBtime != 0)
+	assert.True(t, stx.Btime.Sec >= before)
+
+	err = f.Close()
+	assert.NoError(t, err)
+
+	err = mount.Symlink("file", "/statx/symlink")
+	assert.NoError(t, err)
+
+	stx2, err := mount.Statx("/statx/symlink", cephfs.StatxAllStats, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, stx2.Inode, stx.Inode)
+	assert.Equal(t, stx2.Btime, stx.Btime)
+
+	lstx, err := mount.Statx("/statx/symlink", cephfs.StatxIno|cephfs.StatxMode,
+		cephfs.AtSymlinkNofollow)
+	assert.NoError(t, err)
+	assert.NotEqual(t, lstx.Inode, stx.Inode)
+	assert.Equal(t, lstx.Mode&syscall.S_IFMT, uint16(syscall.S_IFLNK))
+
+	_, err = mount.Statx("/statx/missing", cephfs.StatxBasicStats, 0)
+	assert.Error(t, err)
+
+	err = mount.Unlink("/statx/symlink")
+	assert.NoError(t, err)
+	err = mount.Unlink("/statx/file")
+	assert.NoError(t, err)
+	err = mount.RemoveDir("/statx")
+	assert.NoError(t, err)
+}
+
+func TestPermissions(t *testing.T) {
+	mount := fsConnect(t)
+	defer fsDisconnect(t, mount)
+
+	err := mount.MakeDirs("/perms", 0755)
+	assert.NoError(t, err)
+	writeFile(t, mount, "/perms/file", []byte("perms"))
+	err = mount.Symlink("file", "/perms/symlink")
+	assert.NoError(t, err)
+
+	statx := func(path string, flags cephfs.AtFlags) *cephfs.CephStatx {
+		stx, err := mount.Statx(path, cephfs.StatxBasicStats, flags)
+		assert.NoError(t, err)
+		return stx
+	}
+
+	err = mount.Chmod("/perms/file", 0600)
+	assert.NoError(t, err)
+	assert.Equal(t, statx("/perms/file", 0).Mode&0777, uint16(0600))
+
+	err = mount.Chown("/perms/file", 1000, 1001)
+	assert.NoError(t, err)
+	stx := statx("/perms/file", 0)
+	assert.Equal(t, stx.Uid, uint32(1000))
+	assert.Equal(t, stx.Gid, uint32(1001))
+
+	err = mount.Chown("/perms/file", -1, 1002)
+	assert.NoError(t, err)
+	stx = statx("/perms/file", 0)
+	assert.Equal(t, stx.Uid, uint32(1000))
+	assert.Equal(t, stx.Gid, uint32(1002))
+
+	err = mount.Lchown("/perms/symlink", 2000, 2000)
+	assert.NoError(t, err)
+	assert.Equal(t, statx("/perms/symlink", cephfs.AtSymlinkNofollow).Uid, uint32(2000))
+	assert.Equal(t, statx("/perms/file", 0).Uid, uint32(1000))
+
+	f, err := mount.Open("/perms/file", os.O_RDONLY, 0)
+	assert.NoError(t, err)
+
+	err = f.Fchmod(0644)
+	assert.NoError(t, err)
+	err = f.Fchown(0, 0)
+	assert.NoError(t, err)
+
+	err = f.Close()
+	assert.NoError(t, err)
+
+	stx = statx("/perms/file", 0)
+	assert.Equal(t, stx.Mode&0777, uint16(0644))
+	assert.Equal(t, stx.Uid, uint32(0))
+	assert.Equal(t, stx.Gid, uint32(0))
+
+	err = mount.Chmod("/perms/missing", 0600)
+	assert.Error(t, err)
+
+	err = mount.Unlink("/perms/symlink")
+	assert.NoError(t, err)
+	err = mount.Unlink("/perms/file")
+	assert.NoError(t, err)
+	err = mount.RemoveDir("/perms")
+	assert.NoError(t, err)
+}
+
+func TestTruncate(t *testing.T) {
+	mount := fsConnect(t)
+	defer fsDisconnect(t, mount)
+
+	writeFile(t, mount, "/truncate", []byte("0123456789"))
+
+	err := mount.Truncate("/truncate", 4)
+	assert.NoError(t, err)
+	assert.Equal(t, readFile(t, mount, "/truncate"), []byte("0123"))
+
+	f, err := mount.Open("/truncate", os.O_RDWR, 0)
+	assert.NoError(t, err)
+
+	err = f.Ftruncate(6)
+	assert.NoError(t, err)
+
+	stx, err := f.Fstatx(cephfs.StatxSize, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, stx.Size, uint64(6))
+
+	err = f.Close()
+	assert.NoError(t, err)
+	assert.Equal(t, readFile(t, mount, "/truncate"), []byte("0123\x00\x00"))
+
+	err = mount.Truncate("/truncate", -1)
+	assert.Error(t, err)
+
+	err = mount.Unlink("/truncate")
+	assert.NoError(t, err)
+}
+
+func TestUtimes(t *testing.T) {
+	mount := fsConnect(t)
+	defer fsDisconnect(t, mount)
+
+	writeFile(t, mount, "/utimes", []byte("utimes"))
+	err := mount.Symlink("utimes", "/utimes.link")
+	assert.NoError(t, err)
+
+	atime := cephfs.Timespec{Sec: 1500000000, Nsec: 123456789}
+	mtime := cephfs.Timespec{Sec: 1400000000, Nsec: 987654321}
+	err = mount.Utimes("/utimes.link", atime, mtime)
+	assert.NoError(t, err)
+
+	stx, err := mount.Statx("/utimes", cephfs.StatxAtime|cephfs.StatxMtime, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, stx.Atime, atime)
+	assert.Equal(t, stx.Mtime, mtime)
+
+	omit := cephfs.Timespec{Nsec: cephfs.UtimeOmit}
+	newMtime := cephfs.Timespec{Sec: 1600000000}
+	err = mount.Utimes("/utimes", omit, newMtime)
+	assert.NoError(t, err)
+
+	stx, err = mount.Statx("/utimes", cephfs.StatxAtime|cephfs.StatxMtime, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, stx.Atime, atime)
+	assert.Equal(t, stx.Mtime, newMtime)
+
+	err = mount.Lutimes("/utimes.link", omit, mtime)
+	assert.NoError(t, err)
+	lstx, err := mount.Statx("/utimes.link", cephfs.StatxMtime,
+		cephfs.AtSymlinkNofollow)
+	assert.NoError(t, err)
+	assert.Equal(t, lstx.Mtime, mtime)
+
+	f, err := mount.Open("/utimes", os.O_RDONLY, 0)
+	assert.NoError(t, err)
+
+	before := time.Now().Add(-time.Minute).Unix()
+	err = f.Futimens(cephfs.Timespec{Nsec: cephfs.UtimeNow}, omit)
+	assert.NoError(t, err)
+
+	stx, err = f.Fstatx(cephfs.StatxAtime|cephfs.StatxMtime, 0)
+	assert.NoError(t, err)
+	assert.True(t, stx.Atime.Sec >= before)
+	assert.Equal(t, stx.Mtime, newMtime)
+
+	err = f.Close()
+	assert.NoError(t, err)
+
+	err = mount.Unlink("/utimes.link")
+	assert.NoError(t, err)
+	err = mount.Unlink("/utimes")
+	assert.NoError(t, err)
+}
+
+func TestFallocate(t *testing.T) {
+	mount := fsConnect(t)
+	defer fsDisconnect(t, mount)
+
+	f, err := mount.Open("/fallocate", os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	assert.NoError(t, err)
+
+	err = f.Fallocate(0, 0, 1<<20)
+	assert.NoError(t, err)
+
+	stx, err := f.Fstatx(cephfs.StatxSize, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, stx.Size, uint64(1<<20))
+
+	err = f.Fallocate(cephfs.FallocKeepSize, 1<<20, 1<<20)
+	assert.NoError(t, err)
+
+	stx, err = f.Fstatx(cephfs.StatxSize, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, stx.Size, uint64(1<<20))
+
+	_, err = f.WriteAt([]byte("data"), 100)
+	assert.NoError(t, err)
+
+	err = f.Fallocate(cephfs.FallocPunchHole|cephfs.FallocKeepSize, 0, 4096)
+	assert.NoError(t, err)
+
+	buf := make([]byte, 4)
+	_, err = f.ReadAt(buf, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, buf, make([]byte, 4))
+
+	err = f.Fallocate(cephfs.FallocPunchHole, 0, 4096)
+	assert.Error(t, err)
+
+	err = f.Close()
+	assert.NoError(t, err)
+
+	err = mount.Unlink("/fallocate")
+	assert.NoError(t, err)
+}
+
+func TestFlock(t *testing.T) {
+	mount := fsConnect(t)
+	defer fsDisconnect(t, mount)
+
+	f1, err := mount.Open("/flock", os.O_RDWR|os.O_CREATE, 0644)
+	assert.NoError(t, err)
+	f2, err := mount.Open("/flock", os.O_RDWR, 0)
+	assert.NoError(t, err)
+
+	err = f1.Flock(cephfs.LockEx|cephfs.LockNb, 1)
+	assert.NoError(t, err)
+
+	err = f2.Flock(cephfs.LockSh|cephfs.LockNb, 2)
+	assert.Equal(t, err, cephfs.CephError(-int(syscall.EWOULDBLOCK)))
+
+	err = f1.Flock(cephfs.LockUn, 1)
+	assert.NoError(t, err)
+
+	err = f2.Flock(cephfs.LockSh|cephfs.LockNb, 2)
+	assert.NoError(t, err)
+	err = f1.Flock(cephfs.LockSh|cephfs.LockNb, 1)
+	assert.NoError(t, err)
+
+	err = f1.Flock(cephfs.LockUn, 1)
+	assert.NoError(t, err)
+	err = f2.Flock(cephfs.LockUn, 2)
+	assert.NoError(t, err)
+
+	err = f1.Close()
+	assert.NoError(t, err)
+	err = f2.Close()
+	assert.NoError(t, err)
+
+	err = mount.Unlink("/flock")
+	assert.NoError(t, err)
+}
+
+func TestFsync(t *testing.T) {
+	mount := fsConnect(t)
+	defer fsDisconnect(t, mount)
+
+	f, err := mount.Open("/fsync", os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	assert.NoError(t, err)
+
+	_, err = f.Write([]byte("durable"))
+	assert.NoError(t, err)
+
+	err = f.Fsync(true)
+	assert.NoError(t, err)
+
+	err = f.Fsync(false)
+	assert.NoError(t, err)
+
+	err = f.Close()
+	assert.NoError(t, err)
+
+	err = f.Fsync(false)
+	assert.Error(t, err)
+
+	err = mount.SyncFs()
+	assert.NoError(t, err)
+
+	err = mount.Unlink("/fsync")
+	assert.NoError(t, err)
+}
+
+func TestStatfs(t *testing.T) {
+	mount := fsConnect(t)
+	defer fsDisconnect(t, mount)
+
+	stat, err := mount.Statfs("/")
+	assert.NoError(t, err)
+	assert.True(t, stat.Bsize > 0)
+	assert.True(t, stat.Frsize > 0)
+	assert.True(t, stat.Blocks > 0)
+	assert.True(t, stat.Bfree <= stat.Blocks)
+	assert.True(t, stat.Namemax > 0)
+
+	_, err = mount.Statfs("/no-such-dir")
+	assert.Error(t, err)
+}
+
+func TestXattrs(t *testing.T) {
+	mount := fsConnect(t)
+	defer fsDisconnect(t, mount)
+
+	writeFile(t, mount, "/xattrs", []byte("attributes"))
+
+	err := mount.SetXattr("/xattrs", "user.color", []byte("blue"), cephfs.XattrDefault)
+	assert.NoError(t, err)
+
+	value, err := mount.GetXattr("/xattrs", "user.color")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("blue"), value)
+
+	err = mount.SetXattr("/xattrs", "user.color", []byte("red"), cephfs.XattrCreate)
+	assert.Error(t, err)
+
+	err = mount.SetXattr("/xattrs", "user.shape", []byte("round"), cephfs.XattrReplace)
+	assert.Error(t, err)
+
+	names, err := mount.ListXattr("/xattrs")
+	assert.NoError(t, err)
+	assert.Contains(t, names, "user.color")
+
+	f, err := mount.Open("/xattrs", os.O_RDWR, 0)
+	assert.NoError(t, err)
+
+	err = f.SetXattr("user.shape", []byte("square"), cephfs.XattrCreate)
+	assert.NoError(t, err)
+
+	value, err = f.GetXattr("user.shape")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("square"), value)
+
+	names, err = f.ListXattr()
+	assert.NoError(t, err)
+	assert.Contains(t, names, "user.shape")
+
+	err = f.RemoveXattr("user.shape")
+	assert.NoError(t, err)
+
+	err = f.Close()
+	assert.NoError(t, err)
+
+	err = mount.RemoveXattr("/xattrs", "user.color")
+	assert.NoError(t, err)
+
+	_, err = mount.GetXattr("/xattrs", "user.color")
+	assert.Error(t, err)
+
+	// virtual xattrs are readable but not listed
+	layout, err := mount.GetXattr("/xattrs", "ceph.file.layout")
+	assert.NoError(t, err)
+	assert.Contains(t, string(layout), "stripe_unit")
+
+	entries, err := mount.GetXattr("/", "ceph.dir.rentries")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, entries)
+
+	err = mount.Symlink("/xattrs", "/xattrs-link")
+	assert.NoError(t, err)
+
+	_, err = mount.LGetXattr("/xattrs-link", "user.color")
+	assert.Error(t, err)
+
+	names, err = mount.LListXattr("/xattrs-link")
+	assert.NoError(t, err)
+	assert.NotContains(t, names, "user.color")
+
+	err = mount.Unlink("/xattrs-link")
+	assert.NoError(t, err)
+	err = mount.Unlink("/xattrs")
+	assert.NoError(t, err)
+}
+
+func TestQuota(t *testing.T) {
+	mount := fsConnect(t)
+	defer fsDisconnect(t, mount)
+
+	err := mount.MakeDir("/quota", 0755)
+	assert.NoError(t, err)
+
+	quota, err := mount.GetQuota("/quota")
+	assert.NoError(t, err)
+	assert.Equal(t, &cephfs.Quota{}, quota)
+
+	err = mount.SetQuota("/quota", &cephfs.Quota{
+		MaxBytes: 1 << 30,
+		MaxFiles: 1000,
+	})
+	assert.NoError(t, err)
+
+	quota, err = mount.GetQuota("/quota")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1<<30), quota.MaxBytes)
+	assert.Equal(t, uint64(1000), quota.MaxFiles)
+
+	writeFile(t, mount, "/quota/file", []byte("quota"))
+
+	usage, err := mount.GetQuotaUsage("/quota")
+	assert.NoError(t, err)
+	assert.True(t, usage.Files <= 1)
+
+	err = mount.SetQuota("/quota", &cephfs.Quota{})
+	assert.NoError(t, err)
+
+	quota, err = mount.GetQuota("/quota")
+	assert.NoError(t, err)
+	assert.Equal(t, &cephfs.Quota{}, quota)
+
+	err = mount.Unlink("/quota/file")
+	assert.NoError(t, err)
+	err = mount.RemoveDir("/quota")
+	assert.NoError(t, err)
+}
+
+func TestSnapshots(t *testing.T) {
+	mount := fsConnect(t)
+	defer fsDisconnect(t, mount)
+
+	err := mount.MakeDir("/snapped", 0755)
+	assert.NoError(t, err)
+	writeFile(t, mount, "/snapped/file", []byte("before"))
+
+	err = mount.CreateSnapshot("/snapped", "snap1")
+	assert.NoError(t, err)
+
+	writeFile(t, mount, "/snapped/file", []byte("after"))
+
+	data := readFile(t, mount, "/snapped/.snap/snap1/file")
+	assert.Equal(t, []byte("before"), data)
+
+	snaps, err := mount.ListSnapshots("/snapped")
+	assert.NoError(t, err)
+	assert.Len(t, snaps, 1)
+	assert.Equal(t, "snap1", snaps[0].Name)
+	assert.True(t, snaps[0].Ctime.Sec > 0)
+
+	err = mount.CreateSnapshot("/snapped", "snap1")
+	assert.Error(t, err)
+
+	err = mount.RemoveSnapshot("/snapped", "snap1")
+	assert.NoError(t, err)
+
+	snaps, err = mount.ListSnapshots("/snapped")
+	assert.NoError(t, err)
+	assert.Len(t, snaps, 0)
+
+	err = mount.Unlink("/snapped/file")
+	assert.NoError(t, err)
+	err = mount.RemoveDir("/snapped")
+	assert.NoError(t, err)
+}
+
+func TestLayout(t *testing.T) {
+	mount := fsConnect(t)
+	defer fsDisconnect(t, mount)
+
+	err := mount.MakeDir("/layout", 0755)
+	assert.NoError(t, err)
+
+	layout, err := mount.GetDirLayout("/layout")
+	assert.NoError(t, err)
+	assert.Equal(t, &cephfs.Layout{}, layout)
+
+	err = mount.SetDirLayout("/layout", &cephfs.Layout{
+		StripeUnit:  1 << 20,
+		StripeCount: 2,
+		ObjectSize:  8 << 20,
+	})
+	assert.NoError(t, err)
+
+	layout, err = mount.GetDirLayout("/layout")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1<<20), layout.StripeUnit)
+	assert.Equal(t, uint64(2), layout.StripeCount)
+	assert.Equal(t, uint64(8<<20), layout.ObjectSize)
+	assert.NotEmpty(t, layout.Pool)
+
+	writeFile(t, mount, "/layout/inherited", []byte{})
+
+	fileLayout, err := mount.GetFileLayout("/layout/inherited")
+	assert.NoError(t, err)
+	assert.Equal(t, layout, fileLayout)
+
+	err = mount.SetFileLayout("/layout/inherited", &cephfs.Layout{
+		StripeCount: 1,
+	})
+	assert.NoError(t, err)
+
+	fileLayout, err = mount.GetFileLayout("/layout/inherited")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), fileLayout.StripeCount)
+
+	writeFile(t, mount, "/layout/inherited", []byte("data"))
+
+	err = mount.SetFileLayout("/layout/inherited", &cephfs.Layout{
+		StripeCount: 4,
+	})
+	assert.Error(t, err)
+
+	err = mount.RemoveDirLayout("/layout")
+	assert.NoError(t, err)
+
+	layout, err = mount.GetDirLayout("/layout")
+	assert.NoError(t, err)
+	assert.Equal(t, &cephfs.Layout{}, layout)
+
+	err = mount.Unlink("/layout/inherited")
+	assert.NoError(t, err)
+	err = mount.RemoveDir("/layout")
+	assert.NoError(t, err)
+}
+
+func TestExtentOsds(t *testing.T) {
+	mount := fsConnect(t)
+	defer fsDisconnect(t, mount)
+
+	writeFile(t, mount, "/located", []byte("somewhere"))
+
+	f, err := mount.Open("/located", os.O_RDONLY, 0)
+	assert.NoError(t, err)
+
+	stripeUnit, err := f.GetStripeUnit()
+	assert.NoError(t, err)
+	assert.True(t, stripeUnit > 0)
+
+	osds, length, err := f.GetExtentOsds(0)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, osds)
+	assert.True(t, length > 0)
+
+	addr, err := mount.GetOsdAddr(osds[0])
+	assert.NoError(t, err)
+	assert.NotNil(t, addr.IP)
+	assert.True(t, addr.Port > 0)
+
+	_, err = mount.GetOsdAddr(-1)
+	assert.Error(t, err)
+
+	err = f.Close()
+	assert.NoError(t, err)
+
+	err = mount.Unlink("/located")
+	assert.NoError(t, err)
+}
+
+func TestMdsCommand(t *testing.T) {
+	mount := fsConnect(t)
+	defer fsDisconnect(t, mount)
+
+	cmd, err := json.Marshal(map[string]string{"prefix": "session ls", "format": "json"})
+	assert.NoError(t, err)
+
+	buf, _, err := mount.MdsCommand("*", cmd)
+	assert.NoError(t, err)
+
+	var sessions []map[string]interface{}
+	err = json.Unmarshal(buf, &sessions)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, sessions)
+
+	cmd, err = json.Marshal(map[string]string{"prefix": "no such command"})
+	assert.NoError(t, err)
+
+	_, _, err = mount.MdsCommand("*", cmd)
+	assert.Error(t, err)
+}
+
+func TestRelativePaths(t *testing.T) {
+	mount := fsConnect(t)
+	defer fsDisconnect(t, mount)
+
+	err := mount.MakeDir("/relative", 0755)
+	assert.NoError(t, err)
+
+	err = mount.ChangeDir("/relative")
+	assert.NoError(t, err)
+	assert.Equal(t, "/relative", mount.CurrentDir())
+
+	writeFile(t, mount, "file", []byte("relative"))
+	assert.Equal(t, []byte("relative"), readFile(t, mount, "/relative/file"))
+
+	err = mount.ChangeDir("..")
+	assert.NoError(t, err)
+	assert.Equal(t, "/", mount.CurrentDir())
+
+	assert.Equal(t, []byte("relative"), readFile(t, mount, "relative/file"))
+
+	err = mount.Unlink("relative/file")
+	assert.NoError(t, err)
+	err = mount.RemoveDir("relative")
+	assert.NoError(t, err)
+}
+
+func TestMknod(t *testing.T) {
+	mount := fsConnect(t)
+	defer fsDisconnect(t, mount)
+
+	err := mount.Mknod("/fifo", syscall.S_IFIFO|0644, 0)
+	assert.NoError(t, err)
+
+	stx, err := mount.Statx("/fifo", cephfs.StatxMode, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(syscall.S_IFIFO|0644), stx.Mode)
+
+	err = mount.Mknod("/fifo", syscall.S_IFIFO|0644, 0)
+	assert.Error(t, err)
+
+	err = mount.Unlink("/fifo")
+	assert.NoError(t, err)
+}
+
+func TestUserPerm(t *testing.T) {
+	mount := fsConnect(t)
+	defer fsDisconnect(t, mount)
+
+	err := mount.MakeDir("/perms", 0755)
+	assert.NoError(t, err)
+	err = mount.Chown("/perms", 0, 0)
+	assert.NoError(t, err)
+
+	perm := cephfs.NewUserPerm(1000, 1000, []int{1001, 1002})
+	defer perm.Destroy()
+
+	userMount, err := cephfs.CreateMount()
+	assert.NoError(t, err)
+	err = userMount.ReadDefaultConfigFile()
+	assert.NoError(t, err)
+	err = userMount.SetMountPerms(perm)
+	assert.NoError(t, err)
+	err = userMount.Mount()
+	assert.NoError(t, err)
+	defer fsDisconnect(t, userMount)
+
+	assert.NotNil(t, userMount.MountPerms())
+
+	_, err = userMount.Open("/perms/denied", os.O_WRONLY|os.O_CREATE, 0644)
+	assert.Equal(t, cephfs.CephError(-int(syscall.EACCES)), err)
+
+	err = mount.Chown("/perms", 1000, 1000)
+	assert.NoError(t, err)
+
+	f, err := userMount.Open("/perms/allowed", os.O_WRONLY|os.O_CREATE, 0644)
+	assert.NoError(t, err)
+	err = f.Close()
+	assert.NoError(t, err)
+
+	stx, err := mount.Statx("/perms/allowed", cephfs.StatxUid|cephfs.StatxGid, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(1000), stx.Uid)
+	assert.Equal(t, uint32(1000), stx.Gid)
+
+	err = mount.Unlink("/perms/allowed")
+	assert.NoError(t, err)
+	err = mount.RemoveDir("/perms")
+	assert.NoError(t, err)
+}
+
+func TestInodes(t *testing.T) {
+	mount := fsConnect(t)
+	defer fsDisconnect(t, mount)
+
+	err := mount.MakeDir("/inodes", 0755)
+	assert.NoError(t, err)
+	writeFile(t, mount, "/inodes/file", []byte("by handle"))
+
+	root, err := mount.LookupRoot()
+	assert.NoError(t, err)
+	defer root.Put()
+
+	dir, stx, err := root.Lookup("inodes", cephfs.StatxMode, 0, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(syscall.S_IFDIR), stx.Mode&syscall.S_IFMT)
+	defer dir.Put()
+
+	_, _, err = root.Lookup("no-such-entry", cephfs.StatxMode, 0, nil)
+	assert.Error(t, err)
+
+	d, err := dir.OpenDir(nil)
+	assert.NoError(t, err)
+
+	var file *cephfs.Inode
+	for {
+		entry, in, err := d.ReadDirInode(cephfs.StatxSize, 0)
+		assert.NoError(t, err)
+		if entry == nil {
+			break
+		}
+		if entry.Name == "file" {
+			assert.Equal(t, uint64(9), entry.Statx.Size)
+			file = in
+		} else {
+			in.Put()
+		}
+	}
+	err = d.Close()
+	assert.NoError(t, err)
+	assert.NotNil(t, file)
+	defer file.Put()
+
+	stx, err = file.Getattr(cephfs.StatxSize, 0, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(9), stx.Size)
+
+	fh, err := file.Open(os.O_RDWR, nil)
+	assert.NoError(t, err)
+
+	n, err := fh.WriteAt([]byte("HANDLE"), 3)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, n)
+
+	buf := make([]byte, 16)
+	n, err = fh.ReadAt(buf, 0)
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, "by HANDLE", string(buf[:n]))
+
+	lock := &cephfs.PosixLock{Type: cephfs.PosixLockWrite, Start: 0, Len: 4}
+	err = fh.Setlk(lock, 1, false)
+	assert.NoError(t, err)
+
+	conflict, err := fh.Getlk(&cephfs.PosixLock{Type: cephfs.PosixLockRead}, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, cephfs.PosixLockWrite, conflict.Type)
+
+	err = fh.Setlk(&cephfs.PosixLock{Type: cephfs.PosixLockRead}, 2, false)
+	assert.Error(t, err)
+
+	lock.Type = cephfs.PosixLockUnlock
+	err = fh.Setlk(lock, 1, false)
+	assert.NoError(t, err)
+
+	conflict, err = fh.Getlk(&cephfs.PosixLock{Type: cephfs.PosixLockRead}, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, cephfs.PosixLockUnlock, conflict.Type)
+
+	err = fh.Close()
+	assert.NoError(t, err)
+	err = fh.Close()
+	assert.Error(t, err)
+
+	err = mount.Unlink("/inodes/file")
+	assert.NoError(t, err)
+	err = mount.RemoveDir("/inodes")
+	assert.NoError(t, err)
+}
+
+func TestLazyIO(t *testing.T) {
+	mount := fsConnect(t)
+	defer fsDisconnect(t, mount)
+
+	f, err := mount.Open("/lazyio", os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	assert.NoError(t, err)
+
+	err = f.LazyIO(true)
+	assert.NoError(t, err)
+
+	_, err = f.Write([]byte("lazy"))
+	assert.NoError(t, err)
+
+	err = f.LazyIOPropagate(0, 0)
+	assert.NoError(t, err)
+	err = f.LazyIOSynchronize(0, 0)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []byte("lazy"), readFile(t, mount, "/lazyio"))
+
+	err = f.LazyIO(false)
+	assert.NoError(t, err)
+
+	err = f.Close()
+	assert.NoError(t, err)
+
+	err = f.LazyIO(true)
+	assert.Error(t, err)
+
+	err = mount.Unlink("/lazyio")
+	assert.NoError(t, err)
+}
+
+func TestAsyncIO(t *testing.T) {
+	mount := fsConnect(t)
+	defer fsDisconnect(t, mount)
+
+	writeFile(t, mount, "/async", []byte{})
+
+	root, err := mount.LookupRoot()
+	assert.NoError(t, err)
+	defer root.Put()
+
+	in, _, err := root.Lookup("async", cephfs.StatxIno, 0, nil)
+	assert.NoError(t, err)
+	defer in.Put()
+
+	fh, err := in.Open(os.O_RDWR, nil)
+	assert.NoError(t, err)
+
+	writes := []*cephfs.AsyncIO{}
+	for i := 0; i < 8; i++ {
+		data := [][]byte{[]byte(strings.Repeat(string(rune('a'+i)), 4))}
+		aio, err := fh.PwritevAsync(data, int64(i*4))
+		assert.NoError(t, err)
+		writes = append(writes, aio)
+	}
+	for _, aio := range writes {
+		n, err := aio.Wait()
+		assert.NoError(t, err)
+		assert.Equal(t, 4, n)
+	}
+
+	head := make([]byte, 8)
+	tail := make([]byte, 24)
+	aio, err := fh.PreadvAsync([][]byte{head, tail}, 0)
+	assert.NoError(t, err)
+
+	select {
+	case <-aio.Done():
+	case <-time.After(10 * time.Second):
+		t.Fatal("read did not complete")
+	}
+	n, err := aio.Wait()
+	assert.NoError(t, err)
+	assert.Equal(t, 32, n)
+	assert.Equal(t, "aaaabbbb", string(head))
+	assert.Equal(t, "ccccddddeeeeffffgggghhhh", string(tail))
+
+	aio, err = fh.PreadvAsync([][]byte{head}, 32)
+	assert.NoError(t, err)
+	_, err = aio.Wait()
+	assert.Equal(t, io.EOF, err)
+
+	err = fh.Close()
+	assert.NoError(t, err)
+
+	_, err = fh.PreadvAsync([][]byte{head}, 0)
+	assert.Error(t, err)
+
+	err = mount.Unlink("/async")
+	assert.NoError(t, err)
+}
+
+func TestSessions(t *testing.T) {
+	mount, err := cephfs.CreateMount()
+	assert.NoError(t, err)
+	err = mount.ReadDefaultConfigFile()
+	assert.NoError(t, err)
+	err = mount.SetSessionTimeout(120 * time.Second)
+	assert.NoError(t, err)
+	err = mount.SetClientMetadata(map[string]string{"app": "go-ceph-test"})
+	assert.NoError(t, err)
+	err = mount.Mount()
+	assert.NoError(t, err)
+	defer fsDisconnect(t, mount)
+
+	err = mount.SetSessionTimeout(60 * time.Second)
+	assert.Error(t, err)
+
+	assert.NotZero(t, mount.InstanceId())
+
+	sessions, err := mount.ListSessions("*")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, sessions)
+
+	own, err := mount.OwnSessions("*")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, own)
+	for _, session := range own {
+		assert.Equal(t, mount.InstanceId(), session.Id)
+		assert.Equal(t, "go-ceph-test", session.ClientMetadata["app"])
+	}
+}
+
+func TestSeekHoleData(t *testing.T) {
+	mount := fsConnect(t)
+	defer fsDisconnect(t, mount)
+
+	f, err := mount.Open("/sparse", os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	assert.NoError(t, err)
+
+	_, err = f.WriteAt([]byte("data"), 16<<20)
+	assert.NoError(t, err)
+
+	// libcephfs may report the hole as data, but never data as a hole
+	offset, err := f.Seek(0, cephfs.SeekData)
+	assert.NoError(t, err)
+	assert.True(t, offset <= 16<<20)
+
+	offset, err = f.Seek(16<<20, cephfs.SeekHole)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(16<<20+4), offset)
+
+	_, err = f.Seek(32<<20, cephfs.SeekData)
+	assert.Equal(t, cephfs.CephError(-int(syscall.ENXIO)), err)
+
+	err = f.Close()
+	assert.NoError(t, err)
+
+	err = mount.Unlink("/sparse")
+	assert.NoError(t, err)
+}
+
+func TestFS(t *testing.T) {
+	mount := fsConnect(t)
+	defer fsDisconnect(t, mount)
+
+	err := mount.MakeDirs("/fstree/a/b", 0755)
+	assert.NoError(t, err)
+	writeFile(t, mount, "/fstree/top", []byte("top"))
+	writeFile(t, mount, "/fstree/a/b/leaf", []byte("leaf"))
+
+	fsys := cephfs.NewFS(mount, "/fstree")
+
+	err = fstest.TestFS(fsys, "top", "a/b/leaf")
+	assert.NoError(t, err)
+
+	data, err := fs.ReadFile(fsys, "a/b/leaf")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("leaf"), data)
+
+	entries, err := fs.ReadDir(fsys, ".")
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "a", entries[0].Name())
+	assert.True(t, entries[0].IsDir())
+	assert.Equal(t, "top", entries[1].Name())
+
+	walked := []string{}
+	err = fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		walked = append(walked, p)
+		return err
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{".", "a", "a/b", "a/b/leaf", "top"}, walked)
+
+	info, err := fs.Stat(fsys, "top")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), info.Size())
+	assert.True(t, info.Mode().IsRegular())
+
+	_, err = fsys.Open("missing")
+	assert.True(t, errors.Is(err, fs.ErrNotExist))
+
+	_, err = fsys.Open("../escape")
+	assert.True(t, errors.Is(err, fs.ErrInvalid))
+
+	err = mount.Unlink("/fstree/a/b/leaf")
+	assert.NoError(t, err)
+	err = mount.Unlink("/fstree/top")
+	assert.NoError(t, err)
+	err = mount.RemoveDir("/fstree/a/b")
+	assert.NoError(t, err)
+	err = mount.RemoveDir("/fstree/a")
+	assert.NoError(t, err)
+	err = mount.RemoveDir("/fstree")
+	assert.NoError(t, err)
+}
+
+// osFile is the subset of *os.File that File provides.
+type osFile interface {
+	io.ReadWriteSeeker
+	io.Closer
+	Name() string
+	Stat() (fs.FileInfo, error)
+	Truncate(size int64) error
+	Sync() error
+}
+
+func TestOSFile(t *testing.T) {
+	mount := fsConnect(t)
+	defer fsDisconnect(t, mount)
+
+	cf, err := mount.Open("/osfile", os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0640)
+	assert.NoError(t, err)
+
+	var f osFile = cf
+	assert.Equal(t, "/osfile", f.Name())
+
+	_, err = f.Write([]byte("compatible"))
+	assert.NoError(t, err)
+
+	err = f.Sync()
+	assert.NoError(t, err)
+
+	err = f.Truncate(6)
+	assert.NoError(t, err)
+
+	info, err := f.Stat()
+	assert.NoError(t, err)
+	assert.Equal(t, "osfile", info.Name())
+	assert.Equal(t, int64(6), info.Size())
+	assert.Equal(t, fs.FileMode(0640), info.Mode())
+	assert.False(t, info.IsDir())
+	assert.IsType(t, &cephfs.CephStatx{}, info.Sys())
+
+	_, err = f.Seek(0, io.SeekStart)
+	assert.NoError(t, err)
+	data, err := io.ReadAll(f)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("compat"), data)
+
+	err = f.Close()
+	assert.NoError(t, err)
+
+	err = mount.Unlink("/osfile")
+	assert.NoError(t, err)
+}
+
+func TestConfigOptionsMounted(t *testing.T) {
+	mount, err := cephfs.CreateMount()
+	assert.NoError(t, err)
+	err = mount.ReadDefaultConfigFile()
+	assert.NoError(t, err)
+	err = mount.SetConfigOption("client_oc", "false")
+	assert.NoError(t, err)
+	err = mount.Mount()
+	assert.NoError(t, err)
+	defer fsDisconnect(t, mount)
+
+	value, err := mount.GetConfigOption("client_oc")
+	assert.NoError(t, err)
+	assert.Equal(t, "false", value)
+
+	err = mount.SetConfigOption("client_readahead_max_bytes", "4194304")
+	assert.NoError(t, err)
+
+	value, err = mount.GetConfigOption("client_readahead_max_bytes")
+	assert.NoError(t, err)
+	assert.Equal(t, "4194304", value)
+
+	// options of other mounts are not affected
+	other := fsConnect(t)
+	defer fsDisconnect(t, other)
+
+	value, err = other.GetConfigOption("client_readahead_max_bytes")
+	assert.NoError(t, err)
+	assert.NotEqual(t, "4194304", value)
+}
+
+func TestSnapDiff(t *testing.T) {
+	mount := fsConnect(t)
+	defer fsDisconnect(t, mount)
+
+	err := mount.MakeDirs("/snapdiff/same/deep", 0755)
+	assert.NoError(t, err)
+	err = mount.MakeDir("/snapdiff/gone", 0755)
+	assert.NoError(t, err)
+	writeFile(t, mount, "/snapdiff/same/deep/kept", []byte("kept"))
+	writeFile(t, mount, "/snapdiff/changed", []byte("v1"))
+	writeFile(t, mount, "/snapdiff/gone/file", []byte("gone"))
+
+	err = mount.CreateSnapshot("/snapdiff", "s1")
+	assert.NoError(t, err)
+
+	writeFile(t, mount, "/snapdiff/changed", []byte("v2"))
+	err = mount.Unlink("/snapdiff/gone/file")
+	assert.NoError(t, err)
+	err = mount.RemoveDir("/snapdiff/gone")
+	assert.NoError(t, err)
+	err = mount.MakeDir("/snapdiff/new", 0755)
+	assert.NoError(t, err)
+	writeFile(t, mount, "/snapdiff/new/file", []byte("new"))
+
+	err = mount.CreateSnapshot("/snapdiff", "s2")
+	assert.NoError(t, err)
+
+	var diff []cephfs.SnapDiffEntry
+	collect := func(entry *cephfs.SnapDiffEntry) error {
+		diff = append(diff, *entry)
+		return nil
+	}
+
+	err = mount.SnapDiff("/snapdiff", "s1", "s2", collect)
+	assert.NoError(t, err)
+	assert.Equal(t, []cephfs.SnapDiffEntry{
+		{Path: "gone", Type: cephfs.SnapDiffDeleted, IsDir: true},
+		{Path: "changed", Type: cephfs.SnapDiffModified},
+		{Path: "new", Type: cephfs.SnapDiffCreated, IsDir: true},
+		{Path: "new/file", Type: cephfs.SnapDiffCreated},
+	}, diff)
+
+	writeFile(t, mount, "/snapdiff/live", []byte("live"))
+
+	diff = nil
+	err = mount.SnapDiff("/snapdiff", "s2", "", collect)
+	assert.NoError(t, err)
+	assert.Equal(t, []cephfs.SnapDiffEntry{
+		{Path: "live", Type: cephfs.SnapDiffCreated},
+	}, diff)
+
+	stop := errors.New("stop")
+	err = mount.SnapDiff("/snapdiff", "s1", "s2", func(*cephfs.SnapDiffEntry) error {
+		return stop
+	})
+	assert.Equal(t, stop, err)
+
+	err = mount.RemoveSnapshot("/snapdiff", "s2")
+	assert.NoError(t, err)
+	err = mount.RemoveSnapshot("/snapdiff", "s1")
+	assert.NoError(t, err)
+	for _, file := range []string{"live", "changed", "new/file", "same/deep/kept"} {
+		err = mount.Unlink("/snapdiff/" + file)
+		assert.NoError(t, err)
+	}
+	for _, dir := range []string{"new", "same/deep", "same", ""} {
+		err = mount.RemoveDir("/snapdiff/" + dir)
+		assert.NoError(t, err)
+	}
+}
+
+func TestPinning(t *testing.T) {
+	mount := fsConnect(t)
+	defer fsDisconnect(t, mount)
+
+	err := mount.MakeDir("/pinned", 0755)
+	assert.NoError(t, err)
+
+	rank, err := mount.GetExportPin("/pinned")
+	assert.NoError(t, err)
+	assert.Equal(t, cephfs.PinNone, rank)
+
+	err = mount.SetExportPin("/pinned", 0)
+	assert.NoError(t, err)
+	rank, err = mount.GetExportPin("/pinned")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, rank)
+
+	err = mount.SetExportPin("/pinned", cephfs.PinNone)
+	assert.NoError(t, err)
+
+	err = mount.SetDistributedPin("/pinned", true)
+	assert.NoError(t, err)
+	distributed, err := mount.GetDistributedPin("/pinned")
+	assert.NoError(t, err)
+	assert.True(t, distributed)
+
+	err = mount.SetDistributedPin("/pinned", false)
+	assert.NoError(t, err)
+	distributed, err = mount.GetDistributedPin("/pinned")
+	assert.NoError(t, err)
+	assert.False(t, distributed)
+
+	err = mount.SetRandomPin("/pinned", 0.005)
+	assert.NoError(t, err)
+	probability, err := mount.GetRandomPin("/pinned")
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.005, probability, 0.0001)
+
+	err = mount.SetRandomPin("/pinned", 0)
+	assert.NoError(t, err)
+
+	err = mount.RemoveDir("/pinned")
+	assert.NoError(t, err)
+}
+
+func TestDirStats(t *testing.T) {
+	mount := fsConnect(t)
+	defer fsDisconnect(t, mount)
+
+	err := mount.MakeDirs("/rstats/sub", 0755)
+	assert.NoError(t, err)
+	writeFile(t, mount, "/rstats/top", []byte("12345"))
+	writeFile(t, mount, "/rstats/sub/leaf", []byte("1234567890"))
+
+	// flush the capabilities so that the MDS has the statistics
+	err = mount.SyncFs()
+	assert.NoError(t, err)
+
+	stats, err := mount.GetDirStats("/rstats")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), stats.Files)
+	assert.Equal(t, uint64(1), stats.Subdirs)
+	assert.True(t, stats.RFiles <= 2)
+	assert.True(t, stats.RBytes <= 15)
+	assert.True(t, stats.RCtime.Sec > 0)
+
+	_, err = mount.GetDirStats("/no-such-dir")
+	assert.Error(t, err)
+
+	err = mount.Unlink("/rstats/sub/leaf")
+	assert.NoError(t, err)
+	err = mount.Unlink("/rstats/top")
+	assert.NoError(t, err)
+	err = mount.RemoveDir("/rstats/sub")
+	assert.NoError(t, err)
+	err = mount.RemoveDir("/rstats")
+	assert.NoError(t, err)
+}
+
+func TestCopyFileRange(t *testing.T) {
+	mount := fsConnect(t)
+	defer fsDisconnect(t, mount)
+
+	data := []byte(strings.Repeat("0123456789", 1<<20))
+	writeFile(t, mount, "/copy-src", data)
+
+	src, err := mount.Open("/copy-src", os.O_RDONLY, 0)
+	assert.NoError(t, err)
+	dst, err := mount.Open("/copy-dst", os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	assert.NoError(t, err)
+
+	n, err := src.CopyFileRange(0, dst, 0, int64(len(data)))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(data)), n)
+
+	n, err = src.CopyFileRange(5, dst, int64(len(data)), 100)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100), n)
+
+	n, err = src.CopyFileRange(int64(len(data))-3, dst, 0, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+
+	// a failed write is reported, along with the bytes written before it
+	n, err = src.CopyFileRange(0, src, 0, 100)
+	assert.Error(t, err)
+	assert.Equal(t, int64(0), n)
+
+	err = src.Close()
+	assert.NoError(t, err)
+	err = dst.Close()
+	assert.NoError(t, err)
+
+	copied := readFile(t, mount, "/copy-dst")
+	assert.Equal(t, len(data)+100, len(copied))
+	assert.Equal(t, []byte("789"), copied[:3])
+	assert.Equal(t, data[3:], copied[3:len(data)])
+	assert.Equal(t, data[5:105], copied[len(data):])
+
+	err = mount.Unlink("/copy-src")
+	assert.NoError(t, err)
+	err = mount.Unlink("/copy-dst")
+	assert.NoError(t, err)
+}
+
+func TestWalkDir(t *testing.T) {
+	mount := fsConnect(t)
+	defer fsDisconnect(t, mount)
+
+	err := mount.MakeDirs("/walk/a/skip", 0755)
+	assert.NoError(t, err)
+	err = mount.MakeDirs("/walk/b", 0755)
+	assert.NoError(t, err)
+	writeFile(t, mount, "/walk/a/file", []byte("a"))
+	writeFile(t, mount, "/walk/a/skip/hidden", []byte("hidden"))
+	writeFile(t, mount, "/walk/b/file", []byte("b"))
+
+	walked := []string{}
+	err = mount.WalkDir("/walk", func(p string, d fs.DirEntry, err error) error {
+		assert.NoError(t, err)
+		walked = append(walked, p)
+		if d.Name() == "skip" {
+			return fs.SkipDir
+		}
+		if !d.IsDir() {
+			info, err := d.Info()
+			assert.NoError(t, err)
+			assert.Equal(t, int64(1), info.Size())
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/walk", "/walk/a", "/walk/a/file",
+		"/walk/a/skip", "/walk/b", "/walk/b/file"}, walked)
+
+	var mutex sync.Mutex
+	walked = []string{}
+	opts := &cephfs.WalkDirOptions{Concurrency: 4}
+	err = mount.WalkDirWithOptions("/walk", opts, func(p string, d fs.DirEntry, err error) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		walked = append(walked, p)
+		return err
+	})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"/walk", "/walk/a", "/walk/a/file",
+		"/walk/a/skip", "/walk/a/skip/hidden", "/walk/b", "/walk/b/file"}, walked)
+
+	stop := errors.New("stop")
+	err = mount.WalkDirWithOptions("/walk", opts, func(p string, d fs.DirEntry, err error) error {
+		if p == "/walk/b" {
+			return stop
+		}
+		return nil
+	})
+	assert.Equal(t, stop, err)
+
+	err = mount.WalkDir("/no-such-dir", func(p string, d fs.DirEntry, err error) error {
+		assert.Nil(t, d)
+		return err
+	})
+	assert.Error(t, err)
+
+	for _, file := range []string{"a/file", "a/skip/hidden", "b/file"} {
+		err = mount.Unlink("/walk/" + file)
+		assert.NoError(t, err)
+	}
+	for _, dir := range []string{"a/skip", "a", "b", ""} {
+		err = mount.RemoveDir("/walk/" + dir)
+		assert.NoError(t, err)
+	}
+}
+
+func TestMountWithTimeout(t *testing.T) {
+	mount, err := cephfs.CreateMount()
+	assert.NoError(t, err)
+	err = mount.ReadDefaultConfigFile()
+	assert.NoError(t, err)
+	err = mount.MountWithTimeout(30 * time.Second)
+	assert.NoError(t, err)
+	fsDisconnect(t, mount)
+
+	// nothing listens on the discard port
+	mount, err = cephfs.CreateMount()
+	assert.NoError(t, err)
+	err = mount.SetConfigOption("mon_host", "127.0.0.1:9")
+	assert.NoError(t, err)
+
+	start := time.Now()
+	err = mount.MountWithTimeout(2 * time.Second)
+	assert.Equal(t, cephfs.ErrMountTimeout, err)
+	assert.True(t, time.Since(start) < 30*time.Second)
+
+	err = mount.Release()
+	assert.NoError(t, err)
+}
+
+func TestCaps(t *testing.T) {
+	mount := fsConnect(t)
+	defer fsDisconnect(t, mount)
+
+	f, err := mount.Open("/caps", os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	assert.NoError(t, err)
+
+	caps, err := f.GetCaps()
+	assert.NoError(t, err)
+	assert.NotZero(t, caps)
+
+	pathCaps, err := mount.GetFileCaps("/caps")
+	assert.NoError(t, err)
+	assert.Equal(t, caps, pathCaps)
+
+	err = f.Close()
+	assert.NoError(t, err)
+
+	_, err = mount.GetFileCaps("/no-such-file")
+	assert.Error(t, err)
+
+	count, err := mount.CapCount()
+	assert.NoError(t, err)
+	assert.True(t, count > 0)
+
+	err = mount.SetCacheSize(100)
+	assert.NoError(t, err)
+	value, err := mount.GetConfigOption("client_cache_size")
+	assert.NoError(t, err)
+	assert.Equal(t, "100", value)
+
+	st, err := mount.ForceStatx("/caps", cephfs.StatxSize)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), st.Size)
+
+	_, err = mount.ForceStatx("/no-such-file", cephfs.StatxSize)
+	assert.Error(t, err)
+
+	err = mount.DropMDSCache("*", 10*time.Second)
+	assert.NoError(t, err)
+	dropped, err := mount.CapCount()
+	assert.NoError(t, err)
+	assert.True(t, dropped <= count)
+
+	err = mount.Unlink("/caps")
+	assert.NoError(t, err)
+}
+
+func TestAtCalls(t *testing.T) {
+	mount := fsConnect(t)
+	defer fsDisconnect(t, mount)
+
+	err := mount.MakeDir("/at", 0755)
+	assert.NoError(t, err)
+
+	dir, err := mount.Open("/at", os.O_RDONLY|syscall.O_DIRECTORY, 0)
+	assert.NoError(t, err)
+
+	// the handle keeps referring to the directory after a rename
+	err = mount.Rename("/at", "/at-renamed")
+	assert.NoError(t, err)
+
+	err = dir.MkdirAt("sub", 0755)
+	assert.NoError(t, err)
+
+	f, err := dir.OpenAt("sub/file", os.O_WRONLY|os.O_CREATE, 0644)
+	assert.NoError(t, err)
+	_, err = f.Write([]byte("at"))
+	assert.NoError(t, err)
+	err = f.Close()
+	assert.NoError(t, err)
+
+	stx, err := dir.StatxAt("sub/file", cephfs.StatxSize, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), stx.Size)
+	assert.Equal(t, []byte("at"), readFile(t, mount, "/at-renamed/sub/file"))
+
+	err = dir.UnlinkAt("sub", cephfs.AtRemoveDir)
+	assert.Error(t, err)
+	err = dir.UnlinkAt("sub/file", 0)
+	assert.NoError(t, err)
+	err = dir.UnlinkAt("sub", cephfs.AtRemoveDir)
+	assert.NoError(t, err)
+
+	_, err = dir.StatxAt("sub", cephfs.StatxMode, 0)
+	assert.Error(t, err)
+
+	err = dir.Close()
+	assert.NoError(t, err)
+	err = mount.RemoveDir("/at-renamed")
+	assert.NoError(t, err)
+}
+
+func TestPoolInfo(t *testing.T) {
+	mount := fsConnect(t)
+	defer fsDisconnect(t, mount)
+
+	writeFile(t, mount, "/pooled", []byte("pooled"))
+
+	defaultPool, err := mount.GetDefaultDataPoolName()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, defaultPool)
+
+	pool, err := mount.GetPoolName("/pooled")
+	assert.NoError(t, err)
+	assert.Equal(t, defaultPool, pool)
+
+	layout, err := mount.GetFileLayout("/pooled")
+	assert.NoError(t, err)
+	assert.Equal(t, layout.Pool, pool)
+
+	replication, err := mount.GetReplication("/pooled")
+	assert.NoError(t, err)
+	assert.True(t, replication >= 1)
+
+	f, err := mount.Open("/pooled", os.O_RDONLY, 0)
+	assert.NoError(t, err)
+
+	pool, err = f.GetPoolName()
+	assert.NoError(t, err)
+	assert.Equal(t, defaultPool, pool)
+
+	fileReplication, err := f.GetReplication()
+	assert.NoError(t, err)
+	assert.Equal(t, replication, fileReplication)
+
+	err = f.Close()
+	assert.NoError(t, err)
+
+	_, err = mount.GetPoolName("/no-such-file")
+	assert.Error(t, err)
+
+	err = mount.Unlink("/pooled")
+	assert.NoError(t, err)
+}
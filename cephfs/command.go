@@ -0,0 +1,51 @@
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <stdlib.h>
+#include <cephfs/libcephfs.h>
+*/
+import "C"
+
+import (
+	"unsafe"
+)
+
+// MdsCommand sends a JSON formatted command to the MDS daemons selected by
+// mdsSpec, which is a rank, a daemon name, or "*" for all active daemons.
+// It returns the output of the command and the status string.
+func (mount *MountInfo) MdsCommand(mdsSpec string, cmd []byte) (buffer []byte, info string, err error) {
+	c_spec := C.CString(mdsSpec)
+	defer C.free(unsafe.Pointer(c_spec))
+	c_cmd := C.CString(string(cmd))
+	defer C.free(unsafe.Pointer(c_cmd))
+
+	var (
+		outs, outbuf       *C.char
+		outslen, outbuflen C.size_t
+	)
+	ret := C.ceph_mds_command(mount.mount, c_spec,
+		&c_cmd, 1,
+		nil, 0, // bulk input
+		&outbuf, &outbuflen,
+		&outs, &outslen)
+
+	if outslen > 0 {
+		info = C.GoStringN(outs, C.int(outslen))
+	}
+	if outs != nil {
+		C.ceph_buffer_free(outs)
+	}
+	if outbuflen > 0 {
+		buffer = C.GoBytes(unsafe.Pointer(outbuf), C.int(outbuflen))
+	}
+	if outbuf != nil {
+		C.ceph_buffer_free(outbuf)
+	}
+	if ret != 0 {
+		return nil, info, CephError(ret)
+	}
+
+	return buffer, info, nil
+}
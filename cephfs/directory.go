@@ -0,0 +1,140 @@
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <errno.h>
+#include <stdlib.h>
+#include <dirent.h>
+#include <cephfs/libcephfs.h>
+*/
+import "C"
+
+import (
+	"unsafe"
+)
+
+// DType is the type of a directory entry.
+type DType uint8
+
+const (
+	DTypeUnknown = DType(C.DT_UNKNOWN)
+	DTypeFifo    = DType(C.DT_FIFO)
+	DTypeChr     = DType(C.DT_CHR)
+	DTypeDir     = DType(C.DT_DIR)
+	DTypeBlk     = DType(C.DT_BLK)
+	DTypeReg     = DType(C.DT_REG)
+	DTypeLnk     = DType(C.DT_LNK)
+	DTypeSock    = DType(C.DT_SOCK)
+)
+
+// DirEntry is an entry of a directory, as returned by ReadDir.
+type DirEntry struct {
+	Name  string
+	Inode uint64
+	Type  DType
+}
+
+func newDirEntry(de *C.struct_dirent) *DirEntry {
+	return &DirEntry{
+		Name:  C.GoString(&de.d_name[0]),
+		Inode: uint64(de.d_ino),
+		Type:  DType(de.d_type),
+	}
+}
+
+// Directory is a directory opened for reading its entries.
+type Directory struct {
+	mount *MountInfo
+	dir   *C.struct_ceph_dir_result
+	// lowLevel is set for directories opened with Inode.OpenDir.
+	lowLevel bool
+}
+
+// OpenDir opens the directory at path.
+func (mount *MountInfo) OpenDir(path string) (*Directory, error) {
+	c_path := C.CString(path)
+	defer C.free(unsafe.Pointer(c_path))
+
+	dir := &Directory{mount: mount}
+	ret := C.ceph_opendir(mount.mount, c_path, &dir.dir)
+	if ret != 0 {
+		return nil, CephError(ret)
+	}
+	return dir, nil
+}
+
+// Close closes the directory.
+func (dir *Directory) Close() error {
+	if dir.dir == nil {
+		return CephError(-C.EBADF)
+	}
+
+	var ret C.int
+	if dir.lowLevel {
+		ret = C.ceph_ll_releasedir(dir.mount.mount, dir.dir)
+	} else {
+		ret = C.ceph_closedir(dir.mount.mount, dir.dir)
+	}
+	if ret != 0 {
+		return CephError(ret)
+	}
+	dir.dir = nil
+	return nil
+}
+
+// ReadDir returns the next entry of the directory, or nil once all entries
+// have been read. The "." and ".." entries are included.
+func (dir *Directory) ReadDir() (*DirEntry, error) {
+	if dir.dir == nil {
+		return nil, CephError(-C.EBADF)
+	}
+
+	var de C.struct_dirent
+	ret := C.ceph_readdir_r(dir.mount.mount, dir.dir, &de)
+	if ret < 0 {
+		return nil, CephError(ret)
+	} else if ret == 0 {
+		return nil, nil
+	}
+	return newDirEntry(&de), nil
+}
+
+// RewindDir resets the directory so that ReadDir starts again at the first
+// entry.
+func (dir *Directory) RewindDir() {
+	if dir.dir == nil {
+		return
+	}
+	C.ceph_rewinddir(dir.mount.mount, dir.dir)
+}
+
+// DirEntryPlus is a directory entry together with the attributes of the
+// file it refers to, as returned by ReadDirPlus.
+type DirEntryPlus struct {
+	DirEntry
+	Statx *CephStatx
+}
+
+// ReadDirPlus returns the next entry of the directory like ReadDir, along
+// with the attributes selected by want. This avoids a separate stat call
+// for every entry. It returns nil once all entries have been read.
+func (dir *Directory) ReadDirPlus(want StatxMask, flags AtFlags) (*DirEntryPlus, error) {
+	if dir.dir == nil {
+		return nil, CephError(-C.EBADF)
+	}
+
+	var de C.struct_dirent
+	var stx C.struct_ceph_statx
+	ret := C.ceph_readdirplus_r(dir.mount.mount, dir.dir, &de, &stx,
+		C.uint(want), C.uint(flags), nil)
+	if ret < 0 {
+		return nil, CephError(ret)
+	} else if ret == 0 {
+		return nil, nil
+	}
+	return &DirEntryPlus{
+		DirEntry: *newDirEntry(&de),
+		Statx:    newCephStatx(&stx),
+	}, nil
+}
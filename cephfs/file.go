@@ -0,0 +1,320 @@
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#define _GNU_SOURCE
+#include <errno.h>
+#include <stdlib.h>
+#include <linux/falloc.h>
+#include <sys/file.h>
+#include <unistd.h>
+#include <cephfs/libcephfs.h>
+*/
+import "C"
+
+import (
+	"io"
+	"io/fs"
+	"path"
+	"unsafe"
+)
+
+// FallocMode selects the operation of File.Fallocate.
+type FallocMode int
+
+const (
+	// FallocKeepSize allocates space without changing the file size.
+	FallocKeepSize = FallocMode(C.FALLOC_FL_KEEP_SIZE)
+	// FallocPunchHole deallocates the range, which then reads as zeros.
+	// It must be combined with FallocKeepSize.
+	FallocPunchHole = FallocMode(C.FALLOC_FL_PUNCH_HOLE)
+)
+
+// LockOp is the operation of File.Flock.
+type LockOp int
+
+const (
+	LockSh = LockOp(C.LOCK_SH)
+	LockEx = LockOp(C.LOCK_EX)
+	LockUn = LockOp(C.LOCK_UN)
+	// LockNb makes LockSh and LockEx fail instead of blocking when the lock
+	// is held by another owner.
+	LockNb = LockOp(C.LOCK_NB)
+)
+
+// Whence values of File.Seek in addition to io.SeekStart, io.SeekCurrent and
+// io.SeekEnd, for skipping the holes of sparse files.
+const (
+	// SeekData seeks to the start of the next data at or after offset.
+	SeekData = int(C.SEEK_DATA)
+	// SeekHole seeks to the start of the next hole at or after offset. The
+	// end of the file counts as a hole.
+	SeekHole = int(C.SEEK_HOLE)
+)
+
+// File is a file opened on a mounted file system. It implements the io
+// interfaces like os.File does.
+type File struct {
+	mount *MountInfo
+	fd    C.int
+	name  string
+}
+
+// Open opens the file at path with the given flags, which are the os.O_*
+// flags, creating it with the permissions mode if os.O_CREATE is given.
+func (mount *MountInfo) Open(path string, flags int, mode uint32) (*File, error) {
+	c_path := C.CString(path)
+	defer C.free(unsafe.Pointer(c_path))
+
+	ret := C.ceph_open(mount.mount, c_path, C.int(flags), C.mode_t(mode))
+	if ret < 0 {
+		return nil, CephError(ret)
+	}
+
+	return &File{mount: mount, fd: ret, name: path}, nil
+}
+
+// Close closes the file.
+func (f *File) Close() error {
+	ret := C.ceph_close(f.mount.mount, f.fd)
+	if ret != 0 {
+		return CephError(ret)
+	}
+	f.fd = -1
+	return nil
+}
+
+func (f *File) read(buf []byte, offset int64) (int, error) {
+	if len(buf) == 0 {
+		return 0, nil
+	}
+
+	ret := C.ceph_read(f.mount.mount, f.fd,
+		(*C.char)(unsafe.Pointer(&buf[0])), C.int64_t(len(buf)),
+		C.int64_t(offset))
+	if ret < 0 {
+		return 0, CephError(ret)
+	} else if ret == 0 {
+		return 0, io.EOF
+	}
+	return int(ret), nil
+}
+
+func (f *File) write(buf []byte, offset int64) (int, error) {
+	if len(buf) == 0 {
+		return 0, nil
+	}
+
+	ret := C.ceph_write(f.mount.mount, f.fd,
+		(*C.char)(unsafe.Pointer(&buf[0])), C.int64_t(len(buf)),
+		C.int64_t(offset))
+	if ret < 0 {
+		return 0, CephError(ret)
+	}
+	return int(ret), nil
+}
+
+// Read reads up to len(buf) bytes from the current position of the file.
+// It returns io.EOF at the end of the file.
+func (f *File) Read(buf []byte) (int, error) {
+	return f.read(buf, -1)
+}
+
+// ReadAt reads len(buf) bytes from the file starting at offset. It returns
+// io.EOF if the file ends before buf is filled.
+func (f *File) ReadAt(buf []byte, offset int64) (int, error) {
+	if offset < 0 {
+		return 0, CephError(-C.EINVAL)
+	}
+
+	total := 0
+	for total < len(buf) {
+		n, err := f.read(buf[total:], offset+int64(total))
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// Write writes buf at the current position of the file.
+func (f *File) Write(buf []byte) (int, error) {
+	return f.write(buf, -1)
+}
+
+// WriteAt writes buf to the file starting at offset. It returns an error
+// if less than len(buf) bytes are written, io.ErrShortWrite if libcephfs
+// stops writing without one.
+func (f *File) WriteAt(buf []byte, offset int64) (int, error) {
+	if offset < 0 {
+		return 0, CephError(-C.EINVAL)
+	}
+
+	total := 0
+	for total < len(buf) {
+		n, err := f.write(buf[total:], offset+int64(total))
+		total += n
+		if err != nil {
+			return total, err
+		}
+		if n == 0 {
+			return total, io.ErrShortWrite
+		}
+	}
+	return total, nil
+}
+
+// Seek sets the position of the file for the next Read or Write. whence is
+// one of io.SeekStart, io.SeekCurrent, io.SeekEnd, SeekData and SeekHole.
+// SeekData and SeekHole fail with ENXIO if offset is at or beyond the end
+// of the file.
+func (f *File) Seek(offset int64, whence int) (int64, error) {
+	ret := C.ceph_lseek(f.mount.mount, f.fd, C.int64_t(offset), C.int(whence))
+	if ret < 0 {
+		return 0, CephError(ret)
+	}
+	return int64(ret), nil
+}
+
+// Preadv reads from the file starting at offset into the buffers in order,
+// filling each before moving to the next. It returns the total number of
+// bytes read, and io.EOF if nothing was read at the end of the file.
+func (f *File) Preadv(data [][]byte, offset int64) (int, error) {
+	v := newIovecs(data)
+	defer v.free()
+
+	ret := C.ceph_preadv(f.mount.mount, f.fd, v.pointer(), v.count(),
+		C.int64_t(offset))
+	if ret < 0 {
+		return 0, CephError(ret)
+	}
+	v.toGo(int(ret))
+	if ret == 0 && totalLen(data) > 0 {
+		return 0, io.EOF
+	}
+	return int(ret), nil
+}
+
+// Pwritev writes the buffers in order to the file starting at offset with a
+// single call. It returns the total number of bytes written.
+func (f *File) Pwritev(data [][]byte, offset int64) (int, error) {
+	v := newIovecs(data)
+	defer v.free()
+	v.fromGo()
+
+	ret := C.ceph_pwritev(f.mount.mount, f.fd, v.pointer(), v.count(),
+		C.int64_t(offset))
+	if ret < 0 {
+		return 0, CephError(ret)
+	}
+	return int(ret), nil
+}
+
+func totalLen(data [][]byte) int {
+	n := 0
+	for _, buf := range data {
+		n += len(buf)
+	}
+	return n
+}
+
+// Fchmod changes the permissions of the open file to mode.
+func (f *File) Fchmod(mode uint32) error {
+	return getError(C.ceph_fchmod(f.mount.mount, f.fd, C.mode_t(mode)))
+}
+
+// Fchown changes the owner and group of the open file. A uid or gid of -1
+// leaves that value unchanged.
+func (f *File) Fchown(uid, gid int) error {
+	return getError(C.ceph_fchown(f.mount.mount, f.fd, C.int(uid), C.int(gid)))
+}
+
+// Ftruncate changes the size of the open file. The file position is not
+// changed.
+func (f *File) Ftruncate(size int64) error {
+	return getError(C.ceph_ftruncate(f.mount.mount, f.fd, C.int64_t(size)))
+}
+
+// Fallocate allocates or, with FallocPunchHole, deallocates the range of
+// length bytes at offset of the open file. With mode 0 the file is extended
+// if the range ends beyond its current size.
+func (f *File) Fallocate(mode FallocMode, offset, length int64) error {
+	return getError(C.ceph_fallocate(f.mount.mount, f.fd, C.int(mode),
+		C.int64_t(offset), C.int64_t(length)))
+}
+
+// Flock applies or removes an advisory lock on the whole open file, like
+// flock(2). Locks are held by owner, an arbitrary id chosen by the caller,
+// and are shared with all clients of the file system.
+func (f *File) Flock(operation LockOp, owner uint64) error {
+	return getError(C.ceph_flock(f.mount.mount, f.fd, C.int(operation),
+		C.uint64_t(owner)))
+}
+
+// Fsync writes the buffered data and metadata of the open file to the
+// cluster. With dataOnly, metadata that is not needed to read the data back,
+// such as the modification time, is not synced.
+func (f *File) Fsync(dataOnly bool) error {
+	var c_dataonly C.int
+	if dataOnly {
+		c_dataonly = 1
+	}
+	return getError(C.ceph_fsync(f.mount.mount, f.fd, c_dataonly))
+}
+
+// LazyIO enables or disables lazy I/O on the open file. With lazy I/O,
+// clients that have the file open for writing at the same time keep caching
+// and buffering its data instead of falling back to synchronous I/O. The
+// application is then responsible for coherency, using LazyIOPropagate and
+// LazyIOSynchronize.
+func (f *File) LazyIO(enable bool) error {
+	var c_enable C.int
+	if enable {
+		c_enable = 1
+	}
+	return getError(C.ceph_lazyio(f.mount.mount, f.fd, c_enable))
+}
+
+// LazyIOPropagate writes the buffered data of the count bytes at offset to
+// the cluster, so that other clients can see it after LazyIOSynchronize. A
+// count of zero covers the whole file.
+func (f *File) LazyIOPropagate(offset int64, count uint64) error {
+	return getError(C.ceph_lazyio_propagate(f.mount.mount, f.fd,
+		C.int64_t(offset), C.size_t(count)))
+}
+
+// LazyIOSynchronize drops the cached data of the count bytes at offset, so
+// that data propagated by other clients is read. A count of zero covers the
+// whole file.
+func (f *File) LazyIOSynchronize(offset int64, count uint64) error {
+	return getError(C.ceph_lazyio_synchronize(f.mount.mount, f.fd,
+		C.int64_t(offset), C.size_t(count)))
+}
+
+// Name returns the path the file was opened with.
+func (f *File) Name() string {
+	return f.name
+}
+
+// Stat returns the attributes of the open file as an fs.FileInfo, like
+// os.File.Stat. Its Sys method returns the *CephStatx.
+func (f *File) Stat() (fs.FileInfo, error) {
+	stx, err := f.Fstatx(StatxBasicStats, 0)
+	if err != nil {
+		return nil, err
+	}
+	return newFileInfo(path.Base(f.name), stx), nil
+}
+
+// Truncate changes the size of the open file like Ftruncate.
+func (f *File) Truncate(size int64) error {
+	return f.Ftruncate(size)
+}
+
+// Sync writes the buffered data and metadata of the open file to the
+// cluster like Fsync.
+func (f *File) Sync() error {
+	return f.Fsync(false)
+}
@@ -0,0 +1,236 @@
+package cephfs
+
+import (
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"syscall"
+	"time"
+)
+
+// FS exposes a subtree of a mounted file system as a read only fs.FS, so that
+// code like http.FileServer and fs.WalkDir can use CephFS. It implements
+// fs.ReadDirFS and fs.StatFS as well.
+type FS struct {
+	mount *MountInfo
+	root  string
+}
+
+// NewFS returns the subtree of the mount at root as an FS.
+func NewFS(mount *MountInfo, root string) *FS {
+	return &FS{mount: mount, root: root}
+}
+
+// fsError converts an error of the mount into the errors of the io/fs
+// package, so that errors.Is(err, fs.ErrNotExist) and the like work.
+func fsError(op, name string, err error) error {
+	if e, ok := err.(CephError); ok {
+		err = syscall.Errno(-e)
+	}
+	return &fs.PathError{Op: op, Path: name, Err: err}
+}
+
+func (fsys *FS) path(op, name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	return path.Join(fsys.root, name), nil
+}
+
+// Open opens the file name for reading.
+func (fsys *FS) Open(name string) (fs.File, error) {
+	p, err := fsys.path("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	stx, err := fsys.mount.Statx(p, StatxBasicStats, 0)
+	if err != nil {
+		return nil, fsError("open", name, err)
+	}
+	info := newFileInfo(path.Base(name), stx)
+
+	if info.IsDir() {
+		dir, err := fsys.mount.OpenDir(p)
+		if err != nil {
+			return nil, fsError("open", name, err)
+		}
+		return &fsDir{dir: dir, name: name, info: info}, nil
+	}
+
+	f, err := fsys.mount.Open(p, syscall.O_RDONLY, 0)
+	if err != nil {
+		return nil, fsError("open", name, err)
+	}
+	return &fsFile{File: f, info: info}, nil
+}
+
+// Stat returns the attributes of the file name. Symbolic links are followed.
+func (fsys *FS) Stat(name string) (fs.FileInfo, error) {
+	p, err := fsys.path("stat", name)
+	if err != nil {
+		return nil, err
+	}
+
+	stx, err := fsys.mount.Statx(p, StatxBasicStats, 0)
+	if err != nil {
+		return nil, fsError("stat", name, err)
+	}
+	return newFileInfo(path.Base(name), stx), nil
+}
+
+// ReadDir returns the entries of the directory name sorted by name.
+func (fsys *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	p, err := fsys.path("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := fsys.mount.readDirSorted(p)
+	if err != nil {
+		return nil, fsError("readdir", name, err)
+	}
+	return entries, nil
+}
+
+// readDirSorted returns the entries of the directory at path sorted by name.
+func (mount *MountInfo) readDirSorted(path string) ([]fs.DirEntry, error) {
+	dir, err := mount.OpenDir(path)
+	if err != nil {
+		return nil, err
+	}
+	defer dir.Close()
+
+	entries, err := readDirEntries(dir, -1)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+	return entries, nil
+}
+
+// readDirEntries reads up to n entries of dir, or all remaining entries if
+// n is negative. The "." and ".." entries are skipped.
+func readDirEntries(dir *Directory, n int) ([]fs.DirEntry, error) {
+	entries := []fs.DirEntry{}
+	for n < 0 || len(entries) < n {
+		entry, err := dir.ReadDirPlus(StatxBasicStats, AtSymlinkNofollow)
+		if err != nil {
+			return entries, err
+		} else if entry == nil {
+			break
+		}
+
+		if entry.Name == "." || entry.Name == ".." {
+			continue
+		}
+		info := newFileInfo(entry.Name, entry.Statx)
+		entries = append(entries, fs.FileInfoToDirEntry(info))
+	}
+	return entries, nil
+}
+
+// fileInfo implements fs.FileInfo for the attributes of a file. Sys returns
+// the *CephStatx.
+type fileInfo struct {
+	name string
+	stx  *CephStatx
+}
+
+func newFileInfo(name string, stx *CephStatx) *fileInfo {
+	return &fileInfo{name: name, stx: stx}
+}
+
+func (fi *fileInfo) Name() string {
+	return fi.name
+}
+
+func (fi *fileInfo) Size() int64 {
+	return int64(fi.stx.Size)
+}
+
+func (fi *fileInfo) Mode() fs.FileMode {
+	mode := fs.FileMode(fi.stx.Mode & 0777)
+	switch uint32(fi.stx.Mode) & syscall.S_IFMT {
+	case syscall.S_IFDIR:
+		mode |= fs.ModeDir
+	case syscall.S_IFLNK:
+		mode |= fs.ModeSymlink
+	case syscall.S_IFIFO:
+		mode |= fs.ModeNamedPipe
+	case syscall.S_IFSOCK:
+		mode |= fs.ModeSocket
+	case syscall.S_IFCHR:
+		mode |= fs.ModeDevice | fs.ModeCharDevice
+	case syscall.S_IFBLK:
+		mode |= fs.ModeDevice
+	}
+	if fi.stx.Mode&syscall.S_ISUID != 0 {
+		mode |= fs.ModeSetuid
+	}
+	if fi.stx.Mode&syscall.S_ISGID != 0 {
+		mode |= fs.ModeSetgid
+	}
+	if fi.stx.Mode&syscall.S_ISVTX != 0 {
+		mode |= fs.ModeSticky
+	}
+	return mode
+}
+
+func (fi *fileInfo) ModTime() time.Time {
+	return time.Unix(fi.stx.Mtime.Sec, fi.stx.Mtime.Nsec)
+}
+
+func (fi *fileInfo) IsDir() bool {
+	return fi.Mode().IsDir()
+}
+
+func (fi *fileInfo) Sys() interface{} {
+	return fi.stx
+}
+
+// fsFile is a regular file opened through an FS.
+type fsFile struct {
+	*File
+	info *fileInfo
+}
+
+func (f *fsFile) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+// fsDir is a directory opened through an FS.
+type fsDir struct {
+	dir  *Directory
+	name string
+	info *fileInfo
+}
+
+func (d *fsDir) Stat() (fs.FileInfo, error) {
+	return d.info, nil
+}
+
+func (d *fsDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: syscall.EISDIR}
+}
+
+func (d *fsDir) Close() error {
+	return d.dir.Close()
+}
+
+func (d *fsDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if n <= 0 {
+		n = -1
+	}
+	entries, err := readDirEntries(d.dir, n)
+	if err != nil {
+		return entries, fsError("readdir", d.name, err)
+	}
+	if n > 0 && len(entries) == 0 {
+		return entries, io.EOF
+	}
+	return entries, nil
+}
@@ -0,0 +1,251 @@
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <errno.h>
+#include <stdlib.h>
+#include <fcntl.h>
+#include <cephfs/libcephfs.h>
+*/
+import "C"
+
+import (
+	"io"
+	"unsafe"
+)
+
+// Inode is a reference to a file of the low level API, which addresses files
+// by handle rather than by path. Every Inode returned must be released with
+// Put.
+//
+// The low level calls take the credentials to check permissions against.
+// If perm is nil, the credentials of the mount are used.
+type Inode struct {
+	mount *MountInfo
+	inode *C.struct_Inode
+}
+
+func (mount *MountInfo) userPerm(perm *UserPerm) *C.UserPerm {
+	if perm == nil {
+		return C.ceph_mount_perms(mount.mount)
+	}
+	return perm.perm
+}
+
+// LookupRoot returns the root directory of the mount.
+func (mount *MountInfo) LookupRoot() (*Inode, error) {
+	in := &Inode{mount: mount}
+	ret := C.ceph_ll_lookup_root(mount.mount, &in.inode)
+	if ret != 0 {
+		return nil, CephError(ret)
+	}
+	return in, nil
+}
+
+// Put releases the reference to the inode.
+func (in *Inode) Put() error {
+	if in.inode == nil {
+		return CephError(-C.EBADF)
+	}
+
+	ret := C.ceph_ll_put(in.mount.mount, in.inode)
+	if ret != 0 {
+		return CephError(ret)
+	}
+	in.inode = nil
+	return nil
+}
+
+// Lookup returns the entry name of the directory, along with the attributes
+// selected by want.
+func (in *Inode) Lookup(name string, want StatxMask, flags AtFlags, perm *UserPerm) (*Inode, *CephStatx, error) {
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	out := &Inode{mount: in.mount}
+	var stx C.struct_ceph_statx
+	ret := C.ceph_ll_lookup(in.mount.mount, in.inode, c_name, &out.inode,
+		&stx, C.uint(want), C.uint(flags), in.mount.userPerm(perm))
+	if ret != 0 {
+		return nil, nil, CephError(ret)
+	}
+	return out, newCephStatx(&stx), nil
+}
+
+// Getattr returns the attributes of the inode selected by want.
+func (in *Inode) Getattr(want StatxMask, flags AtFlags, perm *UserPerm) (*CephStatx, error) {
+	var stx C.struct_ceph_statx
+	ret := C.ceph_ll_getattr(in.mount.mount, in.inode, &stx, C.uint(want),
+		C.uint(flags), in.mount.userPerm(perm))
+	if ret != 0 {
+		return nil, CephError(ret)
+	}
+	return newCephStatx(&stx), nil
+}
+
+// OpenDir opens the directory for reading its entries. ReadDirInode returns
+// the entries along with references to their inodes.
+func (in *Inode) OpenDir(perm *UserPerm) (*Directory, error) {
+	dir := &Directory{mount: in.mount, lowLevel: true}
+	ret := C.ceph_ll_opendir(in.mount.mount, in.inode, &dir.dir,
+		in.mount.userPerm(perm))
+	if ret != 0 {
+		return nil, CephError(ret)
+	}
+	return dir, nil
+}
+
+// ReadDirInode returns the next entry of the directory like ReadDirPlus, and
+// a reference to the inode of the entry that must be released with Put. It
+// returns nil once all entries have been read.
+func (dir *Directory) ReadDirInode(want StatxMask, flags AtFlags) (*DirEntryPlus, *Inode, error) {
+	if dir.dir == nil {
+		return nil, nil, CephError(-C.EBADF)
+	}
+
+	var de C.struct_dirent
+	var stx C.struct_ceph_statx
+	in := &Inode{mount: dir.mount}
+	ret := C.ceph_readdirplus_r(dir.mount.mount, dir.dir, &de, &stx,
+		C.uint(want), C.uint(flags), &in.inode)
+	if ret < 0 {
+		return nil, nil, CephError(ret)
+	} else if ret == 0 {
+		return nil, nil, nil
+	}
+	return &DirEntryPlus{
+		DirEntry: *newDirEntry(&de),
+		Statx:    newCephStatx(&stx),
+	}, in, nil
+}
+
+// FileHandle is a file of the low level API opened with Inode.Open. Unlike
+// File, it has no position; reads and writes always give an offset.
+type FileHandle struct {
+	mount *MountInfo
+	fh    *C.struct_Fh
+}
+
+// Open opens the file with the given flags, which are the os.O_* flags.
+// Files are created through the path based API.
+func (in *Inode) Open(flags int, perm *UserPerm) (*FileHandle, error) {
+	fh := &FileHandle{mount: in.mount}
+	ret := C.ceph_ll_open(in.mount.mount, in.inode, C.int(flags), &fh.fh,
+		in.mount.userPerm(perm))
+	if ret != 0 {
+		return nil, CephError(ret)
+	}
+	return fh, nil
+}
+
+// Close closes the file handle.
+func (fh *FileHandle) Close() error {
+	if fh.fh == nil {
+		return CephError(-C.EBADF)
+	}
+
+	ret := C.ceph_ll_close(fh.mount.mount, fh.fh)
+	if ret != 0 {
+		return CephError(ret)
+	}
+	fh.fh = nil
+	return nil
+}
+
+// ReadAt reads len(buf) bytes from the file starting at offset. It returns
+// io.EOF if the file ends before buf is filled.
+func (fh *FileHandle) ReadAt(buf []byte, offset int64) (int, error) {
+	if offset < 0 {
+		return 0, CephError(-C.EINVAL)
+	}
+
+	total := 0
+	for total < len(buf) {
+		ret := C.ceph_ll_read(fh.mount.mount, fh.fh, C.int64_t(offset+int64(total)),
+			C.uint64_t(len(buf)-total), (*C.char)(unsafe.Pointer(&buf[total])))
+		if ret < 0 {
+			return total, CephError(ret)
+		} else if ret == 0 {
+			return total, io.EOF
+		}
+		total += int(ret)
+	}
+	return total, nil
+}
+
+// WriteAt writes buf to the file starting at offset.
+func (fh *FileHandle) WriteAt(buf []byte, offset int64) (int, error) {
+	if offset < 0 {
+		return 0, CephError(-C.EINVAL)
+	}
+	if len(buf) == 0 {
+		return 0, nil
+	}
+
+	ret := C.ceph_ll_write(fh.mount.mount, fh.fh, C.int64_t(offset),
+		C.uint64_t(len(buf)), (*C.char)(unsafe.Pointer(&buf[0])))
+	if ret < 0 {
+		return 0, CephError(ret)
+	}
+	return int(ret), nil
+}
+
+// PosixLockType is the type of a PosixLock.
+type PosixLockType int16
+
+const (
+	PosixLockRead   = PosixLockType(C.F_RDLCK)
+	PosixLockWrite  = PosixLockType(C.F_WRLCK)
+	PosixLockUnlock = PosixLockType(C.F_UNLCK)
+)
+
+// PosixLock is a byte range lock as set by fcntl(2). A Len of zero extends
+// the lock to the end of the file, however large it grows.
+type PosixLock struct {
+	Type  PosixLockType
+	Start int64
+	Len   int64
+	Pid   int
+}
+
+func (lock *PosixLock) flock() C.struct_flock {
+	var fl C.struct_flock
+	fl.l_type = C.short(lock.Type)
+	fl.l_whence = C.SEEK_SET
+	fl.l_start = C.off_t(lock.Start)
+	fl.l_len = C.off_t(lock.Len)
+	fl.l_pid = C.pid_t(lock.Pid)
+	return fl
+}
+
+// Getlk tests whether lock could be set by owner. It returns the first lock
+// of another owner that conflicts with it, or a lock of type PosixLockUnlock
+// if there is none.
+func (fh *FileHandle) Getlk(lock *PosixLock, owner uint64) (*PosixLock, error) {
+	fl := lock.flock()
+	ret := C.ceph_ll_getlk(fh.mount.mount, fh.fh, &fl, C.uint64_t(owner))
+	if ret != 0 {
+		return nil, CephError(ret)
+	}
+	return &PosixLock{
+		Type:  PosixLockType(fl.l_type),
+		Start: int64(fl.l_start),
+		Len:   int64(fl.l_len),
+		Pid:   int(fl.l_pid),
+	}, nil
+}
+
+// Setlk sets or, with PosixLockUnlock, releases lock for owner. If the lock
+// conflicts with a lock of another owner, Setlk waits for it to be released
+// if wait is true, and fails with EAGAIN otherwise.
+func (fh *FileHandle) Setlk(lock *PosixLock, owner uint64, wait bool) error {
+	c_sleep := C.int(0)
+	if wait {
+		c_sleep = 1
+	}
+
+	fl := lock.flock()
+	return getError(C.ceph_ll_setlk(fh.mount.mount, fh.fh, &fl,
+		C.uint64_t(owner), c_sleep))
+}
@@ -0,0 +1,81 @@
+package cephfs
+
+/*
+#include <stdlib.h>
+#include <sys/uio.h>
+*/
+import "C"
+
+import (
+	"unsafe"
+)
+
+// iovecs is a C array of struct iovec backed by C buffers of the same sizes
+// as a set of Go buffers. C code must not keep Go pointers, so the data is
+// copied between the Go and C buffers around the call using the iovecs.
+type iovecs struct {
+	iovec []C.struct_iovec
+	bufs  [][]byte
+}
+
+func newIovecs(bufs [][]byte) *iovecs {
+	v := &iovecs{bufs: bufs}
+	if len(bufs) == 0 {
+		return v
+	}
+
+	size := C.size_t(len(bufs)) * C.size_t(unsafe.Sizeof(C.struct_iovec{}))
+	p := (*[1 << 20]C.struct_iovec)(C.malloc(size))
+	v.iovec = p[:len(bufs):len(bufs)]
+	for i, buf := range bufs {
+		v.iovec[i].iov_base = C.malloc(C.size_t(len(buf)))
+		v.iovec[i].iov_len = C.size_t(len(buf))
+	}
+	return v
+}
+
+func (v *iovecs) pointer() *C.struct_iovec {
+	if len(v.iovec) == 0 {
+		return nil
+	}
+	return &v.iovec[0]
+}
+
+func (v *iovecs) count() C.int {
+	return C.int(len(v.iovec))
+}
+
+// fromGo copies the Go buffers into the C buffers.
+func (v *iovecs) fromGo() {
+	for i, buf := range v.bufs {
+		if len(buf) > 0 {
+			copy((*[1 << 30]byte)(v.iovec[i].iov_base)[:len(buf):len(buf)], buf)
+		}
+	}
+}
+
+// toGo copies the first n bytes of the C buffers into the Go buffers.
+func (v *iovecs) toGo(n int) {
+	for i, buf := range v.bufs {
+		if n <= 0 {
+			return
+		}
+		if len(buf) > n {
+			buf = buf[:n]
+		}
+		if len(buf) > 0 {
+			copy(buf, (*[1 << 30]byte)(v.iovec[i].iov_base)[:len(buf):len(buf)])
+		}
+		n -= len(buf)
+	}
+}
+
+func (v *iovecs) free() {
+	for i := range v.iovec {
+		C.free(v.iovec[i].iov_base)
+	}
+	if len(v.iovec) > 0 {
+		C.free(unsafe.Pointer(&v.iovec[0]))
+	}
+	v.iovec = nil
+}
@@ -0,0 +1,198 @@
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <errno.h>
+#include <stdlib.h>
+#include <cephfs/libcephfs.h>
+*/
+import "C"
+
+import (
+	"unsafe"
+)
+
+// Layout describes how the data of a file is striped over RADOS objects and
+// in which data pool the objects are stored.
+type Layout struct {
+	StripeUnit    uint64
+	StripeCount   uint64
+	ObjectSize    uint64
+	Pool          string
+	PoolNamespace string
+}
+
+const (
+	fileLayoutPrefix = "ceph.file.layout."
+	dirLayoutPrefix  = "ceph.dir.layout."
+)
+
+func (mount *MountInfo) getXattrString(path, name string) (string, error) {
+	value, err := mount.GetXattr(path, name)
+	if err == CephError(-C.ENODATA) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
+
+func (mount *MountInfo) getLayout(path, prefix string) (*Layout, error) {
+	var err error
+	layout := &Layout{}
+	if layout.StripeUnit, err = mount.getXattrUint64(path, prefix+"stripe_unit"); err != nil {
+		return nil, err
+	}
+	if layout.StripeCount, err = mount.getXattrUint64(path, prefix+"stripe_count"); err != nil {
+		return nil, err
+	}
+	if layout.ObjectSize, err = mount.getXattrUint64(path, prefix+"object_size"); err != nil {
+		return nil, err
+	}
+	if layout.Pool, err = mount.getXattrString(path, prefix+"pool"); err != nil {
+		return nil, err
+	}
+	if layout.PoolNamespace, err = mount.getXattrString(path, prefix+"pool_namespace"); err != nil {
+		return nil, err
+	}
+	return layout, nil
+}
+
+// setLayout sets the fields of layout that are not zero. The pool must be
+// set before the striping, because the layout is validated as a whole on
+// every change.
+func (mount *MountInfo) setLayout(path, prefix string, layout *Layout) error {
+	if layout.Pool != "" {
+		err := mount.SetXattr(path, prefix+"pool", []byte(layout.Pool), XattrDefault)
+		if err != nil {
+			return err
+		}
+	}
+	if layout.PoolNamespace != "" {
+		err := mount.SetXattr(path, prefix+"pool_namespace",
+			[]byte(layout.PoolNamespace), XattrDefault)
+		if err != nil {
+			return err
+		}
+	}
+	if layout.ObjectSize != 0 {
+		err := mount.setXattrUint64(path, prefix+"object_size", layout.ObjectSize)
+		if err != nil {
+			return err
+		}
+	}
+	if layout.StripeUnit != 0 {
+		err := mount.setXattrUint64(path, prefix+"stripe_unit", layout.StripeUnit)
+		if err != nil {
+			return err
+		}
+	}
+	if layout.StripeCount != 0 {
+		err := mount.setXattrUint64(path, prefix+"stripe_count", layout.StripeCount)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetFileLayout returns the layout of the file at path.
+func (mount *MountInfo) GetFileLayout(path string) (*Layout, error) {
+	return mount.getLayout(path, fileLayoutPrefix)
+}
+
+// SetFileLayout changes the layout of the file at path. Fields of layout that
+// are zero are left unchanged. The layout of a file can only be changed while
+// the file is empty.
+func (mount *MountInfo) SetFileLayout(path string, layout *Layout) error {
+	return mount.setLayout(path, fileLayoutPrefix, layout)
+}
+
+// GetDirLayout returns the layout that new files created below the directory
+// at path inherit from it. The fields are zero if the directory has no
+// layout of its own.
+func (mount *MountInfo) GetDirLayout(path string) (*Layout, error) {
+	return mount.getLayout(path, dirLayoutPrefix)
+}
+
+// SetDirLayout sets the layout that new files created below the directory at
+// path inherit. Fields of layout that are zero are left unchanged. Existing
+// files keep their layout. The pool must have been added to the file system
+// as a data pool.
+func (mount *MountInfo) SetDirLayout(path string, layout *Layout) error {
+	return mount.setLayout(path, dirLayoutPrefix, layout)
+}
+
+// RemoveDirLayout removes the layout of the directory at path, so that new
+// files inherit the layout of its parent again.
+func (mount *MountInfo) RemoveDirLayout(path string) error {
+	return mount.RemoveXattr(path, "ceph.dir.layout")
+}
+
+type poolNameFunc func(buf *C.char, size C.size_t) C.int
+
+func getPoolName(nameFn poolNameFunc) (string, error) {
+	buf := make([]byte, 64)
+	for {
+		ret := nameFn((*C.char)(unsafe.Pointer(&buf[0])), C.size_t(len(buf)))
+		if ret == -C.ERANGE {
+			buf = make([]byte, len(buf)*2)
+			continue
+		} else if ret < 0 {
+			return "", CephError(ret)
+		}
+
+		return C.GoStringN((*C.char)(unsafe.Pointer(&buf[0])), C.int(ret)), nil
+	}
+}
+
+// GetPoolName returns the name of the data pool that the data of the file
+// at path is stored in.
+func (mount *MountInfo) GetPoolName(path string) (string, error) {
+	c_path := C.CString(path)
+	defer C.free(unsafe.Pointer(c_path))
+
+	return getPoolName(func(buf *C.char, size C.size_t) C.int {
+		return C.ceph_get_path_pool_name(mount.mount, c_path, buf, size)
+	})
+}
+
+// GetPoolName returns the name of the data pool that the data of the open
+// file is stored in.
+func (f *File) GetPoolName() (string, error) {
+	return getPoolName(func(buf *C.char, size C.size_t) C.int {
+		return C.ceph_get_file_pool_name(f.mount.mount, f.fd, buf, size)
+	})
+}
+
+// GetDefaultDataPoolName returns the name of the data pool of files that
+// have no other pool set in their layout.
+func (mount *MountInfo) GetDefaultDataPoolName() (string, error) {
+	return getPoolName(func(buf *C.char, size C.size_t) C.int {
+		return C.ceph_get_default_data_pool_name(mount.mount, buf, size)
+	})
+}
+
+// GetReplication returns the number of replicas of the data of the file at
+// path, which is the size of its data pool.
+func (mount *MountInfo) GetReplication(path string) (int, error) {
+	c_path := C.CString(path)
+	defer C.free(unsafe.Pointer(c_path))
+
+	ret := C.ceph_get_path_replication(mount.mount, c_path)
+	if ret < 0 {
+		return 0, CephError(ret)
+	}
+	return int(ret), nil
+}
+
+// GetReplication returns the number of replicas of the data of the open
+// file.
+func (f *File) GetReplication() (int, error) {
+	ret := C.ceph_get_file_replication(f.mount.mount, f.fd)
+	if ret < 0 {
+		return 0, CephError(ret)
+	}
+	return int(ret), nil
+}
@@ -0,0 +1,85 @@
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <errno.h>
+#include <stdlib.h>
+#include <sys/socket.h>
+#include <netinet/in.h>
+#include <cephfs/libcephfs.h>
+*/
+import "C"
+
+import (
+	"net"
+	"unsafe"
+)
+
+// GetStripeUnit returns the stripe unit of the file, the amount of data
+// stored contiguously in one object.
+func (f *File) GetStripeUnit() (int, error) {
+	ret := C.ceph_get_file_stripe_unit(f.mount.mount, f.fd)
+	if ret < 0 {
+		return 0, CephError(ret)
+	}
+	return int(ret), nil
+}
+
+// GetExtentOsds returns the OSDs that store the data of the file at offset,
+// the primary first. length is the number of bytes from offset that are
+// stored in the same object and thus on the same OSDs.
+func (f *File) GetExtentOsds(offset int64) (osds []int, length int64, err error) {
+	c_osds := make([]C.int, 8)
+	for {
+		var c_length C.int64_t
+		ret := C.ceph_get_file_extent_osds(f.mount.mount, f.fd,
+			C.int64_t(offset), &c_length, &c_osds[0], C.int(len(c_osds)))
+		if ret == -C.ERANGE {
+			c_osds = make([]C.int, len(c_osds)*2)
+			continue
+		} else if ret < 0 {
+			return nil, 0, CephError(ret)
+		}
+
+		osds = make([]int, ret)
+		for i := range osds {
+			osds[i] = int(c_osds[i])
+		}
+		return osds, int64(c_length), nil
+	}
+}
+
+// GetOsdAddr returns the address at which the OSD with the given id serves
+// clients.
+func (mount *MountInfo) GetOsdAddr(osd int) (*net.TCPAddr, error) {
+	var c_addr C.struct_sockaddr_storage
+	ret := C.ceph_get_osd_addr(mount.mount, C.int(osd), &c_addr)
+	if ret < 0 {
+		return nil, CephError(ret)
+	}
+
+	switch c_addr.ss_family {
+	case C.AF_INET:
+		c_in := (*C.struct_sockaddr_in)(unsafe.Pointer(&c_addr))
+		ip := (*[net.IPv4len]byte)(unsafe.Pointer(&c_in.sin_addr))
+		return &net.TCPAddr{
+			IP:   net.IP(append([]byte(nil), ip[:]...)),
+			Port: networkPort(unsafe.Pointer(&c_in.sin_port)),
+		}, nil
+	case C.AF_INET6:
+		c_in6 := (*C.struct_sockaddr_in6)(unsafe.Pointer(&c_addr))
+		ip := (*[net.IPv6len]byte)(unsafe.Pointer(&c_in6.sin6_addr))
+		return &net.TCPAddr{
+			IP:   net.IP(append([]byte(nil), ip[:]...)),
+			Port: networkPort(unsafe.Pointer(&c_in6.sin6_port)),
+		}, nil
+	}
+	return nil, CephError(-C.EAFNOSUPPORT)
+}
+
+// networkPort converts a port in network byte order.
+func networkPort(p unsafe.Pointer) int {
+	b := (*[2]byte)(p)
+	return int(b[0])<<8 | int(b[1])
+}
@@ -0,0 +1,123 @@
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <stdlib.h>
+#include <cephfs/libcephfs.h>
+*/
+import "C"
+
+import (
+	"unsafe"
+)
+
+// Unlink removes the file at path. Open handles of the file stay usable
+// until they are closed.
+func (mount *MountInfo) Unlink(path string) error {
+	c_path := C.CString(path)
+	defer C.free(unsafe.Pointer(c_path))
+
+	return getError(C.ceph_unlink(mount.mount, c_path))
+}
+
+// Rename renames from to to. As with rename(2), an existing file at to is
+// atomically replaced, and an existing directory at to is replaced only if
+// from is a directory too and to is empty.
+func (mount *MountInfo) Rename(from, to string) error {
+	c_from := C.CString(from)
+	defer C.free(unsafe.Pointer(c_from))
+	c_to := C.CString(to)
+	defer C.free(unsafe.Pointer(c_to))
+
+	return getError(C.ceph_rename(mount.mount, c_from, c_to))
+}
+
+// Link creates newname as a hard link to the existing file.
+func (mount *MountInfo) Link(existing, newname string) error {
+	c_existing := C.CString(existing)
+	defer C.free(unsafe.Pointer(c_existing))
+	c_newname := C.CString(newname)
+	defer C.free(unsafe.Pointer(c_newname))
+
+	return getError(C.ceph_link(mount.mount, c_existing, c_newname))
+}
+
+// Symlink creates newname as a symbolic link to target. The target does not
+// need to exist.
+func (mount *MountInfo) Symlink(target, newname string) error {
+	c_target := C.CString(target)
+	defer C.free(unsafe.Pointer(c_target))
+	c_newname := C.CString(newname)
+	defer C.free(unsafe.Pointer(c_newname))
+
+	return getError(C.ceph_symlink(mount.mount, c_target, c_newname))
+}
+
+// Readlink returns the target of the symbolic link at path.
+func (mount *MountInfo) Readlink(path string) (string, error) {
+	c_path := C.CString(path)
+	defer C.free(unsafe.Pointer(c_path))
+
+	buf := make([]byte, 256)
+	for {
+		ret := C.ceph_readlink(mount.mount, c_path,
+			(*C.char)(unsafe.Pointer(&buf[0])), C.int64_t(len(buf)))
+		if ret < 0 {
+			return "", CephError(ret)
+		}
+		// the target may have been truncated
+		if int(ret) == len(buf) {
+			buf = make([]byte, len(buf)*2)
+			continue
+		}
+
+		return string(buf[:ret]), nil
+	}
+}
+
+// Chmod changes the permissions of the file at path to mode.
+func (mount *MountInfo) Chmod(path string, mode uint32) error {
+	c_path := C.CString(path)
+	defer C.free(unsafe.Pointer(c_path))
+
+	return getError(C.ceph_chmod(mount.mount, c_path, C.mode_t(mode)))
+}
+
+// Chown changes the owner and group of the file at path, following symbolic
+// links. A uid or gid of -1 leaves that value unchanged.
+func (mount *MountInfo) Chown(path string, uid, gid int) error {
+	c_path := C.CString(path)
+	defer C.free(unsafe.Pointer(c_path))
+
+	return getError(C.ceph_chown(mount.mount, c_path, C.int(uid), C.int(gid)))
+}
+
+// Lchown changes the owner and group of the file at path like Chown, but
+// changes a symbolic link itself rather than its target.
+func (mount *MountInfo) Lchown(path string, uid, gid int) error {
+	c_path := C.CString(path)
+	defer C.free(unsafe.Pointer(c_path))
+
+	return getError(C.ceph_lchown(mount.mount, c_path, C.int(uid), C.int(gid)))
+}
+
+// Truncate changes the size of the file at path. A file that is extended
+// reads as zeros beyond its previous end.
+func (mount *MountInfo) Truncate(path string, size int64) error {
+	c_path := C.CString(path)
+	defer C.free(unsafe.Pointer(c_path))
+
+	return getError(C.ceph_truncate(mount.mount, c_path, C.int64_t(size)))
+}
+
+// Mknod creates a file system node at path. mode holds both the permissions
+// and the type of the node, such as syscall.S_IFIFO or syscall.S_IFCHR. rdev
+// is the device number of character and block devices.
+func (mount *MountInfo) Mknod(path string, mode uint32, rdev uint64) error {
+	c_path := C.CString(path)
+	defer C.free(unsafe.Pointer(c_path))
+
+	return getError(C.ceph_mknod(mount.mount, c_path, C.mode_t(mode),
+		C.dev_t(rdev)))
+}
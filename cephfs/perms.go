@@ -0,0 +1,68 @@
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <stdlib.h>
+#include <cephfs/libcephfs.h>
+*/
+import "C"
+
+import (
+	"unsafe"
+)
+
+// UserPerm holds the credentials, a uid, a gid and supplementary groups,
+// that CephFS checks permissions against. A UserPerm created with
+// NewUserPerm must be released with Destroy.
+type UserPerm struct {
+	perm    *C.UserPerm
+	gidList *C.gid_t
+	managed bool
+}
+
+// NewUserPerm returns the credentials of the user uid with the primary group
+// gid and the supplementary groups gids.
+func NewUserPerm(uid, gid int, gids []int) *UserPerm {
+	p := &UserPerm{managed: true}
+	// the list is referenced, not copied, so it has to live in C memory
+	if len(gids) > 0 {
+		p.gidList = (*C.gid_t)(C.malloc(C.size_t(len(gids)) *
+			C.size_t(unsafe.Sizeof(C.gid_t(0)))))
+		list := (*[1 << 20]C.gid_t)(unsafe.Pointer(p.gidList))[:len(gids):len(gids)]
+		for i, g := range gids {
+			list[i] = C.gid_t(g)
+		}
+	}
+	p.perm = C.ceph_userperm_new(C.uid_t(uid), C.gid_t(gid),
+		C.int(len(gids)), p.gidList)
+	return p
+}
+
+// Destroy releases the credentials. It does nothing for the credentials
+// returned by MountPerms, which belong to the mount.
+func (p *UserPerm) Destroy() {
+	if !p.managed || p.perm == nil {
+		return
+	}
+	C.ceph_userperm_destroy(p.perm)
+	p.perm = nil
+	if p.gidList != nil {
+		C.free(unsafe.Pointer(p.gidList))
+		p.gidList = nil
+	}
+}
+
+// SetMountPerms sets the credentials that all operations through the mount
+// are performed with. It must be called before the mount is mounted. The
+// mount keeps a copy, so perm may be destroyed afterwards.
+func (mount *MountInfo) SetMountPerms(perm *UserPerm) error {
+	return getError(C.ceph_mount_perms_set(mount.mount, perm.perm))
+}
+
+// MountPerms returns the credentials that operations through the mount are
+// performed with. They belong to the mount and stay valid until it is
+// released.
+func (mount *MountInfo) MountPerms() *UserPerm {
+	return &UserPerm{perm: C.ceph_mount_perms(mount.mount)}
+}
@@ -0,0 +1,71 @@
+package cephfs
+
+import (
+	"strconv"
+	"strings"
+)
+
+// PinNone is the export pin of a directory that is not pinned to an MDS
+// rank and inherits the pin of its parent.
+const PinNone = -1
+
+// GetExportPin returns the MDS rank the directory at path is pinned to, or
+// PinNone.
+func (mount *MountInfo) GetExportPin(path string) (int, error) {
+	value, err := mount.getXattrString(path, "ceph.dir.pin")
+	if err != nil {
+		return 0, err
+	} else if value == "" {
+		return PinNone, nil
+	}
+	return strconv.Atoi(strings.TrimSpace(value))
+}
+
+// SetExportPin pins the directory at path and the tree below it to the MDS
+// rank, so that its metadata is always served by that rank. PinNone removes
+// the pin.
+func (mount *MountInfo) SetExportPin(path string, rank int) error {
+	return mount.SetXattr(path, "ceph.dir.pin",
+		[]byte(strconv.Itoa(rank)), XattrDefault)
+}
+
+// GetDistributedPin reports whether the immediate subdirectories of the
+// directory at path are pinned across all active MDS ranks.
+func (mount *MountInfo) GetDistributedPin(path string) (bool, error) {
+	value, err := mount.getXattrString(path, "ceph.dir.pin.distributed")
+	if err != nil || value == "" {
+		return false, err
+	}
+	return strconv.ParseBool(strings.TrimSpace(value))
+}
+
+// SetDistributedPin enables or disables distributing the immediate
+// subdirectories of the directory at path across all active MDS ranks,
+// for example for a directory holding the home directories of many users.
+func (mount *MountInfo) SetDistributedPin(path string, enable bool) error {
+	value := "0"
+	if enable {
+		value = "1"
+	}
+	return mount.SetXattr(path, "ceph.dir.pin.distributed", []byte(value),
+		XattrDefault)
+}
+
+// GetRandomPin returns the probability with which the subdirectories below
+// the directory at path are pinned to a random MDS rank.
+func (mount *MountInfo) GetRandomPin(path string) (float64, error) {
+	value, err := mount.getXattrString(path, "ceph.dir.pin.random")
+	if err != nil || value == "" {
+		return 0, err
+	}
+	return strconv.ParseFloat(strings.TrimSpace(value), 64)
+}
+
+// SetRandomPin sets the probability, between 0 and the
+// mds_export_ephemeral_random_max option, with which the subdirectories
+// below the directory at path are pinned to a random MDS rank. 0 disables
+// random pinning.
+func (mount *MountInfo) SetRandomPin(path string, probability float64) error {
+	return mount.SetXattr(path, "ceph.dir.pin.random",
+		[]byte(strconv.FormatFloat(probability, 'f', -1, 64)), XattrDefault)
+}
@@ -0,0 +1,95 @@
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <errno.h>
+#include <cephfs/libcephfs.h>
+*/
+import "C"
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Quota holds the limits of a directory tree. A limit of zero means the
+// tree is not limited.
+type Quota struct {
+	MaxBytes uint64
+	MaxFiles uint64
+}
+
+// QuotaUsage holds the recursive usage of a directory tree, which counts
+// against its quota.
+type QuotaUsage struct {
+	Bytes uint64
+	Files uint64
+}
+
+// getXattrUint64 reads a virtual xattr holding a decimal number. An attribute
+// that is not set reads as zero.
+func (mount *MountInfo) getXattrUint64(path, name string) (uint64, error) {
+	value, err := mount.GetXattr(path, name)
+	if err == CephError(-C.ENODATA) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseUint(strings.TrimSpace(string(value)), 10, 64)
+}
+
+func (mount *MountInfo) setXattrUint64(path, name string, value uint64) error {
+	return mount.SetXattr(path, name,
+		[]byte(strconv.FormatUint(value, 10)), XattrDefault)
+}
+
+// GetQuota returns the quota of the directory at path.
+func (mount *MountInfo) GetQuota(path string) (*Quota, error) {
+	maxBytes, err := mount.getXattrUint64(path, "ceph.quota.max_bytes")
+	if err != nil {
+		return nil, err
+	}
+
+	maxFiles, err := mount.getXattrUint64(path, "ceph.quota.max_files")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Quota{
+		MaxBytes: maxBytes,
+		MaxFiles: maxFiles,
+	}, nil
+}
+
+// SetQuota sets the quota of the directory at path. Quotas are enforced by
+// the clients, so writes may exceed a limit briefly before they are stopped.
+func (mount *MountInfo) SetQuota(path string, quota *Quota) error {
+	err := mount.setXattrUint64(path, "ceph.quota.max_bytes", quota.MaxBytes)
+	if err != nil {
+		return err
+	}
+
+	return mount.setXattrUint64(path, "ceph.quota.max_files", quota.MaxFiles)
+}
+
+// GetQuotaUsage returns the number of bytes and files below the directory at
+// path. The MDS propagates these statistics lazily, so recent changes may not
+// be included yet.
+func (mount *MountInfo) GetQuotaUsage(path string) (*QuotaUsage, error) {
+	bytes, err := mount.getXattrUint64(path, "ceph.dir.rbytes")
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := mount.getXattrUint64(path, "ceph.dir.rfiles")
+	if err != nil {
+		return nil, err
+	}
+
+	return &QuotaUsage{
+		Bytes: bytes,
+		Files: files,
+	}, nil
+}
@@ -0,0 +1,72 @@
+package cephfs
+
+import (
+	"strconv"
+	"strings"
+)
+
+// DirStats holds the statistics the MDS keeps for a directory. The
+// recursive statistics cover the whole tree below the directory and are
+// propagated lazily, so recent changes may not be included yet.
+type DirStats struct {
+	// Files and Subdirs count the entries of the directory itself.
+	Files   uint64
+	Subdirs uint64
+	// RFiles, RSubdirs and RBytes count the files, directories and bytes
+	// of the whole tree.
+	RFiles   uint64
+	RSubdirs uint64
+	RBytes   uint64
+	// RCtime is the latest ctime of any file or directory of the tree.
+	RCtime Timespec
+}
+
+// GetDirStats returns the statistics of the directory at path without
+// walking the tree below it.
+func (mount *MountInfo) GetDirStats(path string) (*DirStats, error) {
+	var err error
+	stats := &DirStats{}
+	counters := []struct {
+		name  string
+		value *uint64
+	}{
+		{"ceph.dir.files", &stats.Files},
+		{"ceph.dir.subdirs", &stats.Subdirs},
+		{"ceph.dir.rfiles", &stats.RFiles},
+		{"ceph.dir.rsubdirs", &stats.RSubdirs},
+		{"ceph.dir.rbytes", &stats.RBytes},
+	}
+	for _, c := range counters {
+		if *c.value, err = mount.getXattrUint64(path, c.name); err != nil {
+			return nil, err
+		}
+	}
+
+	rctime, err := mount.getXattrString(path, "ceph.dir.rctime")
+	if err != nil {
+		return nil, err
+	}
+	if stats.RCtime, err = parseRctime(rctime); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// parseRctime parses the value of the ceph.dir.rctime vxattr, which holds
+// the seconds and nanoseconds separated by a dot.
+func parseRctime(value string) (Timespec, error) {
+	sec, nsec := strings.TrimSpace(value), "0"
+	if i := strings.IndexByte(sec, '.'); i >= 0 {
+		sec, nsec = sec[:i], sec[i+1:]
+	}
+
+	var ts Timespec
+	var err error
+	if ts.Sec, err = strconv.ParseInt(sec, 10, 64); err != nil {
+		return Timespec{}, err
+	}
+	if ts.Nsec, err = strconv.ParseInt(nsec, 10, 64); err != nil {
+		return Timespec{}, err
+	}
+	return ts, nil
+}
@@ -0,0 +1,116 @@
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <errno.h>
+#include <cephfs/libcephfs.h>
+*/
+import "C"
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// InstanceId returns the global id of the client, which is also the id of
+// its sessions with the MDS daemons. It is only valid while mounted.
+func (mount *MountInfo) InstanceId() uint64 {
+	return uint64(C.ceph_get_instance_id(mount.mount))
+}
+
+// SetSessionTimeout sets the time after which the MDS daemons consider the
+// client dead if it stops responding, and may evict it. It must be called
+// before Mount.
+func (mount *MountInfo) SetSessionTimeout(timeout time.Duration) error {
+	return getError(C.ceph_set_session_timeout(mount.mount,
+		C.uint(timeout/time.Second)))
+}
+
+// SetClientMetadata sets metadata that the client reports to the MDS
+// daemons when it opens its sessions, in addition to the hostname and
+// entity id reported by default. It must be called before Mount. Keys and
+// values must not contain "=" or ",".
+func (mount *MountInfo) SetClientMetadata(metadata map[string]string) error {
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = key + "=" + metadata[key]
+	}
+	return mount.SetConfigOption("client_metadata", strings.Join(pairs, ","))
+}
+
+// SessionInfo describes the session of a client with an MDS daemon, as
+// listed by the "session ls" MDS command.
+type SessionInfo struct {
+	Id                uint64                 `json:"id"`
+	State             string                 `json:"state"`
+	NumLeases         int                    `json:"num_leases"`
+	NumCaps           int                    `json:"num_caps"`
+	RequestLoadAvg    int                    `json:"request_load_avg"`
+	Uptime            float64                `json:"uptime"`
+	ReplayRequests    int                    `json:"replay_requests"`
+	CompletedRequests int                    `json:"completed_requests"`
+	Reconnecting      bool                   `json:"reconnecting"`
+	Inst              string                 `json:"inst"`
+	ClientMetadata    map[string]interface{} `json:"client_metadata"`
+}
+
+// ListSessions returns the client sessions of the MDS daemons selected by
+// mdsSpec, as accepted by MdsCommand.
+func (mount *MountInfo) ListSessions(mdsSpec string) ([]SessionInfo, error) {
+	cmd, err := json.Marshal(map[string]string{
+		"prefix": "session ls",
+		"format": "json",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	buf, _, err := mount.MdsCommand(mdsSpec, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	// with several daemons selected, their lists are concatenated
+	sessions := []SessionInfo{}
+	decoder := json.NewDecoder(bytes.NewReader(buf))
+	for {
+		var list []SessionInfo
+		err := decoder.Decode(&list)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, list...)
+	}
+	return sessions, nil
+}
+
+// OwnSessions returns the sessions of this client with the MDS daemons
+// selected by mdsSpec.
+func (mount *MountInfo) OwnSessions(mdsSpec string) ([]SessionInfo, error) {
+	sessions, err := mount.ListSessions(mdsSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	id := mount.InstanceId()
+	own := []SessionInfo{}
+	for _, session := range sessions {
+		if session.Id == id {
+			own = append(own, session)
+		}
+	}
+	return own, nil
+}
@@ -0,0 +1,186 @@
+package cephfs
+
+import (
+	"path"
+	"sort"
+	"syscall"
+)
+
+// SnapDiffType is the kind of change of a SnapDiffEntry.
+type SnapDiffType int
+
+const (
+	SnapDiffCreated SnapDiffType = iota
+	SnapDiffModified
+	SnapDiffDeleted
+)
+
+// SnapDiffEntry is a change between two states of a directory tree. Path is
+// relative to the directory that is compared.
+type SnapDiffEntry struct {
+	Path  string
+	Type  SnapDiffType
+	IsDir bool
+}
+
+// SnapDiffFunc is called by SnapDiff for every change. Returning an error
+// stops the comparison and makes SnapDiff return it.
+type SnapDiffFunc func(entry *SnapDiffEntry) error
+
+// SnapDiff compares the tree below the directory dir in the snapshot
+// fromSnap with the tree in the snapshot toSnap, or with the live tree if
+// toSnap is empty, and calls fn for every entry that was created, modified
+// or deleted in between.
+//
+// A created directory is reported along with everything below it, while a
+// deleted directory is reported alone. A directory whose entries changed is
+// reported as modified. Subtrees whose recursive ctime (ceph.dir.rctime) did
+// not change are not read, so the cost depends on the amount of change
+// rather than on the size of the tree.
+func (mount *MountInfo) SnapDiff(dir, fromSnap, toSnap string, fn SnapDiffFunc) error {
+	fromRoot := path.Join(dir, SnapDir, fromSnap)
+	toRoot := dir
+	if toSnap != "" {
+		toRoot = path.Join(dir, SnapDir, toSnap)
+	}
+	return mount.snapDiffDir(fromRoot, toRoot, "", fn)
+}
+
+func (mount *MountInfo) snapDiffDir(fromRoot, toRoot, rel string, fn SnapDiffFunc) error {
+	fromEntries, err := mount.readDirStatx(path.Join(fromRoot, rel))
+	if err != nil {
+		return err
+	}
+	toEntries, err := mount.readDirStatx(path.Join(toRoot, rel))
+	if err != nil {
+		return err
+	}
+
+	for _, name := range sortedNames(fromEntries) {
+		from := fromEntries[name]
+		to, ok := toEntries[name]
+		if ok && to.Inode == from.Inode && isDir(to) == isDir(from) {
+			continue
+		}
+		err := fn(&SnapDiffEntry{
+			Path:  path.Join(rel, name),
+			Type:  SnapDiffDeleted,
+			IsDir: isDir(from),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, name := range sortedNames(toEntries) {
+		to := toEntries[name]
+		entryRel := path.Join(rel, name)
+		from, ok := fromEntries[name]
+		if !ok || to.Inode != from.Inode || isDir(to) != isDir(from) {
+			if err := mount.snapDiffCreated(toRoot, entryRel, to, fn); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if to.Ctime != from.Ctime {
+			err := fn(&SnapDiffEntry{
+				Path:  entryRel,
+				Type:  SnapDiffModified,
+				IsDir: isDir(to),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if isDir(to) {
+			changed, err := mount.rctimeChanged(path.Join(fromRoot, entryRel),
+				path.Join(toRoot, entryRel))
+			if err != nil {
+				return err
+			}
+			if changed {
+				if err := mount.snapDiffDir(fromRoot, toRoot, entryRel, fn); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// snapDiffCreated reports the entry at rel and, for a directory, everything
+// below it as created.
+func (mount *MountInfo) snapDiffCreated(root, rel string, stx *CephStatx, fn SnapDiffFunc) error {
+	err := fn(&SnapDiffEntry{
+		Path:  rel,
+		Type:  SnapDiffCreated,
+		IsDir: isDir(stx),
+	})
+	if err != nil || !isDir(stx) {
+		return err
+	}
+
+	entries, err := mount.readDirStatx(path.Join(root, rel))
+	if err != nil {
+		return err
+	}
+	for _, name := range sortedNames(entries) {
+		err := mount.snapDiffCreated(root, path.Join(rel, name), entries[name], fn)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (mount *MountInfo) rctimeChanged(fromPath, toPath string) (bool, error) {
+	from, err := mount.getXattrString(fromPath, "ceph.dir.rctime")
+	if err != nil {
+		return false, err
+	}
+	to, err := mount.getXattrString(toPath, "ceph.dir.rctime")
+	if err != nil {
+		return false, err
+	}
+	return from != to, nil
+}
+
+// readDirStatx returns the attributes of the entries of the directory at
+// path by name, without the "." and ".." entries.
+func (mount *MountInfo) readDirStatx(path string) (map[string]*CephStatx, error) {
+	dir, err := mount.OpenDir(path)
+	if err != nil {
+		return nil, err
+	}
+	defer dir.Close()
+
+	entries := map[string]*CephStatx{}
+	for {
+		entry, err := dir.ReadDirPlus(StatxMode|StatxIno|StatxCtime,
+			AtSymlinkNofollow)
+		if err != nil {
+			return nil, err
+		} else if entry == nil {
+			return entries, nil
+		}
+
+		if entry.Name != "." && entry.Name != ".." {
+			entries[entry.Name] = entry.Statx
+		}
+	}
+}
+
+func sortedNames(entries map[string]*CephStatx) []string {
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func isDir(stx *CephStatx) bool {
+	return uint32(stx.Mode)&syscall.S_IFMT == syscall.S_IFDIR
+}
@@ -0,0 +1,55 @@
+package cephfs
+
+import (
+	"path"
+)
+
+// SnapDir is the name of the virtual directory that holds the snapshots of
+// a directory. It can be changed with the client_snapdir option.
+const SnapDir = ".snap"
+
+// SnapshotInfo describes a snapshot of a directory.
+type SnapshotInfo struct {
+	Name  string
+	Ctime Timespec
+}
+
+// CreateSnapshot takes a snapshot of the directory dir and everything below
+// it. Snapshots must be allowed on the file system.
+func (mount *MountInfo) CreateSnapshot(dir, name string) error {
+	return mount.MakeDir(path.Join(dir, SnapDir, name), 0755)
+}
+
+// RemoveSnapshot removes the snapshot name of the directory dir.
+func (mount *MountInfo) RemoveSnapshot(dir, name string) error {
+	return mount.RemoveDir(path.Join(dir, SnapDir, name))
+}
+
+// ListSnapshots returns the snapshots of the directory dir. Snapshots that
+// were taken of a parent directory are listed as "_<name>_<inode>".
+func (mount *MountInfo) ListSnapshots(dir string) ([]SnapshotInfo, error) {
+	snapDir, err := mount.OpenDir(path.Join(dir, SnapDir))
+	if err != nil {
+		return nil, err
+	}
+	defer snapDir.Close()
+
+	snaps := []SnapshotInfo{}
+	for {
+		entry, err := snapDir.ReadDirPlus(StatxCtime, 0)
+		if err != nil {
+			return nil, err
+		} else if entry == nil {
+			break
+		}
+
+		if entry.Name == "." || entry.Name == ".." {
+			continue
+		}
+		snaps = append(snaps, SnapshotInfo{
+			Name:  entry.Name,
+			Ctime: entry.Statx.Ctime,
+		})
+	}
+	return snaps, nil
+}
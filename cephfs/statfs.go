@@ -0,0 +1,59 @@
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <stdlib.h>
+#include <sys/statvfs.h>
+#include <cephfs/libcephfs.h>
+*/
+import "C"
+
+import (
+	"unsafe"
+)
+
+// CephStatVFS describes the capacity and usage of a file system, like
+// struct statvfs. Block counts are in units of Frsize bytes.
+type CephStatVFS struct {
+	Bsize   uint64
+	Frsize  uint64
+	Blocks  uint64
+	Bfree   uint64
+	Bavail  uint64
+	Files   uint64
+	Ffree   uint64
+	Favail  uint64
+	Fsid    uint64
+	Flag    uint64
+	Namemax uint64
+}
+
+// Statfs returns the capacity and usage of the file system containing path.
+// If path is in a directory tree with a byte quota, the capacity of the
+// quota is reported instead of that of the whole file system, as long as the
+// client_quota_df option is enabled.
+func (mount *MountInfo) Statfs(path string) (*CephStatVFS, error) {
+	c_path := C.CString(path)
+	defer C.free(unsafe.Pointer(c_path))
+
+	var stbuf C.struct_statvfs
+	ret := C.ceph_statfs(mount.mount, c_path, &stbuf)
+	if ret < 0 {
+		return nil, CephError(ret)
+	}
+
+	return &CephStatVFS{
+		Bsize:   uint64(stbuf.f_bsize),
+		Frsize:  uint64(stbuf.f_frsize),
+		Blocks:  uint64(stbuf.f_blocks),
+		Bfree:   uint64(stbuf.f_bfree),
+		Bavail:  uint64(stbuf.f_bavail),
+		Files:   uint64(stbuf.f_files),
+		Ffree:   uint64(stbuf.f_ffree),
+		Favail:  uint64(stbuf.f_favail),
+		Fsid:    uint64(stbuf.f_fsid),
+		Flag:    uint64(stbuf.f_flag),
+		Namemax: uint64(stbuf.f_namemax),
+	}, nil
+}
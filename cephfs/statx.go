@@ -0,0 +1,134 @@
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <stdlib.h>
+#include <fcntl.h>
+#include <cephfs/libcephfs.h>
+
+// the value of AT_STATX_FORCE_SYNC on Linux, for C libraries without it
+#ifndef AT_STATX_FORCE_SYNC
+#define AT_STATX_FORCE_SYNC 0x2000
+#endif
+*/
+import "C"
+
+import (
+	"unsafe"
+)
+
+// StatxMask selects the fields of a CephStatx that are wanted or valid.
+type StatxMask uint32
+
+const (
+	StatxMode       = StatxMask(C.CEPH_STATX_MODE)
+	StatxNlink      = StatxMask(C.CEPH_STATX_NLINK)
+	StatxUid        = StatxMask(C.CEPH_STATX_UID)
+	StatxGid        = StatxMask(C.CEPH_STATX_GID)
+	StatxRdev       = StatxMask(C.CEPH_STATX_RDEV)
+	StatxAtime      = StatxMask(C.CEPH_STATX_ATIME)
+	StatxMtime      = StatxMask(C.CEPH_STATX_MTIME)
+	StatxCtime      = StatxMask(C.CEPH_STATX_CTIME)
+	StatxIno        = StatxMask(C.CEPH_STATX_INO)
+	StatxSize       = StatxMask(C.CEPH_STATX_SIZE)
+	StatxBlocks     = StatxMask(C.CEPH_STATX_BLOCKS)
+	StatxBasicStats = StatxMask(C.CEPH_STATX_BASIC_STATS)
+	StatxBtime      = StatxMask(C.CEPH_STATX_BTIME)
+	StatxVersion    = StatxMask(C.CEPH_STATX_VERSION)
+	StatxAllStats   = StatxMask(C.CEPH_STATX_ALL_STATS)
+)
+
+// AtFlags modify how a path is resolved and how attributes are fetched.
+type AtFlags uint
+
+const (
+	// AtNoAttrSync allows returning cached attributes that may be stale
+	// instead of fetching them from the MDS.
+	AtNoAttrSync = AtFlags(C.AT_NO_ATTR_SYNC)
+	// AtStatxForceSync fetches the attributes from the MDS even when the
+	// client holds capabilities that would let it use its cached ones.
+	AtStatxForceSync = AtFlags(C.AT_STATX_FORCE_SYNC)
+	// AtSymlinkNofollow returns the attributes of a symbolic link rather
+	// than those of its target.
+	AtSymlinkNofollow = AtFlags(C.AT_SYMLINK_NOFOLLOW)
+	// AtRemoveDir makes File.UnlinkAt remove a directory instead of a
+	// file.
+	AtRemoveDir = AtFlags(C.AT_REMOVEDIR)
+)
+
+// Timespec is a time with nanosecond precision.
+type Timespec struct {
+	Sec  int64
+	Nsec int64
+}
+
+// CephStatx holds the attributes of a file. Only the fields selected by
+// Mask are valid.
+type CephStatx struct {
+	Mask    StatxMask
+	Blksize uint32
+	Nlink   uint32
+	Uid     uint32
+	Gid     uint32
+	Mode    uint16
+	Inode   uint64
+	Size    uint64
+	Blocks  uint64
+	Dev     uint64
+	Rdev    uint64
+	Atime   Timespec
+	Ctime   Timespec
+	Mtime   Timespec
+	Btime   Timespec
+	Version uint64
+}
+
+func newTimespec(ts C.struct_timespec) Timespec {
+	return Timespec{Sec: int64(ts.tv_sec), Nsec: int64(ts.tv_nsec)}
+}
+
+func newCephStatx(stx *C.struct_ceph_statx) *CephStatx {
+	return &CephStatx{
+		Mask:    StatxMask(stx.stx_mask),
+		Blksize: uint32(stx.stx_blksize),
+		Nlink:   uint32(stx.stx_nlink),
+		Uid:     uint32(stx.stx_uid),
+		Gid:     uint32(stx.stx_gid),
+		Mode:    uint16(stx.stx_mode),
+		Inode:   uint64(stx.stx_ino),
+		Size:    uint64(stx.stx_size),
+		Blocks:  uint64(stx.stx_blocks),
+		Dev:     uint64(stx.stx_dev),
+		Rdev:    uint64(stx.stx_rdev),
+		Atime:   newTimespec(stx.stx_atime),
+		Ctime:   newTimespec(stx.stx_ctime),
+		Mtime:   newTimespec(stx.stx_mtime),
+		Btime:   newTimespec(stx.stx_btime),
+		Version: uint64(stx.stx_version),
+	}
+}
+
+// Statx returns the attributes selected by want of the file at path. Symbolic
+// links are followed unless flags contains AtSymlinkNofollow.
+func (mount *MountInfo) Statx(path string, want StatxMask, flags AtFlags) (*CephStatx, error) {
+	c_path := C.CString(path)
+	defer C.free(unsafe.Pointer(c_path))
+
+	var stx C.struct_ceph_statx
+	ret := C.ceph_statx(mount.mount, c_path, &stx, C.uint(want), C.uint(flags))
+	if ret < 0 {
+		return nil, CephError(ret)
+	}
+	return newCephStatx(&stx), nil
+}
+
+// Fstatx returns the attributes selected by want of the open file.
+func (f *File) Fstatx(want StatxMask, flags AtFlags) (*CephStatx, error) {
+	var stx C.struct_ceph_statx
+	ret := C.ceph_fstatx(f.mount.mount, f.fd, &stx, C.uint(want), C.uint(flags))
+	if ret < 0 {
+		return nil, CephError(ret)
+	}
+	return newCephStatx(&stx), nil
+}
@@ -0,0 +1,89 @@
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <stdlib.h>
+#include <fcntl.h>
+#include <sys/stat.h>
+#include <cephfs/libcephfs.h>
+*/
+import "C"
+
+import (
+	"unsafe"
+)
+
+// Special values of Timespec.Nsec for the time setting functions, with the
+// same meaning as for utimensat(2).
+const (
+	// UtimeNow sets the time to the current time.
+	UtimeNow = int64(C.UTIME_NOW)
+	// UtimeOmit leaves the time unchanged.
+	UtimeOmit = int64(C.UTIME_OMIT)
+)
+
+// timesAttr returns the statx and setattr mask that set the access and
+// modification times to atime and mtime.
+func timesAttr(atime, mtime Timespec) (C.struct_ceph_statx, C.int) {
+	var stx C.struct_ceph_statx
+	var mask C.int
+
+	switch atime.Nsec {
+	case UtimeOmit:
+	case UtimeNow:
+		mask |= C.CEPH_SETATTR_ATIME | C.CEPH_SETATTR_ATIME_NOW
+	default:
+		stx.stx_atime.tv_sec = C.time_t(atime.Sec)
+		stx.stx_atime.tv_nsec = C.long(atime.Nsec)
+		mask |= C.CEPH_SETATTR_ATIME
+	}
+
+	switch mtime.Nsec {
+	case UtimeOmit:
+	case UtimeNow:
+		mask |= C.CEPH_SETATTR_MTIME | C.CEPH_SETATTR_MTIME_NOW
+	default:
+		stx.stx_mtime.tv_sec = C.time_t(mtime.Sec)
+		stx.stx_mtime.tv_nsec = C.long(mtime.Nsec)
+		mask |= C.CEPH_SETATTR_MTIME
+	}
+
+	return stx, mask
+}
+
+func (mount *MountInfo) setTimes(path string, atime, mtime Timespec,
+	flags AtFlags) error {
+	c_path := C.CString(path)
+	defer C.free(unsafe.Pointer(c_path))
+
+	stx, mask := timesAttr(atime, mtime)
+	if mask == 0 {
+		return nil
+	}
+	return getError(C.ceph_setattrx(mount.mount, c_path, &stx, mask,
+		C.int(flags)))
+}
+
+// Utimes sets the access and modification times of the file at path with
+// nanosecond precision, following symbolic links. A time whose Nsec is
+// UtimeNow or UtimeOmit is set to the current time or left unchanged.
+func (mount *MountInfo) Utimes(path string, atime, mtime Timespec) error {
+	return mount.setTimes(path, atime, mtime, 0)
+}
+
+// Lutimes sets the times of the file at path like Utimes, but changes a
+// symbolic link itself rather than its target.
+func (mount *MountInfo) Lutimes(path string, atime, mtime Timespec) error {
+	return mount.setTimes(path, atime, mtime, AtSymlinkNofollow)
+}
+
+// Futimens sets the access and modification times of the open file like
+// Utimes.
+func (f *File) Futimens(atime, mtime Timespec) error {
+	stx, mask := timesAttr(atime, mtime)
+	if mask == 0 {
+		return nil
+	}
+	return getError(C.ceph_fsetattrx(f.mount.mount, f.fd, &stx, mask))
+}
@@ -0,0 +1,160 @@
+package cephfs
+
+import (
+	"io/fs"
+	"path"
+	"sync"
+)
+
+// WalkDirOptions control how WalkDirWithOptions walks a tree.
+type WalkDirOptions struct {
+	// Concurrency is the number of directories that are read at the same
+	// time. With a value above 1, the function is called from several
+	// goroutines at once and the order of the calls is not defined.
+	Concurrency int
+}
+
+// WalkDir walks the tree at root like filepath.WalkDir, calling fn for every
+// file and directory in lexical order. The entries are read together with
+// their attributes, so calling Info on them needs no further requests.
+func (mount *MountInfo) WalkDir(root string, fn fs.WalkDirFunc) error {
+	return mount.WalkDirWithOptions(root, nil, fn)
+}
+
+// WalkDirWithOptions walks the tree at root like WalkDir, with the options
+// in opts. fs.SkipDir and fs.SkipAll work as in WalkDir.
+func (mount *MountInfo) WalkDirWithOptions(root string, opts *WalkDirOptions, fn fs.WalkDirFunc) error {
+	stx, err := mount.Statx(root, StatxBasicStats, AtSymlinkNofollow)
+	if err != nil {
+		err = fn(root, nil, err)
+	} else {
+		d := fs.FileInfoToDirEntry(newFileInfo(path.Base(root), stx))
+		w := &walker{mount: mount, fn: fn}
+		if opts != nil && opts.Concurrency > 1 {
+			w.sem = make(chan struct{}, opts.Concurrency-1)
+			w.walkConcurrent(root, d)
+			w.wg.Wait()
+			err = w.err
+		} else {
+			err = w.walk(root, d)
+		}
+	}
+	if err == fs.SkipDir || err == fs.SkipAll {
+		return nil
+	}
+	return err
+}
+
+type walker struct {
+	mount *MountInfo
+	fn    fs.WalkDirFunc
+
+	// used by concurrent walks only
+	sem     chan struct{}
+	wg      sync.WaitGroup
+	mutex   sync.Mutex
+	stopped bool
+	err     error
+}
+
+func (w *walker) walk(p string, d fs.DirEntry) error {
+	if err := w.fn(p, d, nil); err != nil || !d.IsDir() {
+		if err == fs.SkipDir && d.IsDir() {
+			err = nil
+		}
+		return err
+	}
+
+	entries, err := w.mount.readDirSorted(p)
+	if err != nil {
+		// the directory is reported a second time with the error
+		err = w.fn(p, d, err)
+		if err != nil {
+			if err == fs.SkipDir {
+				err = nil
+			}
+			return err
+		}
+	}
+
+	for _, entry := range entries {
+		if err := w.walk(path.Join(p, entry.Name()), entry); err != nil {
+			if err == fs.SkipDir {
+				break
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// stop ends a concurrent walk with err, unless it has already ended.
+func (w *walker) stop(err error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if !w.stopped {
+		w.stopped = true
+		w.err = err
+	}
+}
+
+func (w *walker) isStopped() bool {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.stopped
+}
+
+func (w *walker) walkConcurrent(p string, d fs.DirEntry) {
+	if w.isStopped() {
+		return
+	}
+	if err := w.fn(p, d, nil); err != nil {
+		if err != fs.SkipDir {
+			w.stop(err)
+		}
+		return
+	}
+	if !d.IsDir() {
+		return
+	}
+
+	entries, err := w.mount.readDirSorted(p)
+	if err != nil {
+		if err = w.fn(p, d, err); err != nil && err != fs.SkipDir {
+			w.stop(err)
+		}
+		return
+	}
+
+	for _, entry := range entries {
+		if w.isStopped() {
+			return
+		}
+
+		child := path.Join(p, entry.Name())
+		if !entry.IsDir() {
+			err := w.fn(child, entry, nil)
+			if err == fs.SkipDir {
+				return
+			} else if err != nil {
+				w.stop(err)
+				return
+			}
+			continue
+		}
+
+		// read the subdirectory in a new goroutine if the limit allows,
+		// and in this one otherwise
+		select {
+		case w.sem <- struct{}{}:
+			w.wg.Add(1)
+			go func(child string, entry fs.DirEntry) {
+				defer w.wg.Done()
+				defer func() { <-w.sem }()
+				w.walkConcurrent(child, entry)
+			}(child, entry)
+		default:
+			w.walkConcurrent(child, entry)
+		}
+	}
+}
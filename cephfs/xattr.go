@@ -0,0 +1,203 @@
+package cephfs
+
+/*
+#cgo LDFLAGS: -lcephfs
+#cgo CPPFLAGS: -D_FILE_OFFSET_BITS=64
+#include <errno.h>
+#include <stdlib.h>
+#include <linux/xattr.h>
+#include <cephfs/libcephfs.h>
+*/
+import "C"
+
+import (
+	"bytes"
+	"unsafe"
+)
+
+// XattrFlags control whether setting an extended attribute may create or
+// replace it.
+type XattrFlags int
+
+const (
+	// XattrDefault creates the attribute or replaces its value.
+	XattrDefault = XattrFlags(0)
+	// XattrCreate fails if the attribute already exists.
+	XattrCreate = XattrFlags(C.XATTR_CREATE)
+	// XattrReplace fails if the attribute does not exist.
+	XattrReplace = XattrFlags(C.XATTR_REPLACE)
+)
+
+type xattrGetFunc func(value unsafe.Pointer, size C.size_t) C.int
+
+func getXattr(getFn xattrGetFunc) ([]byte, error) {
+	buf := make([]byte, 256)
+	for {
+		ret := getFn(unsafe.Pointer(&buf[0]), C.size_t(len(buf)))
+		if ret == -C.ERANGE {
+			buf = make([]byte, len(buf)*2)
+			continue
+		} else if ret < 0 {
+			return nil, CephError(ret)
+		}
+
+		return buf[:ret], nil
+	}
+}
+
+func listXattr(listFn xattrGetFunc) ([]string, error) {
+	buf, err := getXattr(listFn)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, name := range bytes.Split(buf, []byte{0}) {
+		if len(name) > 0 {
+			names = append(names, string(name))
+		}
+	}
+	return names, nil
+}
+
+// cValue returns a pointer to the value of an attribute that can be passed
+// to C, also for empty values.
+func cValue(value []byte) (unsafe.Pointer, C.size_t) {
+	if len(value) == 0 {
+		return nil, 0
+	}
+	return unsafe.Pointer(&value[0]), C.size_t(len(value))
+}
+
+// GetXattr returns the value of the extended attribute name of the file at
+// path. Besides user attributes, this returns the virtual attributes of
+// CephFS, such as "ceph.dir.rbytes" or "ceph.file.layout.pool".
+func (mount *MountInfo) GetXattr(path, name string) ([]byte, error) {
+	c_path := C.CString(path)
+	defer C.free(unsafe.Pointer(c_path))
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	return getXattr(func(value unsafe.Pointer, size C.size_t) C.int {
+		return C.ceph_getxattr(mount.mount, c_path, c_name, value, size)
+	})
+}
+
+// LGetXattr returns the value of an extended attribute like GetXattr, but
+// of a symbolic link itself rather than of its target.
+func (mount *MountInfo) LGetXattr(path, name string) ([]byte, error) {
+	c_path := C.CString(path)
+	defer C.free(unsafe.Pointer(c_path))
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	return getXattr(func(value unsafe.Pointer, size C.size_t) C.int {
+		return C.ceph_lgetxattr(mount.mount, c_path, c_name, value, size)
+	})
+}
+
+// SetXattr sets the extended attribute name of the file at path to value.
+// Setting some of the virtual attributes of CephFS changes the
+// corresponding settings, for example "ceph.quota.max_bytes".
+func (mount *MountInfo) SetXattr(path, name string, value []byte, flags XattrFlags) error {
+	c_path := C.CString(path)
+	defer C.free(unsafe.Pointer(c_path))
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	c_value, c_size := cValue(value)
+	return getError(C.ceph_setxattr(mount.mount, c_path, c_name, c_value,
+		c_size, C.int(flags)))
+}
+
+// LSetXattr sets an extended attribute like SetXattr, but of a symbolic
+// link itself rather than of its target.
+func (mount *MountInfo) LSetXattr(path, name string, value []byte, flags XattrFlags) error {
+	c_path := C.CString(path)
+	defer C.free(unsafe.Pointer(c_path))
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	c_value, c_size := cValue(value)
+	return getError(C.ceph_lsetxattr(mount.mount, c_path, c_name, c_value,
+		c_size, C.int(flags)))
+}
+
+// ListXattr returns the names of the extended attributes of the file at
+// path. The virtual attributes of CephFS are not listed.
+func (mount *MountInfo) ListXattr(path string) ([]string, error) {
+	c_path := C.CString(path)
+	defer C.free(unsafe.Pointer(c_path))
+
+	return listXattr(func(list unsafe.Pointer, size C.size_t) C.int {
+		return C.ceph_listxattr(mount.mount, c_path, (*C.char)(list), size)
+	})
+}
+
+// LListXattr returns the names of the extended attributes like ListXattr,
+// but of a symbolic link itself rather than of its target.
+func (mount *MountInfo) LListXattr(path string) ([]string, error) {
+	c_path := C.CString(path)
+	defer C.free(unsafe.Pointer(c_path))
+
+	return listXattr(func(list unsafe.Pointer, size C.size_t) C.int {
+		return C.ceph_llistxattr(mount.mount, c_path, (*C.char)(list), size)
+	})
+}
+
+// RemoveXattr removes the extended attribute name of the file at path.
+func (mount *MountInfo) RemoveXattr(path, name string) error {
+	c_path := C.CString(path)
+	defer C.free(unsafe.Pointer(c_path))
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	return getError(C.ceph_removexattr(mount.mount, c_path, c_name))
+}
+
+// LRemoveXattr removes an extended attribute like RemoveXattr, but of a
+// symbolic link itself rather than of its target.
+func (mount *MountInfo) LRemoveXattr(path, name string) error {
+	c_path := C.CString(path)
+	defer C.free(unsafe.Pointer(c_path))
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	return getError(C.ceph_lremovexattr(mount.mount, c_path, c_name))
+}
+
+// GetXattr returns the value of the extended attribute name of the open
+// file.
+func (f *File) GetXattr(name string) ([]byte, error) {
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	return getXattr(func(value unsafe.Pointer, size C.size_t) C.int {
+		return C.ceph_fgetxattr(f.mount.mount, f.fd, c_name, value, size)
+	})
+}
+
+// SetXattr sets the extended attribute name of the open file to value.
+func (f *File) SetXattr(name string, value []byte, flags XattrFlags) error {
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	c_value, c_size := cValue(value)
+	return getError(C.ceph_fsetxattr(f.mount.mount, f.fd, c_name, c_value,
+		c_size, C.int(flags)))
+}
+
+// ListXattr returns the names of the extended attributes of the open file.
+func (f *File) ListXattr() ([]string, error) {
+	return listXattr(func(list unsafe.Pointer, size C.size_t) C.int {
+		return C.ceph_flistxattr(f.mount.mount, f.fd, (*C.char)(list), size)
+	})
+}
+
+// RemoveXattr removes the extended attribute name of the open file.
+func (f *File) RemoveXattr(name string) error {
+	c_name := C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	return getError(C.ceph_fremovexattr(f.mount.mount, f.fd, c_name))
+}
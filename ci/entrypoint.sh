@@ -29,6 +29,8 @@ DIR=$1
 # get rid of process and directories leftovers
 pkill ceph-mon || true
 pkill ceph-osd || true
+pkill ceph-mgr || true
+pkill ceph-mds || true
 rm -fr $DIR
 
 # cluster wide parameters
@@ -42,6 +44,9 @@ auth cluster required = none
 auth service required = none
 auth client required = none
 osd pool default size = 1
+mon allow pool size one = true
+mon warn on pool no redundancy = false
+mon allow pool delete = true
 EOF
 export CEPH_ARGS="--conf ${DIR}/ceph.conf"
 
@@ -81,6 +86,31 @@ ceph osd crush add osd.${OSD_ID} 1 root=default host=localhost
 ceph-osd --id ${OSD_ID} --mkjournal --mkfs
 ceph-osd --id ${OSD_ID}
 
+# single mgr, the admin packages send most of their commands to it
+MGR_DATA=${DIR}/mgr.x
+mkdir ${MGR_DATA}
+
+cat >> $DIR/ceph.conf <<EOF
+[mgr.x]
+log file = ${DIR}/log/mgr.log
+chdir = ""
+mgr data = ${MGR_DATA}
+EOF
+
+ceph-mgr --id x
+for i in $(seq 60); do
+    ceph mgr stat --format json | grep -Eq '"available": ?true' && break
+    sleep 1
+done
+
+# modules and orchestrator needed by the cephfs/admin, rbd/admin and
+# common/admin tests; volumes and rbd_support are always on
+for module in mirroring snap_schedule nfs test_orchestrator; do
+    ceph mgr module enable ${module}
+done
+ceph orch set backend test_orchestrator
+ceph orch status
+
 # single mds
 MDS_DATA=${DIR}/mds.a
 mkdir ${MDS_DATA}
@@ -100,6 +130,10 @@ ceph osd pool create cephfs_metadata 8
 ceph fs new cephfs cephfs_metadata cephfs_data
 ceph-mds -i a
 
+# default pool, it is no longer created by the monitors
+ceph osd pool create rbd 8
+rbd pool init rbd
+
 # check that it works
 rados --pool rbd put group /etc/group
 rados --pool rbd get group ${DIR}/group
@@ -0,0 +1,23 @@
+// Package manager manages the modules of the Ceph manager daemons and their
+// options.
+package manager
+
+import (
+	"github.com/noahdesu/go-ceph/internal/commands"
+	"github.com/noahdesu/go-ceph/rados"
+)
+
+// MgrAdmin sends manager administration commands over a connected cluster
+// handle.
+type MgrAdmin struct {
+	conn *rados.Conn
+}
+
+// NewFromConn returns a MgrAdmin that uses the connected conn.
+func NewFromConn(conn *rados.Conn) *MgrAdmin {
+	return &MgrAdmin{conn: conn}
+}
+
+func (ma *MgrAdmin) monCommand(args map[string]interface{}, out interface{}) error {
+	return commands.Mon(ma.conn, args, out)
+}
@@ -0,0 +1,69 @@
+package manager_test
+
+import (
+	"testing"
+
+	"github.com/noahdesu/go-ceph/common/admin/manager"
+	"github.com/noahdesu/go-ceph/internal/admintest"
+	"github.com/noahdesu/go-ceph/rados"
+	"github.com/stretchr/testify/assert"
+)
+
+func getMgrAdmin(t *testing.T) *manager.MgrAdmin {
+	conn, err := rados.NewConn()
+	assert.NoError(t, err)
+	err = conn.ReadDefaultConfigFile()
+	assert.NoError(t, err)
+	err = conn.Connect()
+	assert.NoError(t, err)
+	admintest.RequireMgr(t, conn)
+	return manager.NewFromConn(conn)
+}
+
+func TestModules(t *testing.T) {
+	ma := getMgrAdmin(t)
+
+	info, err := ma.ListModules()
+	assert.NoError(t, err)
+	assert.True(t, info.IsEnabled("balancer"))
+	assert.False(t, info.IsEnabled("nonexistent"))
+
+	err = ma.EnableModule("telemetry", false)
+	assert.NoError(t, err)
+	info, err = ma.ListModules()
+	assert.NoError(t, err)
+	assert.True(t, info.IsEnabled("telemetry"))
+
+	err = ma.DisableModule("telemetry")
+	assert.NoError(t, err)
+	info, err = ma.ListModules()
+	assert.NoError(t, err)
+	assert.False(t, info.IsEnabled("telemetry"))
+	found := false
+	for _, module := range info.Disabled {
+		if module.Name == "telemetry" {
+			found = true
+			assert.True(t, module.CanRun)
+		}
+	}
+	assert.True(t, found)
+
+	err = ma.EnableModule("nonexistent", false)
+	assert.Error(t, err)
+}
+
+func TestModuleOptions(t *testing.T) {
+	ma := getMgrAdmin(t)
+
+	err := ma.SetModuleOption("balancer", "sleep_interval", "120")
+	assert.NoError(t, err)
+	value, err := ma.GetModuleOption("balancer", "sleep_interval")
+	assert.NoError(t, err)
+	assert.Equal(t, "120", value)
+
+	err = ma.RemoveModuleOption("balancer", "sleep_interval")
+	assert.NoError(t, err)
+	value, err = ma.GetModuleOption("balancer", "sleep_interval")
+	assert.NoError(t, err)
+	assert.Equal(t, "60", value)
+}
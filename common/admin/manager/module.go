@@ -0,0 +1,103 @@
+package manager
+
+// DisabledModule is a module that is not enabled.
+type DisabledModule struct {
+	Name string `json:"name"`
+	// CanRun reports whether the module could be enabled. If not,
+	// ErrorString explains why, like a missing dependency.
+	CanRun      bool   `json:"can_run"`
+	ErrorString string `json:"error_string"`
+}
+
+// ModuleInfo are the modules of the manager.
+type ModuleInfo struct {
+	// AlwaysOn are the modules that are always enabled.
+	AlwaysOn []string         `json:"always_on_modules"`
+	Enabled  []string         `json:"enabled_modules"`
+	Disabled []DisabledModule `json:"disabled_modules"`
+}
+
+// IsEnabled reports whether the module name is always on or enabled.
+func (mi *ModuleInfo) IsEnabled(name string) bool {
+	for _, modules := range [][]string{mi.AlwaysOn, mi.Enabled} {
+		for _, module := range modules {
+			if module == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ListModules returns the modules of the manager.
+func (ma *MgrAdmin) ListModules() (*ModuleInfo, error) {
+	info := &ModuleInfo{}
+	err := ma.monCommand(map[string]interface{}{
+		"prefix": "mgr module ls",
+	}, info)
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// EnableModule enables the module name. With force, it is enabled even if
+// the active manager does not report it can run. Enabling an enabled module
+// succeeds without changes.
+func (ma *MgrAdmin) EnableModule(name string, force bool) error {
+	args := map[string]interface{}{
+		"prefix": "mgr module enable",
+		"module": name,
+	}
+	if force {
+		args["force"] = "--force"
+	}
+	return ma.monCommand(args, nil)
+}
+
+// DisableModule disables the module name.
+func (ma *MgrAdmin) DisableModule(name string) error {
+	return ma.monCommand(map[string]interface{}{
+		"prefix": "mgr module disable",
+		"module": name,
+	}, nil)
+}
+
+func moduleOptionKey(module, option string) string {
+	return "mgr/" + module + "/" + option
+}
+
+// GetModuleOption returns the value of the option of the module, or its
+// default if it is not set.
+func (ma *MgrAdmin) GetModuleOption(module, option string) (string, error) {
+	var value string
+	err := ma.monCommand(map[string]interface{}{
+		"prefix": "config get",
+		"who":    "mgr",
+		"key":    moduleOptionKey(module, option),
+	}, &value)
+	if err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+// SetModuleOption sets the option of the module to value for all managers.
+func (ma *MgrAdmin) SetModuleOption(module, option, value string) error {
+	return ma.monCommand(map[string]interface{}{
+		"prefix": "config set",
+		"who":    "mgr",
+		"name":   moduleOptionKey(module, option),
+		"value":  value,
+	}, nil)
+}
+
+// RemoveModuleOption removes the option of the module, which is then back
+// at its default.
+func (ma *MgrAdmin) RemoveModuleOption(module, option string) error {
+	return ma.monCommand(map[string]interface{}{
+		"prefix": "config rm",
+		"who":    "mgr",
+		"name":   moduleOptionKey(module, option),
+	}, nil)
+}
@@ -0,0 +1,27 @@
+// Package nfs manages the NFS-Ganesha clusters and exports of the nfs
+// manager module, which export CephFS file systems and RGW buckets over NFS.
+package nfs
+
+import (
+	"github.com/noahdesu/go-ceph/internal/commands"
+	"github.com/noahdesu/go-ceph/rados"
+)
+
+// Admin sends commands to the nfs manager module over a connected cluster
+// handle.
+type Admin struct {
+	conn *rados.Conn
+}
+
+// NewFromConn returns an Admin that uses the connected conn.
+func NewFromConn(conn *rados.Conn) *Admin {
+	return &Admin{conn: conn}
+}
+
+func (nfsa *Admin) mgrCommand(args map[string]interface{}, out interface{}) error {
+	return commands.Mgr(nfsa.conn, args, out)
+}
+
+func (nfsa *Admin) mgrInputCommand(args map[string]interface{}, input []byte, out interface{}) error {
+	return commands.MgrInput(nfsa.conn, args, input, out)
+}
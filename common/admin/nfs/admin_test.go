@@ -0,0 +1,112 @@
+package nfs_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/noahdesu/go-ceph/common/admin/nfs"
+	"github.com/noahdesu/go-ceph/internal/admintest"
+	"github.com/noahdesu/go-ceph/rados"
+	"github.com/stretchr/testify/assert"
+)
+
+// the tests expect the nfs manager module to be enabled along with an
+// orchestrator backend that can deploy NFS-Ganesha daemons; they are
+// skipped otherwise
+const testCluster = "gonfs"
+
+func getNFSAdmin(t *testing.T) *nfs.Admin {
+	conn, err := rados.NewConn()
+	assert.NoError(t, err)
+	err = conn.ReadDefaultConfigFile()
+	assert.NoError(t, err)
+	err = conn.Connect()
+	assert.NoError(t, err)
+	admintest.RequireMgr(t, conn, "nfs")
+	admintest.RequireOrchestrator(t, conn)
+	return nfs.NewFromConn(conn)
+}
+
+func TestClusters(t *testing.T) {
+	nfsa := getNFSAdmin(t)
+
+	err := nfsa.CreateCluster(testCluster, &nfs.ClusterOptions{Port: 2050})
+	assert.NoError(t, err)
+
+	ids, err := nfsa.ListClusters()
+	assert.NoError(t, err)
+	assert.Contains(t, ids, testCluster)
+
+	// the daemon is deployed in the background
+	var info *nfs.ClusterInfo
+	for i := 0; i < 60; i++ {
+		info, err = nfsa.ClusterInfo(testCluster)
+		assert.NoError(t, err)
+		if err != nil || len(info.Backend) > 0 {
+			break
+		}
+		time.Sleep(time.Second)
+	}
+	if assert.Len(t, info.Backend, 1) {
+		assert.Equal(t, 2050, info.Backend[0].Port)
+		assert.NotEmpty(t, info.Backend[0].Hostname)
+	}
+	assert.Empty(t, info.VirtualIP)
+
+	err = nfsa.RemoveCluster(testCluster)
+	assert.NoError(t, err)
+
+	ids, err = nfsa.ListClusters()
+	assert.NoError(t, err)
+	assert.NotContains(t, ids, testCluster)
+}
+
+func TestExports(t *testing.T) {
+	nfsa := getNFSAdmin(t)
+
+	err := nfsa.CreateCluster(testCluster, nil)
+	assert.NoError(t, err)
+	defer nfsa.RemoveCluster(testCluster)
+
+	err = nfsa.CreateCephFSExport(nfs.CephFSExportSpec{
+		ClusterID:  testCluster,
+		PseudoPath: "/cephfs",
+		FileSystem: "cephfs",
+		Squash:     nfs.RootSquash,
+		SecTypes:   []nfs.SecType{nfs.SysSec},
+	})
+	assert.NoError(t, err)
+
+	pseudoPaths, err := nfsa.ListExports(testCluster)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/cephfs"}, pseudoPaths)
+
+	info, err := nfsa.ExportInfo(testCluster, "/cephfs")
+	assert.NoError(t, err)
+	assert.Equal(t, "/", info.Path)
+	assert.Equal(t, "RW", info.AccessType)
+	assert.Equal(t, nfs.RootSquash, info.Squash)
+	assert.Equal(t, "CEPH", info.FSAL.Name)
+	assert.Equal(t, "cephfs", info.FSAL.FileSystem)
+
+	info.AccessType = "RO"
+	info.Clients = []nfs.ClientInfo{{
+		Addresses:  []string{"192.168.0.0/16"},
+		AccessType: "RW",
+		Squash:     nfs.NoneSquash,
+	}}
+	err = nfsa.UpdateExport(testCluster, *info)
+	assert.NoError(t, err)
+
+	exports, err := nfsa.ListDetailedExports(testCluster)
+	assert.NoError(t, err)
+	if assert.Len(t, exports, 1) {
+		assert.Equal(t, "RO", exports[0].AccessType)
+		assert.Len(t, exports[0].Clients, 1)
+	}
+
+	err = nfsa.RemoveExport(testCluster, "/cephfs")
+	assert.NoError(t, err)
+	_, err = nfsa.ExportInfo(testCluster, "/cephfs")
+	assert.Error(t, err)
+}
@@ -0,0 +1,91 @@
+package nfs
+
+// ClusterOptions are the parameters of a new NFS cluster.
+type ClusterOptions struct {
+	// Placement is the orchestrator placement spec of the NFS-Ganesha
+	// daemons, like "2 host1,host2". It defaults to a single daemon.
+	Placement string
+	// Ingress deploys a haproxy and keepalived ingress in front of the
+	// daemons, reachable at VirtualIP.
+	Ingress   bool
+	VirtualIP string
+	// Port is the port the daemons, or the ingress, listen on instead of
+	// 2049.
+	Port int
+}
+
+// CreateCluster deploys the NFS cluster id through the orchestrator.
+func (nfsa *Admin) CreateCluster(id string, o *ClusterOptions) error {
+	args := map[string]interface{}{
+		"prefix":     "nfs cluster create",
+		"cluster_id": id,
+	}
+	if o != nil {
+		if o.Placement != "" {
+			args["placement"] = o.Placement
+		}
+		if o.Ingress {
+			args["ingress"] = true
+		}
+		if o.VirtualIP != "" {
+			args["virtual_ip"] = o.VirtualIP
+		}
+		if o.Port != 0 {
+			args["port"] = o.Port
+		}
+	}
+	return nfsa.mgrCommand(args, nil)
+}
+
+// RemoveCluster removes the NFS cluster id along with its exports.
+func (nfsa *Admin) RemoveCluster(id string) error {
+	return nfsa.mgrCommand(map[string]interface{}{
+		"prefix":     "nfs cluster rm",
+		"cluster_id": id,
+	}, nil)
+}
+
+// ListClusters returns the ids of the NFS clusters.
+func (nfsa *Admin) ListClusters() ([]string, error) {
+	var ids []string
+	err := nfsa.mgrCommand(map[string]interface{}{
+		"prefix": "nfs cluster ls",
+	}, &ids)
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// ClusterBackend is an NFS-Ganesha daemon of a cluster.
+type ClusterBackend struct {
+	Hostname string `json:"hostname"`
+	IP       string `json:"ip"`
+	Port     int    `json:"port"`
+}
+
+// ClusterInfo describes where an NFS cluster is served.
+type ClusterInfo struct {
+	// VirtualIP is the address of the ingress, empty without one.
+	VirtualIP   string           `json:"virtual_ip"`
+	Port        int              `json:"port"`
+	MonitorPort int              `json:"monitor_port"`
+	Backend     []ClusterBackend `json:"backend"`
+}
+
+// ClusterInfo returns the description of the NFS cluster id.
+func (nfsa *Admin) ClusterInfo(id string) (*ClusterInfo, error) {
+	var reply map[string]*ClusterInfo
+	err := nfsa.mgrCommand(map[string]interface{}{
+		"prefix":     "nfs cluster info",
+		"cluster_id": id,
+	}, &reply)
+	if err != nil {
+		return nil, err
+	}
+	info := reply[id]
+	if info == nil {
+		info = &ClusterInfo{}
+	}
+	return info, nil
+}
@@ -0,0 +1,211 @@
+package nfs
+
+import (
+	"encoding/json"
+)
+
+// SquashMode selects the users whose identity is mapped to the anonymous
+// user by an export.
+type SquashMode string
+
+const (
+	NoneSquash   = SquashMode("none")
+	RootSquash   = SquashMode("root")
+	AllSquash    = SquashMode("all")
+	RootIdSquash = SquashMode("rootid")
+)
+
+// SecType is an RPC security flavor accepted by an export.
+type SecType string
+
+const (
+	SysSec   = SecType("sys")
+	NoneSec  = SecType("none")
+	Krb5Sec  = SecType("krb5")
+	Krb5iSec = SecType("krb5i")
+	Krb5pSec = SecType("krb5p")
+)
+
+// CephFSExportSpec are the parameters of a new export of a CephFS file
+// system.
+type CephFSExportSpec struct {
+	ClusterID  string
+	PseudoPath string
+	FileSystem string
+	// Path is the directory of the file system that is exported, by
+	// default its root.
+	Path     string
+	ReadOnly bool
+	// ClientAddrs restricts access to the given addresses and networks.
+	ClientAddrs []string
+	Squash      SquashMode
+	SecTypes    []SecType
+}
+
+// RGWExportSpec are the parameters of a new export of an RGW bucket, or of
+// all buckets of a user if Bucket is empty.
+type RGWExportSpec struct {
+	ClusterID   string
+	PseudoPath  string
+	Bucket      string
+	UserID      string
+	ReadOnly    bool
+	ClientAddrs []string
+	Squash      SquashMode
+	SecTypes    []SecType
+}
+
+func exportArgs(prefix, cluster, pseudo string, readOnly bool, clientAddrs []string,
+	squash SquashMode, secTypes []SecType) map[string]interface{} {
+
+	args := map[string]interface{}{
+		"prefix":      prefix,
+		"cluster_id":  cluster,
+		"pseudo_path": pseudo,
+	}
+	if readOnly {
+		args["readonly"] = true
+	}
+	if len(clientAddrs) > 0 {
+		args["client_addr"] = clientAddrs
+	}
+	if squash != "" {
+		args["squash"] = squash
+	}
+	if len(secTypes) > 0 {
+		args["sectype"] = secTypes
+	}
+	return args
+}
+
+// CreateCephFSExport exports a directory of a CephFS file system at the
+// pseudo path of the spec.
+func (nfsa *Admin) CreateCephFSExport(spec CephFSExportSpec) error {
+	args := exportArgs("nfs export create cephfs", spec.ClusterID,
+		spec.PseudoPath, spec.ReadOnly, spec.ClientAddrs, spec.Squash,
+		spec.SecTypes)
+	args["fsname"] = spec.FileSystem
+	if spec.Path != "" {
+		args["path"] = spec.Path
+	}
+	return nfsa.mgrCommand(args, nil)
+}
+
+// CreateRGWExport exports an RGW bucket at the pseudo path of the spec.
+func (nfsa *Admin) CreateRGWExport(spec RGWExportSpec) error {
+	args := exportArgs("nfs export create rgw", spec.ClusterID,
+		spec.PseudoPath, spec.ReadOnly, spec.ClientAddrs, spec.Squash,
+		spec.SecTypes)
+	if spec.Bucket != "" {
+		args["bucket"] = spec.Bucket
+	}
+	if spec.UserID != "" {
+		args["user_id"] = spec.UserID
+	}
+	return nfsa.mgrCommand(args, nil)
+}
+
+// RemoveExport removes the export at pseudoPath of the NFS cluster.
+func (nfsa *Admin) RemoveExport(cluster, pseudoPath string) error {
+	return nfsa.mgrCommand(map[string]interface{}{
+		"prefix":      "nfs export rm",
+		"cluster_id":  cluster,
+		"pseudo_path": pseudoPath,
+	}, nil)
+}
+
+// FSALInfo is the backend of an export.
+type FSALInfo struct {
+	// Name is "CEPH" for CephFS exports and "RGW" for RGW exports.
+	Name   string `json:"name"`
+	UserID string `json:"user_id,omitempty"`
+	// FileSystem is the CephFS file system of the export.
+	FileSystem string `json:"fs_name,omitempty"`
+	// AccessKeyID and SecretAccessKey are the S3 credentials of RGW
+	// exports.
+	AccessKeyID     string `json:"access_key_id,omitempty"`
+	SecretAccessKey string `json:"secret_access_key,omitempty"`
+}
+
+// ClientInfo overrides the access of an export for some clients.
+type ClientInfo struct {
+	Addresses  []string   `json:"addresses"`
+	AccessType string     `json:"access_type"`
+	Squash     SquashMode `json:"squash"`
+}
+
+// ExportInfo describes an export.
+type ExportInfo struct {
+	ExportID   int64  `json:"export_id"`
+	ClusterID  string `json:"cluster_id"`
+	PseudoPath string `json:"pseudo"`
+	// Path is the exported directory of a CephFS export, or the bucket of
+	// an RGW export.
+	Path string `json:"path"`
+	// AccessType is "RW" or "RO".
+	AccessType    string       `json:"access_type"`
+	Squash        SquashMode   `json:"squash"`
+	SecurityLabel bool         `json:"security_label"`
+	Protocols     []int        `json:"protocols"`
+	Transports    []string     `json:"transports"`
+	FSAL          FSALInfo     `json:"fsal"`
+	Clients       []ClientInfo `json:"clients"`
+	SecTypes      []SecType    `json:"sectype,omitempty"`
+}
+
+// ListExports returns the pseudo paths of the exports of the NFS cluster.
+func (nfsa *Admin) ListExports(cluster string) ([]string, error) {
+	var pseudoPaths []string
+	err := nfsa.mgrCommand(map[string]interface{}{
+		"prefix":     "nfs export ls",
+		"cluster_id": cluster,
+	}, &pseudoPaths)
+	if err != nil {
+		return nil, err
+	}
+	return pseudoPaths, nil
+}
+
+// ListDetailedExports returns the descriptions of the exports of the NFS
+// cluster.
+func (nfsa *Admin) ListDetailedExports(cluster string) ([]ExportInfo, error) {
+	var exports []ExportInfo
+	err := nfsa.mgrCommand(map[string]interface{}{
+		"prefix":     "nfs export ls",
+		"cluster_id": cluster,
+		"detailed":   true,
+	}, &exports)
+	if err != nil {
+		return nil, err
+	}
+	return exports, nil
+}
+
+// ExportInfo returns the description of the export at pseudoPath of the NFS
+// cluster.
+func (nfsa *Admin) ExportInfo(cluster, pseudoPath string) (*ExportInfo, error) {
+	info := &ExportInfo{}
+	err := nfsa.mgrCommand(map[string]interface{}{
+		"prefix":      "nfs export info",
+		"cluster_id":  cluster,
+		"pseudo_path": pseudoPath,
+	}, info)
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// UpdateExport replaces the export of the NFS cluster that has the ExportID
+// of info, or creates it if there is none, typically after changing the
+// result of ExportInfo.
+func (nfsa *Admin) UpdateExport(cluster string, info ExportInfo) error {
+	buf, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	return nfsa.mgrInputCommand(map[string]interface{}{
+		"prefix":     "nfs export apply",
+		"cluster_id": cluster,
+	}, buf, nil)
+}
@@ -0,0 +1,77 @@
+// Package admintest checks that a test cluster provides what the admin
+// package tests need, and skips the tests when it does not. ci/micro-osd.sh
+// provisions all of it.
+package admintest
+
+import (
+	"testing"
+
+	"github.com/noahdesu/go-ceph/common/admin/manager"
+	"github.com/noahdesu/go-ceph/internal/commands"
+	"github.com/noahdesu/go-ceph/rados"
+)
+
+type mgrStat struct {
+	Available  bool   `json:"available"`
+	ActiveName string `json:"active_name"`
+}
+
+// RequireMgr skips the test unless a manager is active and has all of
+// modules enabled. Manager commands wait for an active manager, so this must
+// be checked before sending any.
+func RequireMgr(t *testing.T, conn *rados.Conn, modules ...string) {
+	stat := mgrStat{}
+	err := commands.Mon(conn, map[string]interface{}{"prefix": "mgr stat"}, &stat)
+	if err != nil {
+		t.Skipf("can not get the manager status: %v", err)
+	}
+	if !stat.Available {
+		t.Skip("no active manager")
+	}
+
+	info, err := manager.NewFromConn(conn).ListModules()
+	if err != nil {
+		t.Skipf("can not list the manager modules: %v", err)
+	}
+	for _, module := range modules {
+		if !info.IsEnabled(module) {
+			t.Skipf("manager module %q is not enabled", module)
+		}
+	}
+}
+
+// RequireFS skips the test unless the file system name exists.
+func RequireFS(t *testing.T, conn *rados.Conn, name string) {
+	var filesystems []struct {
+		Name string `json:"name"`
+	}
+	err := commands.Mon(conn, map[string]interface{}{"prefix": "fs ls"}, &filesystems)
+	if err != nil {
+		t.Skipf("can not list the file systems: %v", err)
+	}
+	for _, fs := range filesystems {
+		if fs.Name == name {
+			return
+		}
+	}
+	t.Skipf("no file system named %q", name)
+}
+
+// RequireOrchestrator skips the test unless an orchestrator backend is
+// configured and available.
+func RequireOrchestrator(t *testing.T, conn *rados.Conn) {
+	RequireMgr(t, conn, "orchestrator")
+
+	status := struct {
+		Available bool   `json:"available"`
+		Backend   string `json:"backend"`
+		Reason    string `json:"reason"`
+	}{}
+	err := commands.Mgr(conn, map[string]interface{}{"prefix": "orch status"}, &status)
+	if err != nil {
+		t.Skipf("no orchestrator: %v", err)
+	}
+	if !status.Available {
+		t.Skipf("orchestrator %q is not available: %s", status.Backend, status.Reason)
+	}
+}
@@ -0,0 +1,47 @@
+// Package callbacks provides a registry for Go values that are handed to the
+// Ceph C libraries as opaque callback arguments.
+//
+// C code must not hold Go pointers, so the C side only ever sees the integer
+// index under which a value is stored. The callback trampoline looks the
+// value up again by that index.
+package callbacks
+
+import (
+	"sync"
+)
+
+// Callbacks keeps Go values that are referenced from C by index.
+type Callbacks struct {
+	mutex sync.Mutex
+	next  uintptr
+	items map[uintptr]interface{}
+}
+
+// New returns an empty callback registry.
+func New() *Callbacks {
+	return &Callbacks{items: make(map[uintptr]interface{})}
+}
+
+// Add stores v and returns the index that refers to it.
+func (c *Callbacks) Add(v interface{}) uintptr {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	// index 0 is never used so that a NULL argument is never valid
+	c.next++
+	c.items[c.next] = v
+	return c.next
+}
+
+// Remove forgets the value stored under index.
+func (c *Callbacks) Remove(index uintptr) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.items, index)
+}
+
+// Lookup returns the value stored under index, or nil if there is none.
+func (c *Callbacks) Lookup(index uintptr) interface{} {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.items[index]
+}
@@ -0,0 +1,24 @@
+package callbacks
+
+import (
+	"testing"
+)
+
+func TestCallbacks(t *testing.T) {
+	c := New()
+	a := c.Add("a")
+	b := c.Add("b")
+	if a == 0 || b == 0 || a == b {
+		t.Fatalf("bad indexes %d, %d", a, b)
+	}
+	if v := c.Lookup(a); v != "a" {
+		t.Fatalf("lookup(%d) = %v", a, v)
+	}
+	c.Remove(a)
+	if v := c.Lookup(a); v != nil {
+		t.Fatalf("lookup(%d) after remove = %v", a, v)
+	}
+	if v := c.Lookup(b); v != "b" {
+		t.Fatalf("lookup(%d) = %v", b, v)
+	}
+}
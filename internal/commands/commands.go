@@ -0,0 +1,100 @@
+// Package commands sends JSON commands to the monitors and manager daemons
+// and decodes their replies, for the admin packages.
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MonCommander sends commands to the monitors, like rados.Conn.
+type MonCommander interface {
+	MonCommand(args []byte) ([]byte, string, error)
+}
+
+// MgrCommander sends commands to the manager, like rados.Conn.
+type MgrCommander interface {
+	MgrCommand(args []byte) ([]byte, string, error)
+}
+
+// Error is the error of a failed command. It carries the status string the
+// daemon explained the failure with, and unwraps to the rados error.
+type Error struct {
+	Prefix string
+	Status string
+	Err    error
+}
+
+func (e *Error) Error() string {
+	if e.Status == "" {
+		return fmt.Sprintf("%s: %v", e.Prefix, e.Err)
+	}
+	return fmt.Sprintf("%s: %v: %s", e.Prefix, e.Err, e.Status)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// MgrInputCommander sends commands with a bulk input to the manager, like
+// rados.Conn.
+type MgrInputCommander interface {
+	MgrCommandWithInputBuffer(args, inputBuffer []byte) ([]byte, string, error)
+}
+
+type commandFunc func(args []byte) ([]byte, string, error)
+
+func run(fn commandFunc, args map[string]interface{}) ([]byte, error) {
+	buf, err := json.Marshal(args)
+	if err != nil {
+		return nil, err
+	}
+
+	body, status, err := fn(buf)
+	if err != nil {
+		prefix, _ := args["prefix"].(string)
+		return nil, &Error{Prefix: prefix, Status: status, Err: err}
+	}
+	return body, nil
+}
+
+func runJSON(fn commandFunc, args map[string]interface{}, out interface{}) error {
+	if out != nil {
+		args["format"] = "json"
+	}
+	body, err := run(fn, args)
+	if err != nil || out == nil {
+		return err
+	}
+	return json.Unmarshal(body, out)
+}
+
+// Mon sends the command args to the monitors and decodes the JSON reply
+// into out, unless out is nil. args must contain the "prefix" of the command.
+func Mon(c MonCommander, args map[string]interface{}, out interface{}) error {
+	return runJSON(c.MonCommand, args, out)
+}
+
+// Mgr sends the command args to the manager and decodes the JSON reply into
+// out, unless out is nil. args must contain the "prefix" of the command.
+func Mgr(c MgrCommander, args map[string]interface{}, out interface{}) error {
+	return runJSON(c.MgrCommand, args, out)
+}
+
+// MgrPlain sends the command args to the manager and returns its reply,
+// for the commands that only reply in plain text.
+func MgrPlain(c MgrCommander, args map[string]interface{}) (string, error) {
+	body, err := run(c.MgrCommand, args)
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+// MgrInput sends the command args to the manager along with the bulk input,
+// and decodes the JSON reply into out, unless out is nil.
+func MgrInput(c MgrInputCommander, args map[string]interface{}, input []byte, out interface{}) error {
+	return runJSON(func(args []byte) ([]byte, string, error) {
+		return c.MgrCommandWithInputBuffer(args, input)
+	}, args, out)
+}
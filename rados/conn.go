@@ -262,10 +262,8 @@ func (c *Conn) DeletePool(name string) error {
 
 // MonCommand sends a command to one of the monitors
 func (c *Conn) MonCommand(args []byte) (buffer []byte, info string, err error) {
-	argv := make([]*C.char, len(args))
-	for i, _ := range args {
-		argv[i] = (*C.char)(unsafe.Pointer(&args[i]))
-	}
+	c_args := C.CString(string(args))
+	defer C.free(unsafe.Pointer(c_args))
 
 	var (
 		outs, outbuf       *C.char
@@ -275,7 +273,7 @@ func (c *Conn) MonCommand(args []byte) (buffer []byte, info string, err error) {
 	defer C.free(unsafe.Pointer(inbuf))
 
 	ret := C.rados_mon_command(c.cluster,
-		&argv[0], C.size_t(len(args)),
+		&c_args, 1,
 		inbuf,       // bulk input (e.g. crush map)
 		C.size_t(0), // length inbuf
 		&outbuf,     // buffer
@@ -298,3 +296,44 @@ func (c *Conn) MonCommand(args []byte) (buffer []byte, info string, err error) {
 
 	return
 }
+
+// MgrCommand sends a JSON formatted command to the active manager daemon
+// and returns its output and status string. Commands of manager modules,
+// such as "fs volume ls", are only available this way.
+func (c *Conn) MgrCommand(args []byte) (buffer []byte, info string, err error) {
+	return c.MgrCommandWithInputBuffer(args, nil)
+}
+
+// MgrCommandWithInputBuffer sends a JSON formatted command to the active
+// manager daemon like MgrCommand, along with the bulk input of commands that
+// read one, such as "nfs export apply".
+func (c *Conn) MgrCommandWithInputBuffer(args, inputBuffer []byte) (buffer []byte, info string, err error) {
+	c_args := C.CString(string(args))
+	defer C.free(unsafe.Pointer(c_args))
+	c_inbuf := C.CString(string(inputBuffer))
+	defer C.free(unsafe.Pointer(c_inbuf))
+
+	var (
+		outs, outbuf       *C.char
+		outslen, outbuflen C.size_t
+	)
+	ret := C.rados_mgr_command(c.cluster,
+		&c_args, 1,
+		c_inbuf, C.size_t(len(inputBuffer)),
+		&outbuf, &outbuflen,
+		&outs, &outslen)
+
+	if outslen > 0 {
+		info = C.GoStringN(outs, C.int(outslen))
+		C.free(unsafe.Pointer(outs))
+	}
+	if outbuflen > 0 {
+		buffer = C.GoBytes(unsafe.Pointer(outbuf), C.int(outbuflen))
+		C.free(unsafe.Pointer(outbuf))
+	}
+	if ret != 0 {
+		return nil, info, RadosError(int(ret))
+	}
+
+	return buffer, info, nil
+}
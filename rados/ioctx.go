@@ -170,6 +170,33 @@ func (ioctx *IOContext) GetPoolName() (name string, err error) {
 	}
 }
 
+// SetNamespace sets the namespace for objects within the I/O context. All
+// subsequent operations on the context, including those made through librbd,
+// are confined to the namespace. An empty string selects the default
+// namespace.
+func (ioctx *IOContext) SetNamespace(namespace string) {
+	c_ns := C.CString(namespace)
+	defer C.free(unsafe.Pointer(c_ns))
+	C.rados_ioctx_set_namespace(ioctx.ioctx, c_ns)
+}
+
+// GetNamespace returns the namespace currently set on the I/O context.
+func (ioctx *IOContext) GetNamespace() (namespace string, err error) {
+	buf := make([]byte, 128)
+	for {
+		ret := C.rados_ioctx_get_namespace(ioctx.ioctx,
+			(*C.char)(unsafe.Pointer(&buf[0])), C.unsigned(len(buf)))
+		if ret == -C.ERANGE {
+			buf = make([]byte, len(buf)*2)
+			continue
+		} else if ret < 0 {
+			return "", RadosError(ret)
+		}
+		namespace = C.GoStringN((*C.char)(unsafe.Pointer(&buf[0])), ret)
+		return namespace, nil
+	}
+}
+
 // ObjectListFunc is the type of the function called for each object visited
 // by ListObjects.
 type ObjectListFunc func(oid string)
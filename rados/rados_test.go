@@ -13,6 +13,7 @@ import "time"
 import "net"
 import "fmt"
 import "sort"
+import "strings"
 import "encoding/json"
 
 func GetUUID() string {
@@ -437,6 +438,40 @@ func TestGetPoolName(t *testing.T) {
 	conn.Shutdown()
 }
 
+func TestNamespace(t *testing.T) {
+	conn, _ := rados.NewConn()
+	conn.ReadDefaultConfigFile()
+	conn.Connect()
+
+	poolname := GetUUID()
+	err := conn.MakePool(poolname)
+	assert.NoError(t, err)
+
+	ioctx, err := conn.OpenIOContext(poolname)
+	assert.NoError(t, err)
+
+	ns, err := ioctx.GetNamespace()
+	assert.NoError(t, err)
+	assert.Equal(t, ns, "")
+
+	ioctx.SetNamespace("space1")
+	ns, err = ioctx.GetNamespace()
+	assert.NoError(t, err)
+	assert.Equal(t, ns, "space1")
+
+	err = ioctx.Write("obj", []byte("input data"), 0)
+	assert.NoError(t, err)
+
+	// the object is not visible from the default namespace
+	ioctx.SetNamespace("")
+	_, err = ioctx.Stat("obj")
+	assert.Error(t, err)
+
+	ioctx.Destroy()
+	conn.DeletePool(poolname)
+	conn.Shutdown()
+}
+
 func TestMonCommand(t *testing.T) {
 	conn, _ := rados.NewConn()
 	conn.ReadDefaultConfigFile()
@@ -456,6 +491,59 @@ func TestMonCommand(t *testing.T) {
 	conn.Shutdown()
 }
 
+// the command has to reach the monitor as it is, a long argument must not be
+// cut short or followed by stray bytes
+func TestMonCommandArgs(t *testing.T) {
+	conn, _ := rados.NewConn()
+	conn.ReadDefaultConfigFile()
+	conn.Connect()
+
+	key := "go-ceph/" + GetUUID()
+	value := strings.Repeat("0123456789", 1000)
+
+	command, err := json.Marshal(map[string]string{"prefix": "config-key set", "key": key, "val": value})
+	assert.NoError(t, err)
+	_, _, err = conn.MonCommand(command)
+	assert.NoError(t, err)
+
+	command, err = json.Marshal(map[string]string{"prefix": "config-key get", "key": key})
+	assert.NoError(t, err)
+	buf, _, err := conn.MonCommand(command)
+	assert.NoError(t, err)
+	assert.Equal(t, value, string(buf))
+
+	command, err = json.Marshal(map[string]string{"prefix": "config-key rm", "key": key})
+	assert.NoError(t, err)
+	_, _, err = conn.MonCommand(command)
+	assert.NoError(t, err)
+
+	conn.Shutdown()
+}
+
+func TestMgrCommand(t *testing.T) {
+	conn, _ := rados.NewConn()
+	conn.ReadDefaultConfigFile()
+	conn.Connect()
+
+	command, err := json.Marshal(map[string]string{"prefix": "mgr module ls", "format": "json"})
+	assert.NoError(t, err)
+
+	buf, _, err := conn.MgrCommand(command)
+	assert.NoError(t, err)
+
+	var message map[string]interface{}
+	err = json.Unmarshal(buf, &message)
+	assert.NoError(t, err)
+
+	command, err = json.Marshal(map[string]string{"prefix": "no such command"})
+	assert.NoError(t, err)
+
+	_, _, err = conn.MgrCommand(command)
+	assert.Error(t, err)
+
+	conn.Shutdown()
+}
+
 func TestObjectIterator(t *testing.T) {
 	conn, _ := rados.NewConn()
 	conn.ReadDefaultConfigFile()
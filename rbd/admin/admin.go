@@ -0,0 +1,24 @@
+// Package admin manages RBD images through the commands of the rbd_support
+// manager module, which runs scheduled and long-running image operations
+// inside the manager instead of the client.
+package admin
+
+import (
+	"github.com/noahdesu/go-ceph/internal/commands"
+	"github.com/noahdesu/go-ceph/rados"
+)
+
+// RBDAdmin sends RBD administration commands over a connected cluster
+// handle.
+type RBDAdmin struct {
+	conn *rados.Conn
+}
+
+// NewFromConn returns an RBDAdmin that uses the connected conn.
+func NewFromConn(conn *rados.Conn) *RBDAdmin {
+	return &RBDAdmin{conn: conn}
+}
+
+func (ra *RBDAdmin) mgrCommand(args map[string]interface{}, out interface{}) error {
+	return commands.Mgr(ra.conn, args, out)
+}
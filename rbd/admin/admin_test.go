@@ -0,0 +1,249 @@
+package admin_test
+
+import (
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/noahdesu/go-ceph/internal/admintest"
+	"github.com/noahdesu/go-ceph/rados"
+	"github.com/noahdesu/go-ceph/rbd"
+	"github.com/noahdesu/go-ceph/rbd/admin"
+	"github.com/stretchr/testify/assert"
+)
+
+func getUUID() string {
+	out, _ := exec.Command("uuidgen").Output()
+	return string(out[:36])
+}
+
+// getConn connects to the cluster, or skips the test if there is no active
+// manager to run the rbd_support module.
+func getConn(t *testing.T) *rados.Conn {
+	conn, err := rados.NewConn()
+	assert.NoError(t, err)
+	err = conn.ReadDefaultConfigFile()
+	assert.NoError(t, err)
+	err = conn.Connect()
+	assert.NoError(t, err)
+	admintest.RequireMgr(t, conn, "rbd_support")
+	return conn
+}
+
+func TestLevelSpec(t *testing.T) {
+	assert.Equal(t, "", admin.NewLevelSpec("", "", "").String())
+	assert.Equal(t, "rbd", admin.NewLevelSpec("rbd", "", "").String())
+	assert.Equal(t, "rbd/ns", admin.NewLevelSpec("rbd", "ns", "").String())
+	assert.Equal(t, "rbd/ns/img", admin.NewLevelSpec("rbd", "ns", "img").String())
+	assert.Equal(t, "rbd//img", admin.NewLevelSpec("rbd", "", "img").String())
+}
+
+func TestMirrorSnapshotSchedules(t *testing.T) {
+	conn := getConn(t)
+	defer conn.Shutdown()
+	ra := admin.NewFromConn(conn)
+
+	poolname := getUUID()
+	err := conn.MakePool(poolname)
+	assert.NoError(t, err)
+	defer conn.DeletePool(poolname)
+	ioctx, err := conn.OpenIOContext(poolname)
+	assert.NoError(t, err)
+	defer ioctx.Destroy()
+
+	err = rbd.MirrorModeSet(ioctx, rbd.MirrorModeImage)
+	assert.NoError(t, err)
+	image, err := rbd.Create(ioctx, "mirrored", 1<<22,
+		rbd.RbdFeatureLayering|rbd.RbdFeatureExclusiveLock)
+	assert.NoError(t, err)
+	err = image.Open()
+	assert.NoError(t, err)
+	err = image.MirrorEnable(rbd.ImageMirrorModeSnapshot)
+	assert.NoError(t, err)
+	defer image.Remove()
+	defer image.Close()
+
+	poolLevel := admin.NewLevelSpec(poolname, "", "")
+	imageLevel := admin.NewLevelSpec(poolname, "", "mirrored")
+
+	err = ra.AddMirrorSnapshotSchedule(poolLevel, "1d", admin.NoStartTime)
+	assert.NoError(t, err)
+	err = ra.AddMirrorSnapshotSchedule(imageLevel, "30m", "14:00:00")
+	assert.NoError(t, err)
+
+	schedules, err := ra.ListMirrorSnapshotSchedules(poolLevel)
+	assert.NoError(t, err)
+	if assert.Len(t, schedules, 2) {
+		assert.Equal(t, poolLevel, schedules[0].Level)
+		assert.Equal(t, []admin.ScheduleItem{{Interval: "1d"}}, schedules[0].Items)
+		assert.Equal(t, imageLevel, schedules[1].Level)
+		if assert.Len(t, schedules[1].Items, 1) {
+			assert.Equal(t, admin.Interval("30m"), schedules[1].Items[0].Interval)
+			assert.NotEmpty(t, schedules[1].Items[0].StartTime)
+		}
+	}
+
+	// the manager picks up new schedules periodically
+	images, err := ra.MirrorSnapshotScheduleStatus(poolLevel)
+	assert.NoError(t, err)
+	for _, image := range images {
+		assert.Equal(t, poolname+"/mirrored", image.Image)
+	}
+
+	err = ra.RemoveMirrorSnapshotSchedule(imageLevel, "", admin.NoStartTime)
+	assert.NoError(t, err)
+	err = ra.RemoveMirrorSnapshotSchedule(poolLevel, "1d", admin.NoStartTime)
+	assert.NoError(t, err)
+	err = ra.RemoveMirrorSnapshotSchedule(poolLevel, "1d", admin.NoStartTime)
+	assert.Error(t, err)
+
+	schedules, err = ra.ListMirrorSnapshotSchedules(poolLevel)
+	assert.NoError(t, err)
+	assert.Len(t, schedules, 0)
+}
+
+// waitForTasks waits until the manager has run all tasks.
+func waitForTasks(t *testing.T, ra *admin.RBDAdmin) {
+	for i := 0; i < 60; i++ {
+		tasks, err := ra.ListTasks()
+		assert.NoError(t, err)
+		if err != nil || len(tasks) == 0 {
+			return
+		}
+		time.Sleep(time.Second)
+	}
+	t.Fatal("tasks did not finish")
+}
+
+func TestTasks(t *testing.T) {
+	conn := getConn(t)
+	defer conn.Shutdown()
+	ra := admin.NewFromConn(conn)
+
+	poolname := getUUID()
+	err := conn.MakePool(poolname)
+	assert.NoError(t, err)
+	defer conn.DeletePool(poolname)
+	ioctx, err := conn.OpenIOContext(poolname)
+	assert.NoError(t, err)
+	defer ioctx.Destroy()
+
+	_, err = rbd.Create(ioctx, "removed", 1<<22)
+	assert.NoError(t, err)
+	task, err := ra.AddRemoveTask(poolname, "", "removed")
+	assert.NoError(t, err)
+	assert.Equal(t, "remove", task.Refs.Action)
+	assert.Equal(t, poolname, task.Refs.PoolName)
+	assert.Equal(t, "removed", task.Refs.ImageName)
+
+	image, err := rbd.Create(ioctx, "trashed", 1<<22)
+	assert.NoError(t, err)
+	err = image.Trash(0)
+	assert.NoError(t, err)
+	trash, err := rbd.GetTrashList(ioctx)
+	assert.NoError(t, err)
+	if assert.Len(t, trash, 1) {
+		task, err = ra.AddTrashRemoveTask(poolname, "", trash[0].Id)
+		assert.NoError(t, err)
+		assert.Equal(t, "trash remove", task.Refs.Action)
+		assert.Equal(t, trash[0].Id, task.Refs.ImageID)
+	}
+
+	waitForTasks(t, ra)
+	names, err := rbd.GetImageNames(ioctx)
+	assert.NoError(t, err)
+	assert.Len(t, names, 0)
+	trash, err = rbd.GetTrashList(ioctx)
+	assert.NoError(t, err)
+	assert.Len(t, trash, 0)
+
+	_, err = ra.AddFlattenTask(poolname, "", "nonexistent")
+	assert.Error(t, err)
+	_, err = ra.GetTask("nonexistent")
+	assert.Error(t, err)
+	err = ra.CancelTask("nonexistent")
+	assert.Error(t, err)
+}
+
+func TestImagePerfStats(t *testing.T) {
+	conn := getConn(t)
+	defer conn.Shutdown()
+	ra := admin.NewFromConn(conn)
+
+	poolname := getUUID()
+	err := conn.MakePool(poolname)
+	assert.NoError(t, err)
+	defer conn.DeletePool(poolname)
+	ioctx, err := conn.OpenIOContext(poolname)
+	assert.NoError(t, err)
+	defer ioctx.Destroy()
+
+	image, err := rbd.Create(ioctx, "busy", 1<<22)
+	assert.NoError(t, err)
+	err = image.Open()
+	assert.NoError(t, err)
+	defer image.Remove()
+	defer image.Close()
+
+	_, err = ra.ImagePerfStats(poolname, "", admin.PerfWriteOps)
+	assert.NoError(t, err)
+
+	var stats []admin.ImagePerfStats
+	data := make([]byte, 4096)
+	for i := 0; i < 30 && len(stats) == 0; i++ {
+		for j := 0; j < 100; j++ {
+			_, err = image.WriteAt(data, int64(j*len(data)))
+			assert.NoError(t, err)
+		}
+		time.Sleep(time.Second)
+		stats, err = ra.ImagePerfStats(poolname, "", admin.PerfWriteOps)
+		assert.NoError(t, err)
+	}
+	if assert.Len(t, stats, 1) {
+		assert.Equal(t, poolname, stats[0].Pool)
+		assert.Equal(t, "busy", stats[0].Image)
+		assert.True(t, stats[0].WriteOps > 0)
+		assert.True(t, stats[0].WriteBytes > 0)
+	}
+
+	_, err = ra.ImagePerfStats("nonexistent", "", admin.PerfReadOps)
+	assert.Error(t, err)
+}
+
+func TestTrashPurgeSchedules(t *testing.T) {
+	conn := getConn(t)
+	defer conn.Shutdown()
+	ra := admin.NewFromConn(conn)
+
+	poolname := getUUID()
+	err := conn.MakePool(poolname)
+	assert.NoError(t, err)
+	defer conn.DeletePool(poolname)
+
+	level := admin.NewLevelSpec(poolname, "", "")
+	err = ra.AddTrashPurgeSchedule(level, "1h", admin.NoStartTime)
+	assert.NoError(t, err)
+	err = ra.AddTrashPurgeSchedule(admin.NewLevelSpec(poolname, "", "img"), "1h", admin.NoStartTime)
+	assert.Error(t, err)
+
+	schedules, err := ra.ListTrashPurgeSchedules(level)
+	assert.NoError(t, err)
+	if assert.Len(t, schedules, 1) {
+		assert.Equal(t, level, schedules[0].Level)
+		assert.Equal(t, []admin.ScheduleItem{{Interval: "1h"}}, schedules[0].Items)
+	}
+
+	// the manager picks up new schedules periodically
+	pools, err := ra.TrashPurgeScheduleStatus(level)
+	assert.NoError(t, err)
+	for _, pool := range pools {
+		assert.Equal(t, poolname, pool.PoolName)
+		assert.NotEmpty(t, pool.ScheduleTime)
+	}
+
+	err = ra.RemoveTrashPurgeSchedule(level, "1h", admin.NoStartTime)
+	assert.NoError(t, err)
+	schedules, err = ra.ListTrashPurgeSchedules(level)
+	assert.NoError(t, err)
+	assert.Len(t, schedules, 0)
+}
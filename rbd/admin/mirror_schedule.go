@@ -0,0 +1,30 @@
+package admin
+
+// AddMirrorSnapshotSchedule schedules mirror snapshots of the images
+// selected by level, which must have snapshot based mirroring enabled,
+// every interval.
+func (ra *RBDAdmin) AddMirrorSnapshotSchedule(level LevelSpec, interval Interval, startTime string) error {
+	return ra.scheduleCommand("rbd mirror snapshot schedule add", level,
+		interval, startTime)
+}
+
+// RemoveMirrorSnapshotSchedule removes the mirror snapshot schedule of level
+// with the given interval and start time. An empty interval removes all
+// schedules of level.
+func (ra *RBDAdmin) RemoveMirrorSnapshotSchedule(level LevelSpec, interval Interval, startTime string) error {
+	return ra.scheduleCommand("rbd mirror snapshot schedule remove", level,
+		interval, startTime)
+}
+
+// ListMirrorSnapshotSchedules returns the mirror snapshot schedules set on
+// level and on the levels below it.
+func (ra *RBDAdmin) ListMirrorSnapshotSchedules(level LevelSpec) ([]ScheduleInfo, error) {
+	return ra.listSchedules("rbd mirror snapshot schedule list", level)
+}
+
+// MirrorSnapshotScheduleStatus returns the time of the next mirror snapshot
+// of each scheduled image selected by level.
+func (ra *RBDAdmin) MirrorSnapshotScheduleStatus(level LevelSpec) ([]ScheduledImage, error) {
+	return ra.scheduleStatus("rbd mirror snapshot schedule status",
+		"scheduled_images", level)
+}
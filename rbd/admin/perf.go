@@ -0,0 +1,111 @@
+package admin
+
+import (
+	"sort"
+	"time"
+)
+
+// PerfCounter is a counter of the image performance statistics.
+type PerfCounter string
+
+const (
+	PerfWriteOps     = PerfCounter("write_ops")
+	PerfReadOps      = PerfCounter("read_ops")
+	PerfWriteBytes   = PerfCounter("write_bytes")
+	PerfReadBytes    = PerfCounter("read_bytes")
+	PerfWriteLatency = PerfCounter("write_latency")
+	PerfReadLatency  = PerfCounter("read_latency")
+)
+
+// ImagePerfStats is the I/O rate of an image, averaged over the last
+// interval the manager collected the statistics in.
+type ImagePerfStats struct {
+	// Pool is the pool of the image, in pool/namespace form for images
+	// in a namespace.
+	Pool  string
+	Image string
+	// WriteOps and ReadOps are per second.
+	WriteOps float64
+	ReadOps  float64
+	// WriteBytes and ReadBytes are per second.
+	WriteBytes   float64
+	ReadBytes    float64
+	WriteLatency time.Duration
+	ReadLatency  time.Duration
+}
+
+func (s *ImagePerfStats) set(counter PerfCounter, value float64) {
+	switch counter {
+	case PerfWriteOps:
+		s.WriteOps = value
+	case PerfReadOps:
+		s.ReadOps = value
+	case PerfWriteBytes:
+		s.WriteBytes = value
+	case PerfReadBytes:
+		s.ReadBytes = value
+	case PerfWriteLatency:
+		s.WriteLatency = time.Duration(value)
+	case PerfReadLatency:
+		s.ReadLatency = time.Duration(value)
+	}
+}
+
+func (s *ImagePerfStats) get(counter PerfCounter) float64 {
+	switch counter {
+	case PerfWriteOps:
+		return s.WriteOps
+	case PerfReadOps:
+		return s.ReadOps
+	case PerfWriteBytes:
+		return s.WriteBytes
+	case PerfReadBytes:
+		return s.ReadBytes
+	case PerfWriteLatency:
+		return float64(s.WriteLatency)
+	case PerfReadLatency:
+		return float64(s.ReadLatency)
+	}
+	return 0
+}
+
+// ImagePerfStats returns the I/O rates of the images in the namespace of the
+// pool, of all namespaces of the pool if namespace is empty, or of all pools
+// if pool is empty, ordered by sortBy from the busiest image down, like
+// "rbd perf image iostat" and "rbd perf image iotop". Collecting starts
+// with the first call for a pool, which returns no images until the manager
+// has sampled a full interval.
+func (ra *RBDAdmin) ImagePerfStats(pool, namespace string, sortBy PerfCounter) ([]ImagePerfStats, error) {
+	args := map[string]interface{}{
+		"prefix":  "rbd perf image stats",
+		"sort_by": string(sortBy),
+	}
+	if pool != "" {
+		args["pool_spec"] = NewLevelSpec(pool, namespace, "").spec
+	}
+
+	var reply struct {
+		Counters []PerfCounter                   `json:"stat_counters"`
+		Stats    map[string]map[string][]float64 `json:"stats"`
+	}
+	if err := ra.mgrCommand(args, &reply); err != nil {
+		return nil, err
+	}
+
+	var stats []ImagePerfStats
+	for poolSpec, images := range reply.Stats {
+		for image, values := range images {
+			s := ImagePerfStats{Pool: poolSpec, Image: image}
+			for i, value := range values {
+				if i < len(reply.Counters) {
+					s.set(reply.Counters[i], value)
+				}
+			}
+			stats = append(stats, s)
+		}
+	}
+	sort.SliceStable(stats, func(i, j int) bool {
+		return stats[i].get(sortBy) > stats[j].get(sortBy)
+	})
+	return stats, nil
+}
@@ -0,0 +1,119 @@
+package admin
+
+import (
+	"sort"
+	"strings"
+)
+
+// LevelSpec selects the images a schedule applies to: all images of the
+// cluster, of a pool, of a namespace of a pool, or a single image.
+type LevelSpec struct {
+	spec string
+}
+
+// NewLevelSpec returns the LevelSpec of the image in the namespace of the
+// pool. Leaving image, namespace and pool empty in turn widens the level to
+// the namespace, the pool and the whole cluster.
+func NewLevelSpec(pool, namespace, image string) LevelSpec {
+	var parts []string
+	if pool != "" {
+		parts = append(parts, pool)
+		if namespace != "" || image != "" {
+			parts = append(parts, namespace)
+		}
+		if image != "" {
+			parts = append(parts, image)
+		}
+	}
+	return LevelSpec{strings.Join(parts, "/")}
+}
+
+// String returns the level in the pool/namespace/image form of the rbd
+// command line tool.
+func (ls LevelSpec) String() string {
+	return ls.spec
+}
+
+// Interval is the period of a schedule, a number followed by m for
+// minutes, h for hours or d for days, like "30m".
+type Interval string
+
+// NoStartTime lets the first run of a schedule start at any time.
+const NoStartTime = ""
+
+// ScheduleItem is a period of a schedule. StartTime is the time of day, in
+// ISO 8601 format, the runs are aligned to, or NoStartTime.
+type ScheduleItem struct {
+	Interval  Interval `json:"interval"`
+	StartTime string   `json:"start_time"`
+}
+
+// ScheduleInfo is the schedule of a level.
+type ScheduleInfo struct {
+	// Level is the level the schedule is set on.
+	Level LevelSpec
+	Items []ScheduleItem
+}
+
+// ScheduledImage is the next run of a schedule for an image.
+type ScheduledImage struct {
+	// Image is the image in pool/namespace/image form.
+	Image        string `json:"image"`
+	ScheduleTime string `json:"schedule_time"`
+}
+
+// scheduleCommand adds or removes the schedule item of a level with the
+// command prefix.
+func (ra *RBDAdmin) scheduleCommand(prefix string, level LevelSpec, interval Interval, startTime string) error {
+	args := map[string]interface{}{
+		"prefix":     prefix,
+		"level_spec": level.spec,
+	}
+	if interval != "" {
+		args["interval"] = string(interval)
+	}
+	if startTime != NoStartTime {
+		args["start_time"] = startTime
+	}
+	return ra.mgrCommand(args, nil)
+}
+
+// listSchedules returns the schedules at and below level, ordered by level.
+func (ra *RBDAdmin) listSchedules(prefix string, level LevelSpec) ([]ScheduleInfo, error) {
+	var reply map[string]struct {
+		Name     string         `json:"name"`
+		Schedule []ScheduleItem `json:"schedule"`
+	}
+	err := ra.mgrCommand(map[string]interface{}{
+		"prefix":     prefix,
+		"level_spec": level.spec,
+	}, &reply)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]ScheduleInfo, 0, len(reply))
+	for _, r := range reply {
+		infos = append(infos, ScheduleInfo{
+			Level: LevelSpec{r.Name},
+			Items: r.Schedule,
+		})
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Level.spec < infos[j].Level.spec
+	})
+	return infos, nil
+}
+
+// scheduleStatus returns the next runs of the schedules at and below level.
+func (ra *RBDAdmin) scheduleStatus(prefix, key string, level LevelSpec) ([]ScheduledImage, error) {
+	var reply map[string][]ScheduledImage
+	err := ra.mgrCommand(map[string]interface{}{
+		"prefix":     prefix,
+		"level_spec": level.spec,
+	}, &reply)
+	if err != nil {
+		return nil, err
+	}
+	return reply[key], nil
+}
@@ -0,0 +1,107 @@
+package admin
+
+import (
+	"strings"
+)
+
+// TaskRefs identifies the image a task operates on.
+type TaskRefs struct {
+	// Action is the operation of the task, like "flatten", "remove" or
+	// "trash remove".
+	Action        string `json:"action"`
+	PoolName      string `json:"pool_name"`
+	PoolNamespace string `json:"pool_namespace"`
+	ImageName     string `json:"image_name"`
+	ImageID       string `json:"image_id"`
+}
+
+// TaskResponse describes a task run by the manager.
+type TaskResponse struct {
+	Sequence   int      `json:"sequence"`
+	ID         string   `json:"id"`
+	Message    string   `json:"message"`
+	Refs       TaskRefs `json:"refs"`
+	InProgress bool     `json:"in_progress"`
+	// Progress is the completed fraction of a task in progress, from 0
+	// to 1.
+	Progress      float64 `json:"progress"`
+	RetryAttempts int     `json:"retry_attempts"`
+	RetryTime     string  `json:"retry_time"`
+	RetryMessage  string  `json:"retry_message"`
+}
+
+// imageSpec returns the pool/namespace/image form of an image, or of an
+// image id, leaving the namespace out if it is empty.
+func imageSpec(pool, namespace, image string) string {
+	if namespace == "" {
+		return pool + "/" + image
+	}
+	return strings.Join([]string{pool, namespace, image}, "/")
+}
+
+func (ra *RBDAdmin) addTask(prefix, specKey, spec string) (*TaskResponse, error) {
+	task := &TaskResponse{}
+	err := ra.mgrCommand(map[string]interface{}{
+		"prefix": prefix,
+		specKey:  spec,
+	}, task)
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// AddFlattenTask has the manager flatten the clone image in the namespace
+// of the pool, detaching it from its parent. Adding a task that is queued
+// already returns the queued task.
+func (ra *RBDAdmin) AddFlattenTask(pool, namespace, image string) (*TaskResponse, error) {
+	return ra.addTask("rbd task add flatten", "image_spec",
+		imageSpec(pool, namespace, image))
+}
+
+// AddRemoveTask has the manager remove the image in the namespace of the
+// pool.
+func (ra *RBDAdmin) AddRemoveTask(pool, namespace, image string) (*TaskResponse, error) {
+	return ra.addTask("rbd task add remove", "image_spec",
+		imageSpec(pool, namespace, image))
+}
+
+// AddTrashRemoveTask has the manager remove the image with the id imageID
+// from the trash of the namespace of the pool.
+func (ra *RBDAdmin) AddTrashRemoveTask(pool, namespace, imageID string) (*TaskResponse, error) {
+	return ra.addTask("rbd task add trash remove", "image_id_spec",
+		imageSpec(pool, namespace, imageID))
+}
+
+// ListTasks returns the tasks that are queued or in progress.
+func (ra *RBDAdmin) ListTasks() ([]TaskResponse, error) {
+	var tasks []TaskResponse
+	err := ra.mgrCommand(map[string]interface{}{
+		"prefix": "rbd task list",
+	}, &tasks)
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// GetTask returns the queued or in progress task with the given id.
+func (ra *RBDAdmin) GetTask(id string) (*TaskResponse, error) {
+	task := &TaskResponse{}
+	err := ra.mgrCommand(map[string]interface{}{
+		"prefix":  "rbd task list",
+		"task_id": id,
+	}, task)
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// CancelTask cancels the queued or in progress task with the given id.
+func (ra *RBDAdmin) CancelTask(id string) error {
+	return ra.mgrCommand(map[string]interface{}{
+		"prefix":  "rbd task cancel",
+		"task_id": id,
+	}, nil)
+}
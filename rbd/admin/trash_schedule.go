@@ -0,0 +1,47 @@
+package admin
+
+// AddTrashPurgeSchedule schedules purging the expired images from the trash
+// of the pools or namespaces selected by level every interval. The level
+// must not select an image.
+func (ra *RBDAdmin) AddTrashPurgeSchedule(level LevelSpec, interval Interval, startTime string) error {
+	return ra.scheduleCommand("rbd trash purge schedule add", level,
+		interval, startTime)
+}
+
+// RemoveTrashPurgeSchedule removes the trash purge schedule of level with
+// the given interval and start time. An empty interval removes all
+// schedules of level.
+func (ra *RBDAdmin) RemoveTrashPurgeSchedule(level LevelSpec, interval Interval, startTime string) error {
+	return ra.scheduleCommand("rbd trash purge schedule remove", level,
+		interval, startTime)
+}
+
+// ListTrashPurgeSchedules returns the trash purge schedules set on level and
+// on the levels below it.
+func (ra *RBDAdmin) ListTrashPurgeSchedules(level LevelSpec) ([]ScheduleInfo, error) {
+	return ra.listSchedules("rbd trash purge schedule list", level)
+}
+
+// ScheduledPool is the next trash purge of a pool or namespace.
+type ScheduledPool struct {
+	PoolID       int64  `json:"pool_id"`
+	PoolName     string `json:"pool_name"`
+	Namespace    string `json:"namespace"`
+	ScheduleTime string `json:"schedule_time"`
+}
+
+// TrashPurgeScheduleStatus returns the time of the next trash purge of each
+// scheduled pool and namespace selected by level.
+func (ra *RBDAdmin) TrashPurgeScheduleStatus(level LevelSpec) ([]ScheduledPool, error) {
+	var reply struct {
+		Scheduled []ScheduledPool `json:"scheduled"`
+	}
+	err := ra.mgrCommand(map[string]interface{}{
+		"prefix":     "rbd trash purge schedule status",
+		"level_spec": level.spec,
+	}, &reply)
+	if err != nil {
+		return nil, err
+	}
+	return reply.Scheduled, nil
+}
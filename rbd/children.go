@@ -0,0 +1,81 @@
+package rbd
+
+// #cgo LDFLAGS: -lrbd
+// #include <errno.h>
+// #include <stdlib.h>
+// #include <stdbool.h>
+// #include <rados/librados.h>
+// #include <rbd/librbd.h>
+import "C"
+
+// LinkedImage identifies an image that is linked to another image through
+// cloning, possibly in a different pool or namespace. Trash is true if the
+// image has been moved to the trash.
+type LinkedImage struct {
+	PoolId        int64
+	PoolName      string
+	PoolNamespace string
+	ImageId       string
+	ImageName     string
+	Trash         bool
+}
+
+type linkedImageListFunc func(rbd_image C.rbd_image_t,
+	images *C.rbd_linked_image_spec_t, max_images *C.size_t) C.int
+
+func listLinkedImages(image *Image, listFn linkedImageListFunc) ([]LinkedImage, error) {
+	if image.image == nil {
+		return nil, RbdErrorImageNotOpen
+	}
+
+	var c_max_images C.size_t = 16
+	for {
+		c_images := make([]C.rbd_linked_image_spec_t, c_max_images)
+		ret := listFn(image.image, &c_images[0], &c_max_images)
+		if ret == -C.ERANGE {
+			continue
+		} else if ret < 0 {
+			return nil, GetError(ret)
+		}
+
+		images := make([]LinkedImage, c_max_images)
+		for i := range images {
+			c_image := &c_images[i]
+			images[i] = LinkedImage{
+				PoolId:        int64(c_image.pool_id),
+				PoolName:      C.GoString(c_image.pool_name),
+				PoolNamespace: C.GoString(c_image.pool_namespace),
+				ImageId:       C.GoString(c_image.image_id),
+				ImageName:     C.GoString(c_image.image_name),
+				Trash:         bool(c_image.trash),
+			}
+		}
+		C.rbd_linked_image_spec_list_cleanup(&c_images[0], c_max_images)
+		return images, nil
+	}
+}
+
+// ListLinkedChildren returns the clones of the snapshot the image is set to,
+// across all pools and namespaces, including clones that are in the trash.
+//
+// int rbd_list_children3(rbd_image_t image, rbd_linked_image_spec_t *images, size_t *max_images);
+// void rbd_linked_image_spec_list_cleanup(rbd_linked_image_spec_t *images, size_t num_images);
+func (image *Image) ListLinkedChildren() ([]LinkedImage, error) {
+	return listLinkedImages(image, func(rbd_image C.rbd_image_t,
+		images *C.rbd_linked_image_spec_t, max_images *C.size_t) C.int {
+		return C.rbd_list_children3(rbd_image, images, max_images)
+	})
+}
+
+// ListDescendants returns the clones of the snapshot the image is set to and,
+// recursively, the clones of their snapshots. If the image is not set to a
+// snapshot, the descendants of all its snapshots are returned.
+//
+// int rbd_list_descendants(rbd_image_t image, rbd_linked_image_spec_t *images, size_t *max_images);
+// void rbd_linked_image_spec_list_cleanup(rbd_linked_image_spec_t *images, size_t num_images);
+func (image *Image) ListDescendants() ([]LinkedImage, error) {
+	return listLinkedImages(image, func(rbd_image C.rbd_image_t,
+		images *C.rbd_linked_image_spec_t, max_images *C.size_t) C.int {
+		return C.rbd_list_descendants(rbd_image, images, max_images)
+	})
+}
@@ -0,0 +1,92 @@
+package rbd
+
+// #cgo LDFLAGS: -lrbd
+// #include <errno.h>
+// #include <stdlib.h>
+// #include <rados/librados.h>
+// #include <rbd/librbd.h>
+import "C"
+
+import (
+	"github.com/noahdesu/go-ceph/rados"
+)
+
+// ConfigSource is where the effective value of a librbd option comes from.
+type ConfigSource int
+
+const (
+	// ConfigSourceConfig is the cluster or client configuration.
+	ConfigSourceConfig = ConfigSource(C.RBD_CONFIG_SOURCE_CONFIG)
+	// ConfigSourcePool is a "conf_" pool metadata override.
+	ConfigSourcePool = ConfigSource(C.RBD_CONFIG_SOURCE_POOL)
+	// ConfigSourceImage is a "conf_" image metadata override.
+	ConfigSourceImage = ConfigSource(C.RBD_CONFIG_SOURCE_IMAGE)
+)
+
+// ConfigOption is the effective value of a librbd option.
+type ConfigOption struct {
+	Name   string
+	Value  string
+	Source ConfigSource
+}
+
+func newConfigOptions(c_options []C.rbd_config_option_t) []ConfigOption {
+	options := make([]ConfigOption, len(c_options))
+	for i, o := range c_options {
+		options[i] = ConfigOption{
+			Name:   C.GoString(o.name),
+			Value:  C.GoString(o.value),
+			Source: ConfigSource(o.source),
+		}
+	}
+	return options
+}
+
+// PoolConfigList returns the effective librbd configuration for images in
+// the pool.
+//
+// int rbd_config_pool_list(rados_ioctx_t io_ctx, rbd_config_option_t *options, int *max_options);
+// void rbd_config_pool_list_cleanup(rbd_config_option_t *options, int max_options);
+func PoolConfigList(ioctx *rados.IOContext) ([]ConfigOption, error) {
+	var c_max_options C.int = 256
+	for {
+		c_options := make([]C.rbd_config_option_t, c_max_options)
+		ret := C.rbd_config_pool_list(C.rados_ioctx_t(ioctx.Pointer()),
+			&c_options[0], &c_max_options)
+		if ret == -C.ERANGE {
+			continue
+		} else if ret < 0 {
+			return nil, RBDError(ret)
+		}
+
+		options := newConfigOptions(c_options[:c_max_options])
+		C.rbd_config_pool_list_cleanup(&c_options[0], c_max_options)
+		return options, nil
+	}
+}
+
+// ConfigList returns the effective librbd configuration of the image.
+//
+// int rbd_config_image_list(rbd_image_t image, rbd_config_option_t *options, int *max_options);
+// void rbd_config_image_list_cleanup(rbd_config_option_t *options, int max_options);
+func (image *Image) ConfigList() ([]ConfigOption, error) {
+	if image.image == nil {
+		return nil, RbdErrorImageNotOpen
+	}
+
+	var c_max_options C.int = 256
+	for {
+		c_options := make([]C.rbd_config_option_t, c_max_options)
+		ret := C.rbd_config_image_list(image.image, &c_options[0],
+			&c_max_options)
+		if ret == -C.ERANGE {
+			continue
+		} else if ret < 0 {
+			return nil, RBDError(ret)
+		}
+
+		options := newConfigOptions(c_options[:c_max_options])
+		C.rbd_config_image_list_cleanup(&c_options[0], c_max_options)
+		return options, nil
+	}
+}
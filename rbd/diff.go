@@ -0,0 +1,80 @@
+package rbd
+
+/*
+#cgo LDFLAGS: -lrbd
+#include <errno.h>
+#include <stdint.h>
+#include <stdlib.h>
+#include <rados/librados.h>
+#include <rbd/librbd.h>
+
+extern int diffIterateCallback(uint64_t offset, size_t length, int exists,
+		uintptr_t index);
+
+static inline int wrap_rbd_diff_iterate2(rbd_image_t image,
+		const char *fromsnapname, uint64_t ofs, uint64_t len,
+		uint8_t include_parent, uint8_t whole_object, uintptr_t index) {
+	return rbd_diff_iterate2(image, fromsnapname, ofs, len, include_parent,
+		whole_object,
+		(int (*)(uint64_t, size_t, int, void *))diffIterateCallback,
+		(void *)index);
+}
+*/
+import "C"
+
+import (
+	"github.com/noahdesu/go-ceph/internal/callbacks"
+	"unsafe"
+)
+
+// DiffIterateFunc is the type of the function called for each extent that
+// changed between two points in time of an image. exists is false if the
+// extent was discarded.
+type DiffIterateFunc func(offset, length uint64, exists bool)
+
+var diffIterateCallbacks = callbacks.New()
+
+//export diffIterateCallback
+func diffIterateCallback(offset C.uint64_t, length C.size_t, exists C.int,
+	index C.uintptr_t) C.int {
+	v := diffIterateCallbacks.Lookup(uintptr(index))
+	if diffFn, ok := v.(DiffIterateFunc); ok && diffFn != nil {
+		diffFn(uint64(offset), uint64(length), exists != 0)
+	}
+	return 0
+}
+
+// DiffIterate calls diffFn for each extent in [offset, offset+length) that
+// changed since the snapshot fromSnap, or since the creation of the image if
+// fromSnap is empty. The image is compared as of the snapshot it is set to.
+// With wholeObject the changes are reported per backing object, which uses
+// the object map and is much faster on images with RbdFeatureFastDiff.
+//
+// int rbd_diff_iterate2(rbd_image_t image, const char *fromsnapname, uint64_t ofs, uint64_t len, uint8_t include_parent, uint8_t whole_object, int (*cb)(uint64_t, size_t, int, void *), void *arg);
+func (image *Image) DiffIterate(fromSnap string, offset, length uint64,
+	includeParent, wholeObject bool, diffFn DiffIterateFunc) error {
+	if image.image == nil {
+		return RbdErrorImageNotOpen
+	}
+
+	var c_fromsnap *C.char
+	if fromSnap != "" {
+		c_fromsnap = C.CString(fromSnap)
+		defer C.free(unsafe.Pointer(c_fromsnap))
+	}
+
+	var c_include_parent, c_whole_object C.uint8_t
+	if includeParent {
+		c_include_parent = 1
+	}
+	if wholeObject {
+		c_whole_object = 1
+	}
+
+	index := diffIterateCallbacks.Add(diffFn)
+	defer diffIterateCallbacks.Remove(index)
+
+	return GetError(C.wrap_rbd_diff_iterate2(image.image, c_fromsnap,
+		C.uint64_t(offset), C.uint64_t(length), c_include_parent,
+		c_whole_object, C.uintptr_t(index)))
+}
@@ -0,0 +1,111 @@
+package rbd
+
+import (
+	"github.com/noahdesu/go-ceph/rados"
+	"sort"
+)
+
+// DiskUsage is the space consumed by an image or by one of its snapshots.
+// Snapshot is empty for the image head. UsedBytes of a snapshot only counts
+// the data that changed since the previous snapshot, so the used bytes of an
+// image are the sum over its snapshots and its head.
+type DiskUsage struct {
+	Name             string
+	Snapshot         string
+	ProvisionedBytes uint64
+	UsedBytes        uint64
+}
+
+// DiskUsage returns the provisioned and used bytes of each snapshot of the
+// image, oldest first, followed by those of the image head. Images without
+// RbdFeatureFastDiff are supported but have to be read in full.
+func (image *Image) DiskUsage() ([]DiskUsage, error) {
+	if image.image == nil {
+		return nil, RbdErrorImageNotOpen
+	}
+
+	features, err := image.GetFeatures()
+	if err != nil {
+		return nil, err
+	}
+	wholeObject := features&RbdFeatureFastDiff != 0
+
+	snaps, err := image.GetSnapshotNames()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(snaps, func(i, j int) bool { return snaps[i].Id < snaps[j].Id })
+
+	var usage []DiskUsage
+	fromSnap := ""
+	for _, snap := range snaps {
+		snapImage, err := OpenImageAtSnapshot(image.ioctx, image.name, snap.Name)
+		if err != nil {
+			return nil, err
+		}
+		used, err := snapImage.usedBytes(fromSnap, snap.Size, wholeObject)
+		snapImage.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		usage = append(usage, DiskUsage{
+			Name:             image.name,
+			Snapshot:         snap.Name,
+			ProvisionedBytes: snap.Size,
+			UsedBytes:        used,
+		})
+		fromSnap = snap.Name
+	}
+
+	size, err := image.GetSize()
+	if err != nil {
+		return nil, err
+	}
+	used, err := image.usedBytes(fromSnap, size, wholeObject)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(usage, DiskUsage{
+		Name:             image.name,
+		ProvisionedBytes: size,
+		UsedBytes:        used,
+	}), nil
+}
+
+func (image *Image) usedBytes(fromSnap string, size uint64,
+	wholeObject bool) (used uint64, err error) {
+	err = image.DiffIterate(fromSnap, 0, size, false, wholeObject,
+		func(offset, length uint64, exists bool) {
+			if exists {
+				used += length
+			}
+		})
+	return used, err
+}
+
+// GetPoolDiskUsage returns the disk usage of all images in the pool, as
+// returned by Image.DiskUsage.
+func GetPoolDiskUsage(ioctx *rados.IOContext) ([]DiskUsage, error) {
+	names, err := GetImageNames(ioctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var usage []DiskUsage
+	for _, name := range names {
+		image, err := OpenImageReadOnly(ioctx, name)
+		if err != nil {
+			return nil, err
+		}
+		imageUsage, err := image.DiskUsage()
+		image.Close()
+		if err != nil {
+			return nil, err
+		}
+		usage = append(usage, imageUsage...)
+	}
+
+	return usage, nil
+}
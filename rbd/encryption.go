@@ -0,0 +1,118 @@
+package rbd
+
+// #cgo LDFLAGS: -lrbd
+// #include <errno.h>
+// #include <stdlib.h>
+// #include <rados/librados.h>
+// #include <rbd/librbd.h>
+import "C"
+
+import (
+	"unsafe"
+)
+
+// EncryptionAlgorithm is the cipher used to encrypt the data of an image.
+type EncryptionAlgorithm int
+
+const (
+	EncryptionAlgorithmAES128 = EncryptionAlgorithm(C.RBD_ENCRYPTION_ALGORITHM_AES128)
+	EncryptionAlgorithmAES256 = EncryptionAlgorithm(C.RBD_ENCRYPTION_ALGORITHM_AES256)
+)
+
+// EncryptionOptions is implemented by the option types of the supported
+// encryption formats: EncryptionOptionsLUKS1, EncryptionOptionsLUKS2 and
+// EncryptionOptionsLUKS.
+type EncryptionOptions interface {
+	// toC returns the format and a C allocated options struct of size
+	// bytes, which must be released with free.
+	toC() (format C.rbd_encryption_format_t, opts unsafe.Pointer, size C.size_t, free func())
+}
+
+// EncryptionOptionsLUKS1 are the options of the LUKS1 encryption format.
+type EncryptionOptionsLUKS1 struct {
+	Alg        EncryptionAlgorithm
+	Passphrase []byte
+}
+
+// EncryptionOptionsLUKS2 are the options of the LUKS2 encryption format.
+type EncryptionOptionsLUKS2 struct {
+	Alg        EncryptionAlgorithm
+	Passphrase []byte
+}
+
+// EncryptionOptionsLUKS loads an image that is encrypted with either LUKS1
+// or LUKS2. It can not be used to format an image.
+type EncryptionOptionsLUKS struct {
+	Passphrase []byte
+}
+
+func (opts EncryptionOptionsLUKS1) toC() (C.rbd_encryption_format_t, unsafe.Pointer, C.size_t, func()) {
+	var c_opts *C.rbd_encryption_luks1_format_options_t
+	size := C.size_t(unsafe.Sizeof(*c_opts))
+	c_opts = (*C.rbd_encryption_luks1_format_options_t)(C.malloc(size))
+	c_opts.alg = C.rbd_encryption_algorithm_t(opts.Alg)
+	c_opts.passphrase = C.CString(string(opts.Passphrase))
+	c_opts.passphrase_size = C.size_t(len(opts.Passphrase))
+	return C.RBD_ENCRYPTION_FORMAT_LUKS1, unsafe.Pointer(c_opts), size, func() {
+		C.free(unsafe.Pointer(c_opts.passphrase))
+		C.free(unsafe.Pointer(c_opts))
+	}
+}
+
+func (opts EncryptionOptionsLUKS2) toC() (C.rbd_encryption_format_t, unsafe.Pointer, C.size_t, func()) {
+	var c_opts *C.rbd_encryption_luks2_format_options_t
+	size := C.size_t(unsafe.Sizeof(*c_opts))
+	c_opts = (*C.rbd_encryption_luks2_format_options_t)(C.malloc(size))
+	c_opts.alg = C.rbd_encryption_algorithm_t(opts.Alg)
+	c_opts.passphrase = C.CString(string(opts.Passphrase))
+	c_opts.passphrase_size = C.size_t(len(opts.Passphrase))
+	return C.RBD_ENCRYPTION_FORMAT_LUKS2, unsafe.Pointer(c_opts), size, func() {
+		C.free(unsafe.Pointer(c_opts.passphrase))
+		C.free(unsafe.Pointer(c_opts))
+	}
+}
+
+func (opts EncryptionOptionsLUKS) toC() (C.rbd_encryption_format_t, unsafe.Pointer, C.size_t, func()) {
+	var c_opts *C.rbd_encryption_luks_format_options_t
+	size := C.size_t(unsafe.Sizeof(*c_opts))
+	c_opts = (*C.rbd_encryption_luks_format_options_t)(C.malloc(size))
+	c_opts.passphrase = C.CString(string(opts.Passphrase))
+	c_opts.passphrase_size = C.size_t(len(opts.Passphrase))
+	return C.RBD_ENCRYPTION_FORMAT_LUKS, unsafe.Pointer(c_opts), size, func() {
+		C.free(unsafe.Pointer(c_opts.passphrase))
+		C.free(unsafe.Pointer(c_opts))
+	}
+}
+
+// EncryptionFormat formats the image for encryption. All data previously
+// stored in the image is lost. The image must be opened and loaded with
+// EncryptionLoad before encrypted data can be read or written.
+//
+// int rbd_encryption_format(rbd_image_t image, rbd_encryption_format_t format, rbd_encryption_options_t opts, size_t opts_size);
+func (image *Image) EncryptionFormat(opts EncryptionOptions) error {
+	if image.image == nil {
+		return RbdErrorImageNotOpen
+	}
+
+	c_format, c_opts, c_size, free := opts.toC()
+	defer free()
+
+	return GetError(C.rbd_encryption_format(image.image, c_format,
+		C.rbd_encryption_options_t(c_opts), c_size))
+}
+
+// EncryptionLoad enables encryption on the opened image, so that subsequent
+// I/O is transparently encrypted and decrypted.
+//
+// int rbd_encryption_load(rbd_image_t image, rbd_encryption_format_t format, rbd_encryption_options_t opts, size_t opts_size);
+func (image *Image) EncryptionLoad(opts EncryptionOptions) error {
+	if image.image == nil {
+		return RbdErrorImageNotOpen
+	}
+
+	c_format, c_opts, c_size, free := opts.toC()
+	defer free()
+
+	return GetError(C.rbd_encryption_load(image.image, c_format,
+		C.rbd_encryption_options_t(c_opts), c_size))
+}
@@ -0,0 +1,235 @@
+package rbd
+
+// #cgo LDFLAGS: -lrbd
+// #include <errno.h>
+// #include <stdlib.h>
+// #include <rados/librados.h>
+// #include <rbd/librbd.h>
+import "C"
+
+import (
+	"github.com/noahdesu/go-ceph/rados"
+	"unsafe"
+)
+
+// GroupImageState indicates whether an image is fully attached to a group.
+type GroupImageState int
+
+const (
+	GroupImageStateAttached   = GroupImageState(C.RBD_GROUP_IMAGE_STATE_ATTACHED)
+	GroupImageStateIncomplete = GroupImageState(C.RBD_GROUP_IMAGE_STATE_INCOMPLETE)
+)
+
+// GroupImageInfo describes an image that is a member of a group.
+type GroupImageInfo struct {
+	Name   string
+	PoolId int64
+	State  GroupImageState
+}
+
+// GroupSnapState indicates whether a group snapshot was completed.
+type GroupSnapState int
+
+const (
+	GroupSnapStateIncomplete = GroupSnapState(C.RBD_GROUP_SNAP_STATE_INCOMPLETE)
+	GroupSnapStateComplete   = GroupSnapState(C.RBD_GROUP_SNAP_STATE_COMPLETE)
+)
+
+// GroupSnapInfo describes a snapshot of a group.
+type GroupSnapInfo struct {
+	Name  string
+	State GroupSnapState
+}
+
+// GroupCreate creates a new, empty group.
+//
+// int rbd_group_create(rados_ioctx_t p, const char *name);
+func GroupCreate(ioctx *rados.IOContext, name string) error {
+	var c_name *C.char = C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	return GetError(C.rbd_group_create(C.rados_ioctx_t(ioctx.Pointer()), c_name))
+}
+
+// GroupRemove removes a group. Images in the group are not removed.
+//
+// int rbd_group_remove(rados_ioctx_t p, const char *name);
+func GroupRemove(ioctx *rados.IOContext, name string) error {
+	var c_name *C.char = C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	return GetError(C.rbd_group_remove(C.rados_ioctx_t(ioctx.Pointer()), c_name))
+}
+
+// GroupRename renames a group.
+//
+// int rbd_group_rename(rados_ioctx_t p, const char *src_name, const char *dest_name);
+func GroupRename(ioctx *rados.IOContext, srcName, destName string) error {
+	var c_src_name *C.char = C.CString(srcName)
+	var c_dest_name *C.char = C.CString(destName)
+	defer C.free(unsafe.Pointer(c_src_name))
+	defer C.free(unsafe.Pointer(c_dest_name))
+
+	return GetError(C.rbd_group_rename(C.rados_ioctx_t(ioctx.Pointer()),
+		c_src_name, c_dest_name))
+}
+
+// GroupList returns the names of the groups in the pool.
+//
+// int rbd_group_list(rados_ioctx_t p, char *names, size_t *size);
+func GroupList(ioctx *rados.IOContext) (names []string, err error) {
+	buf := make([]byte, 1024)
+	for {
+		size := C.size_t(len(buf))
+		ret := C.rbd_group_list(C.rados_ioctx_t(ioctx.Pointer()),
+			(*C.char)(unsafe.Pointer(&buf[0])), &size)
+		if ret == -C.ERANGE {
+			buf = make([]byte, size)
+			continue
+		} else if ret < 0 {
+			return nil, RBDError(ret)
+		}
+
+		if size == 0 {
+			return nil, nil
+		}
+		return split(buf[:size]), nil
+	}
+}
+
+// GroupImageAdd adds the image imageName in imageIoctx to the group.
+//
+// int rbd_group_image_add(rados_ioctx_t group_p, const char *group_name, rados_ioctx_t image_p, const char *image_name);
+func GroupImageAdd(groupIoctx *rados.IOContext, groupName string,
+	imageIoctx *rados.IOContext, imageName string) error {
+	var c_group_name *C.char = C.CString(groupName)
+	var c_image_name *C.char = C.CString(imageName)
+	defer C.free(unsafe.Pointer(c_group_name))
+	defer C.free(unsafe.Pointer(c_image_name))
+
+	return GetError(C.rbd_group_image_add(
+		C.rados_ioctx_t(groupIoctx.Pointer()), c_group_name,
+		C.rados_ioctx_t(imageIoctx.Pointer()), c_image_name))
+}
+
+// GroupImageRemove removes the image imageName in imageIoctx from the group.
+//
+// int rbd_group_image_remove(rados_ioctx_t group_p, const char *group_name, rados_ioctx_t image_p, const char *image_name);
+func GroupImageRemove(groupIoctx *rados.IOContext, groupName string,
+	imageIoctx *rados.IOContext, imageName string) error {
+	var c_group_name *C.char = C.CString(groupName)
+	var c_image_name *C.char = C.CString(imageName)
+	defer C.free(unsafe.Pointer(c_group_name))
+	defer C.free(unsafe.Pointer(c_image_name))
+
+	return GetError(C.rbd_group_image_remove(
+		C.rados_ioctx_t(groupIoctx.Pointer()), c_group_name,
+		C.rados_ioctx_t(imageIoctx.Pointer()), c_image_name))
+}
+
+// GroupImageList returns the images that are members of the group.
+//
+// int rbd_group_image_list(rados_ioctx_t group_p, const char *group_name, rbd_group_image_info_t *images, size_t group_image_info_size, size_t *num_entries);
+// int rbd_group_image_list_cleanup(rbd_group_image_info_t *images, size_t group_image_info_size, size_t num_entries);
+func GroupImageList(ioctx *rados.IOContext, groupName string) ([]GroupImageInfo, error) {
+	var c_group_name *C.char = C.CString(groupName)
+	defer C.free(unsafe.Pointer(c_group_name))
+
+	var c_num_entries C.size_t = 32
+	for {
+		c_images := make([]C.rbd_group_image_info_t, c_num_entries)
+		c_info_size := C.size_t(unsafe.Sizeof(c_images[0]))
+		ret := C.rbd_group_image_list(C.rados_ioctx_t(ioctx.Pointer()),
+			c_group_name, &c_images[0], c_info_size, &c_num_entries)
+		if ret == -C.ERANGE {
+			continue
+		} else if ret < 0 {
+			return nil, GetError(ret)
+		}
+
+		images := make([]GroupImageInfo, c_num_entries)
+		for i := range images {
+			images[i] = GroupImageInfo{
+				Name:   C.GoString(c_images[i].name),
+				PoolId: int64(c_images[i].pool),
+				State:  GroupImageState(c_images[i].state),
+			}
+		}
+		C.rbd_group_image_list_cleanup(&c_images[0], c_info_size,
+			c_num_entries)
+		return images, nil
+	}
+}
+
+// GroupSnapCreate takes a crash-consistent snapshot of all images in the
+// group.
+//
+// int rbd_group_snap_create(rados_ioctx_t group_p, const char *group_name, const char *snap_name);
+func GroupSnapCreate(ioctx *rados.IOContext, groupName, snapName string) error {
+	var c_group_name *C.char = C.CString(groupName)
+	var c_snap_name *C.char = C.CString(snapName)
+	defer C.free(unsafe.Pointer(c_group_name))
+	defer C.free(unsafe.Pointer(c_snap_name))
+
+	return GetError(C.rbd_group_snap_create(C.rados_ioctx_t(ioctx.Pointer()),
+		c_group_name, c_snap_name))
+}
+
+// GroupSnapRemove removes a snapshot of the group.
+//
+// int rbd_group_snap_remove(rados_ioctx_t group_p, const char *group_name, const char *snap_name);
+func GroupSnapRemove(ioctx *rados.IOContext, groupName, snapName string) error {
+	var c_group_name *C.char = C.CString(groupName)
+	var c_snap_name *C.char = C.CString(snapName)
+	defer C.free(unsafe.Pointer(c_group_name))
+	defer C.free(unsafe.Pointer(c_snap_name))
+
+	return GetError(C.rbd_group_snap_remove(C.rados_ioctx_t(ioctx.Pointer()),
+		c_group_name, c_snap_name))
+}
+
+// GroupSnapRollback rolls all images in the group back to the group
+// snapshot.
+//
+// int rbd_group_snap_rollback(rados_ioctx_t group_p, const char *group_name, const char *snap_name);
+func GroupSnapRollback(ioctx *rados.IOContext, groupName, snapName string) error {
+	var c_group_name *C.char = C.CString(groupName)
+	var c_snap_name *C.char = C.CString(snapName)
+	defer C.free(unsafe.Pointer(c_group_name))
+	defer C.free(unsafe.Pointer(c_snap_name))
+
+	return GetError(C.rbd_group_snap_rollback(C.rados_ioctx_t(ioctx.Pointer()),
+		c_group_name, c_snap_name))
+}
+
+// GroupSnapList returns the snapshots of the group.
+//
+// int rbd_group_snap_list(rados_ioctx_t group_p, const char *group_name, rbd_group_snap_info_t *snaps, size_t group_snap_info_size, size_t *num_entries);
+// int rbd_group_snap_list_cleanup(rbd_group_snap_info_t *snaps, size_t group_snap_info_size, size_t num_entries);
+func GroupSnapList(ioctx *rados.IOContext, groupName string) ([]GroupSnapInfo, error) {
+	var c_group_name *C.char = C.CString(groupName)
+	defer C.free(unsafe.Pointer(c_group_name))
+
+	var c_num_entries C.size_t = 32
+	for {
+		c_snaps := make([]C.rbd_group_snap_info_t, c_num_entries)
+		c_info_size := C.size_t(unsafe.Sizeof(c_snaps[0]))
+		ret := C.rbd_group_snap_list(C.rados_ioctx_t(ioctx.Pointer()),
+			c_group_name, &c_snaps[0], c_info_size, &c_num_entries)
+		if ret == -C.ERANGE {
+			continue
+		} else if ret < 0 {
+			return nil, GetError(ret)
+		}
+
+		snaps := make([]GroupSnapInfo, c_num_entries)
+		for i := range snaps {
+			snaps[i] = GroupSnapInfo{
+				Name:  C.GoString(c_snaps[i].name),
+				State: GroupSnapState(c_snaps[i].state),
+			}
+		}
+		C.rbd_group_snap_list_cleanup(&c_snaps[0], c_info_size, c_num_entries)
+		return snaps, nil
+	}
+}
@@ -0,0 +1,268 @@
+package rbd
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// librbd has no C API for journal introspection, so the journal header
+// object is read directly. Its omap holds the header fields and the
+// registered clients using the cls_journal encodings.
+const (
+	journalHeaderPrefix   = "journal."
+	journalKeyOrder       = "order"
+	journalKeySplayWidth  = "splay_width"
+	journalKeyPoolId      = "pool_id"
+	journalKeyMinimumSet  = "minimum_set"
+	journalKeyActiveSet   = "active_set"
+	journalKeyClient      = "client_"
+	journalMasterClientId = ""
+)
+
+var RbdErrorJournalDecode = errors.New("RBD journal header malformed")
+
+// JournalClientState is the state of a client registered with a journal.
+type JournalClientState uint8
+
+const (
+	JournalClientStateConnected    = JournalClientState(0)
+	JournalClientStateDisconnected = JournalClientState(1)
+)
+
+// JournalPosition is the position of an entry within a journal.
+type JournalPosition struct {
+	ObjectNumber uint64
+	TagTid       uint64
+	EntryTid     uint64
+}
+
+// JournalClient is a client registered with a journal, along with the
+// positions up to which it has committed the journal entries. The image
+// itself is registered as the master client with an empty id, rbd-mirror
+// peers are registered under their mirror uuid.
+type JournalClient struct {
+	Id              string
+	State           JournalClientState
+	CommitPositions []JournalPosition
+}
+
+// JournalInfo describes the journal of an image with the
+// RbdFeatureJournaling feature.
+type JournalInfo struct {
+	Order      uint8
+	SplayWidth uint8
+	// PoolId is the pool of the journal data objects, or -1 if they are
+	// stored in the pool of the image.
+	PoolId     int64
+	MinimumSet uint64
+	ActiveSet  uint64
+	Clients    []JournalClient
+}
+
+// GetJournalInfo returns the header information and the registered clients
+// of the journal of the image.
+func (image *Image) GetJournalInfo() (*JournalInfo, error) {
+	id, err := image.GetId()
+	if err != nil {
+		return nil, err
+	}
+
+	omap, err := image.ioctx.GetAllOmapValues(journalHeaderPrefix+id, "", "", 64)
+	if err != nil {
+		return nil, err
+	}
+	if len(omap) == 0 {
+		return nil, RbdErrorNotFound
+	}
+
+	return decodeJournalHeader(omap)
+}
+
+// Client returns the registered client with the given id.
+func (info *JournalInfo) Client(id string) (*JournalClient, bool) {
+	for i := range info.Clients {
+		if info.Clients[i].Id == id {
+			return &info.Clients[i], true
+		}
+	}
+	return nil, false
+}
+
+// EntriesBehind returns how many journal entries the client with the given
+// id has not yet committed, relative to the master client. It returns false
+// if either client is unknown or if their last positions are in different
+// tags, in which case the lag can not be expressed as a number of entries.
+func (info *JournalInfo) EntriesBehind(id string) (uint64, bool) {
+	master, ok := info.Client(journalMasterClientId)
+	if !ok {
+		return 0, false
+	}
+	client, ok := info.Client(id)
+	if !ok {
+		return 0, false
+	}
+
+	if len(master.CommitPositions) == 0 {
+		return 0, true
+	}
+	if len(client.CommitPositions) == 0 {
+		return master.CommitPositions[0].EntryTid + 1, true
+	}
+
+	mp, cp := master.CommitPositions[0], client.CommitPositions[0]
+	if mp.TagTid != cp.TagTid {
+		return 0, false
+	}
+	if mp.EntryTid <= cp.EntryTid {
+		return 0, true
+	}
+	return mp.EntryTid - cp.EntryTid, true
+}
+
+func decodeJournalHeader(omap map[string][]byte) (*JournalInfo, error) {
+	info := &JournalInfo{}
+	for key, value := range omap {
+		d := &journalDecoder{buf: value}
+		switch {
+		case key == journalKeyOrder:
+			info.Order = d.u8()
+		case key == journalKeySplayWidth:
+			info.SplayWidth = d.u8()
+		case key == journalKeyPoolId:
+			info.PoolId = int64(d.u64())
+		case key == journalKeyMinimumSet:
+			info.MinimumSet = d.u64()
+		case key == journalKeyActiveSet:
+			info.ActiveSet = d.u64()
+		case strings.HasPrefix(key, journalKeyClient):
+			info.Clients = append(info.Clients, d.client())
+		default:
+			continue
+		}
+		if err := d.finish(); err != nil {
+			return nil, fmt.Errorf("%v: key %q: %v", RbdErrorJournalDecode, key, err)
+		}
+	}
+	return info, nil
+}
+
+// journalStructVersion is the version of the cls_journal structs that is
+// decoded. Newer versions that are compatible with it only append fields,
+// which are skipped.
+const journalStructVersion = 1
+
+// journalDecoder decodes the little-endian Ceph encoding of the cls_journal
+// types. The first error is kept and makes all further reads return zero.
+type journalDecoder struct {
+	buf []byte
+	err error
+	// version is the struct_v of the struct being decoded
+	version uint8
+}
+
+func (d *journalDecoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if len(d.buf) < n {
+		d.err = fmt.Errorf("need %d bytes, have %d", n, len(d.buf))
+		return nil
+	}
+	b := d.buf[:n]
+	d.buf = d.buf[n:]
+	return b
+}
+
+func (d *journalDecoder) u8() uint8 {
+	if b := d.next(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (d *journalDecoder) u32() uint32 {
+	if b := d.next(4); b != nil {
+		return binary.LittleEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (d *journalDecoder) u64() uint64 {
+	if b := d.next(8); b != nil {
+		return binary.LittleEndian.Uint64(b)
+	}
+	return 0
+}
+
+func (d *journalDecoder) bytes() []byte {
+	return d.next(int(d.u32()))
+}
+
+// finish checks that the whole value has been decoded.
+func (d *journalDecoder) finish() error {
+	if d.err == nil && len(d.buf) != 0 {
+		d.err = fmt.Errorf("%d trailing bytes", len(d.buf))
+	}
+	return d.err
+}
+
+// start decodes an ENCODE_START header and returns a decoder limited to the
+// encoded struct. It fails if the struct can not be decoded as
+// journalStructVersion or if its length exceeds the value.
+func (d *journalDecoder) start() *journalDecoder {
+	version := d.u8()
+	compat := d.u8()
+	body := d.bytes()
+	if d.err == nil {
+		switch {
+		case version == 0 || compat == 0 || compat > version:
+			d.err = fmt.Errorf("bad struct version %d, compat %d", version, compat)
+		case compat > journalStructVersion:
+			d.err = fmt.Errorf("struct version %d needs version %d to decode, have %d",
+				version, compat, journalStructVersion)
+		}
+	}
+	return &journalDecoder{buf: body, err: d.err, version: version}
+}
+
+// end finishes decoding the struct of s, started by d.start. A struct of
+// journalStructVersion must have been decoded entirely, newer ones may have
+// fields left. An error of s becomes the error of d.
+func (d *journalDecoder) end(s *journalDecoder) {
+	if s.err == nil && s.version <= journalStructVersion && len(s.buf) != 0 {
+		s.err = fmt.Errorf("%d bytes left in struct version %d", len(s.buf), s.version)
+	}
+	if s.err != nil && d.err == nil {
+		d.err = s.err
+	}
+}
+
+func (d *journalDecoder) position() JournalPosition {
+	s := d.start()
+	p := JournalPosition{
+		ObjectNumber: s.u64(),
+		TagTid:       s.u64(),
+		EntryTid:     s.u64(),
+	}
+	d.end(s)
+	return p
+}
+
+func (d *journalDecoder) client() JournalClient {
+	s := d.start()
+	c := JournalClient{Id: string(s.bytes())}
+	s.bytes() // opaque client data
+
+	set := s.start()
+	count := int(set.u32())
+	for i := 0; i < count && set.err == nil; i++ {
+		c.CommitPositions = append(c.CommitPositions, set.position())
+	}
+	s.end(set)
+
+	c.State = JournalClientState(s.u8())
+	d.end(s)
+	return c
+}
@@ -0,0 +1,123 @@
+package rbd
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// journal header omap values as cls_journal encodes them
+const (
+	// the master client, its commit position at object 5, tag 2, entry 17
+	journalMasterFixture = "0101" + "31000000" + // Client v1, compat 1, length
+		"00000000" + // id ""
+		"00000000" + // data
+		"0101" + "22000000" + // ObjectSetPosition v1, compat 1, length
+		"01000000" + // one position
+		"0101" + "18000000" + // ObjectPosition v1, compat 1, length
+		"0500000000000000" + "0200000000000000" + "1100000000000000" +
+		"00" // connected
+
+	// a disconnected peer at object 3, tag 2, entry 9
+	journalPeerFixture = "0101" + "35000000" +
+		"04000000" + "70656572" + // id "peer"
+		"00000000" +
+		"0101" + "22000000" +
+		"01000000" +
+		"0101" + "18000000" +
+		"0300000000000000" + "0200000000000000" + "0900000000000000" +
+		"01" // disconnected
+)
+
+func journalFixture(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func journalHeaderFixture(t *testing.T) map[string][]byte {
+	return map[string][]byte{
+		"order":       journalFixture(t, "18"),
+		"splay_width": journalFixture(t, "04"),
+		"pool_id":     journalFixture(t, "ffffffffffffffff"),
+		"minimum_set": journalFixture(t, "0000000000000000"),
+		"active_set":  journalFixture(t, "0100000000000000"),
+		"client_":     journalFixture(t, journalMasterFixture),
+		"client_peer": journalFixture(t, journalPeerFixture),
+		"unrelated":   journalFixture(t, "ff"),
+	}
+}
+
+func TestDecodeJournalHeader(t *testing.T) {
+	info, err := decodeJournalHeader(journalHeaderFixture(t))
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(24), info.Order)
+	assert.Equal(t, uint8(4), info.SplayWidth)
+	assert.Equal(t, int64(-1), info.PoolId)
+	assert.Equal(t, uint64(0), info.MinimumSet)
+	assert.Equal(t, uint64(1), info.ActiveSet)
+
+	master, ok := info.Client("")
+	assert.True(t, ok)
+	assert.Equal(t, JournalClientStateConnected, master.State)
+	assert.Equal(t, []JournalPosition{{ObjectNumber: 5, TagTid: 2, EntryTid: 17}},
+		master.CommitPositions)
+
+	peer, ok := info.Client("peer")
+	assert.True(t, ok)
+	assert.Equal(t, JournalClientStateDisconnected, peer.State)
+	assert.Equal(t, []JournalPosition{{ObjectNumber: 3, TagTid: 2, EntryTid: 9}},
+		peer.CommitPositions)
+
+	behind, ok := info.EntriesBehind("peer")
+	assert.True(t, ok)
+	assert.Equal(t, uint64(8), behind)
+}
+
+func TestDecodeJournalClientNewerVersion(t *testing.T) {
+	// a compatible newer client with a field appended, which is skipped
+	omap := map[string][]byte{
+		"client_peer": journalFixture(t, "0201"+"37000000"+journalPeerFixture[12:]+"ffff"),
+	}
+	info, err := decodeJournalHeader(omap)
+	assert.NoError(t, err)
+	peer, ok := info.Client("peer")
+	assert.True(t, ok)
+	assert.Equal(t, JournalClientStateDisconnected, peer.State)
+	assert.Len(t, peer.CommitPositions, 1)
+}
+
+func TestDecodeJournalHeaderErrors(t *testing.T) {
+	peer := journalPeerFixture[12:]
+	tests := []struct {
+		name  string
+		key   string
+		value string
+	}{
+		{"incompatible client", "client_peer", "0202" + "35000000" + peer},
+		{"bad compat", "client_peer", "0102" + "35000000" + peer},
+		{"zero version", "client_peer", "0000" + "35000000" + peer},
+		{"client length too long", "client_peer", "0101" + "36000000" + peer},
+		{"client length too short", "client_peer", "0101" + "34000000" + peer},
+		{"client bytes left", "client_peer", "0101" + "36000000" + peer + "ff"},
+		{"truncated client", "client_peer", journalPeerFixture[:40]},
+		{"trailing bytes", "client_peer", journalPeerFixture + "00"},
+		{"incompatible position", "client_",
+			strings.Replace(journalMasterFixture, "0101"+"18000000", "0102"+"18000000", 1)},
+		{"short order", "order", ""},
+		{"long order", "order", "1800"},
+		{"short pool id", "pool_id", "ffffffff"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			omap := map[string][]byte{test.key: journalFixture(t, test.value)}
+			_, err := decodeJournalHeader(omap)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), RbdErrorJournalDecode.Error())
+		})
+	}
+}
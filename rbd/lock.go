@@ -0,0 +1,112 @@
+package rbd
+
+// #cgo LDFLAGS: -lrbd
+// #include <errno.h>
+// #include <stdlib.h>
+// #include <rados/librados.h>
+// #include <rbd/librbd.h>
+import "C"
+
+import (
+	"unsafe"
+)
+
+// LockMode is the mode of a managed image lock.
+type LockMode int
+
+const (
+	LockModeExclusive = LockMode(C.RBD_LOCK_MODE_EXCLUSIVE)
+	LockModeShared    = LockMode(C.RBD_LOCK_MODE_SHARED)
+)
+
+// LockAcquire acquires the managed lock of an image with the
+// RbdFeatureExclusiveLock feature.
+//
+// int rbd_lock_acquire(rbd_image_t image, rbd_lock_mode_t lock_mode);
+func (image *Image) LockAcquire(mode LockMode) error {
+	if image.image == nil {
+		return RbdErrorImageNotOpen
+	}
+
+	return GetError(C.rbd_lock_acquire(image.image, C.rbd_lock_mode_t(mode)))
+}
+
+// LockRelease releases a lock acquired with LockAcquire.
+//
+// int rbd_lock_release(rbd_image_t image);
+func (image *Image) LockRelease() error {
+	if image.image == nil {
+		return RbdErrorImageNotOpen
+	}
+
+	return GetError(C.rbd_lock_release(image.image))
+}
+
+// IsExclusiveLockOwner reports whether this client owns the exclusive lock of
+// the image.
+//
+// int rbd_is_exclusive_lock_owner(rbd_image_t image, int *is_owner);
+func (image *Image) IsExclusiveLockOwner() (bool, error) {
+	if image.image == nil {
+		return false, RbdErrorImageNotOpen
+	}
+
+	var c_is_owner C.int
+	ret := C.rbd_is_exclusive_lock_owner(image.image, &c_is_owner)
+	if ret < 0 {
+		return false, RBDError(ret)
+	}
+
+	return c_is_owner != 0, nil
+}
+
+// LockGetOwners returns the mode of the managed lock of the image and the
+// clients that currently own it. If the lock has no owner, owners is empty
+// and mode is meaningless.
+//
+// int rbd_lock_get_owners(rbd_image_t image, rbd_lock_mode_t *lock_mode, char **lock_owners, size_t *max_lock_owners);
+// void rbd_lock_get_owners_cleanup(char **lock_owners, size_t lock_owner_count);
+func (image *Image) LockGetOwners() (mode LockMode, owners []string, err error) {
+	if image.image == nil {
+		return 0, nil, RbdErrorImageNotOpen
+	}
+
+	var c_mode C.rbd_lock_mode_t
+	var c_max_owners C.size_t = 8
+	for {
+		c_owners := make([]*C.char, c_max_owners)
+		ret := C.rbd_lock_get_owners(image.image, &c_mode, &c_owners[0],
+			&c_max_owners)
+		if ret == -C.ERANGE {
+			continue
+		} else if ret == -C.ENOENT {
+			// the lock has no owner
+			return 0, []string{}, nil
+		} else if ret < 0 {
+			return 0, nil, GetError(ret)
+		}
+
+		owners = make([]string, c_max_owners)
+		for i := range owners {
+			owners[i] = C.GoString(c_owners[i])
+		}
+		C.rbd_lock_get_owners_cleanup(&c_owners[0], c_max_owners)
+		return LockMode(c_mode), owners, nil
+	}
+}
+
+// LockBreak breaks the managed lock of the image held by owner, for example
+// to fence a client that died while holding the exclusive lock.
+//
+// int rbd_lock_break(rbd_image_t image, rbd_lock_mode_t lock_mode, const char *lock_owner);
+func (image *Image) LockBreak(mode LockMode, owner string) error {
+	if image.image == nil {
+		return RbdErrorImageNotOpen
+	}
+
+	var c_owner *C.char = C.CString(owner)
+	defer C.free(unsafe.Pointer(c_owner))
+
+	return GetError(C.rbd_lock_break(image.image, C.rbd_lock_mode_t(mode),
+		c_owner))
+}
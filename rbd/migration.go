@@ -0,0 +1,151 @@
+package rbd
+
+// #cgo LDFLAGS: -lrbd
+// #include <errno.h>
+// #include <stdlib.h>
+// #include <rados/librados.h>
+// #include <rbd/librbd.h>
+import "C"
+
+import (
+	"github.com/noahdesu/go-ceph/rados"
+	"unsafe"
+)
+
+// MigrationState is the state of a live image migration.
+type MigrationState int
+
+const (
+	MigrationStateUnknown   = MigrationState(C.RBD_IMAGE_MIGRATION_STATE_UNKNOWN)
+	MigrationStateError     = MigrationState(C.RBD_IMAGE_MIGRATION_STATE_ERROR)
+	MigrationStatePreparing = MigrationState(C.RBD_IMAGE_MIGRATION_STATE_PREPARING)
+	MigrationStatePrepared  = MigrationState(C.RBD_IMAGE_MIGRATION_STATE_PREPARED)
+	MigrationStateExecuting = MigrationState(C.RBD_IMAGE_MIGRATION_STATE_EXECUTING)
+	MigrationStateExecuted  = MigrationState(C.RBD_IMAGE_MIGRATION_STATE_EXECUTED)
+	MigrationStateAborting  = MigrationState(C.RBD_IMAGE_MIGRATION_STATE_ABORTING)
+)
+
+// MigrationStatus describes the source, destination and progress of an
+// image migration.
+type MigrationStatus struct {
+	SourcePoolId        int64
+	SourcePoolNamespace string
+	SourceImageName     string
+	SourceImageId       string
+	DestPoolId          int64
+	DestPoolNamespace   string
+	DestImageName       string
+	DestImageId         string
+	State               MigrationState
+	StateDescription    string
+}
+
+// MigrationPrepare prepares the migration of the image name in ioctx to
+// destName in destIoctx. After this call clients must open the destination
+// image; the source image is only accessible through it. The destination
+// image is created with the parameters in opts, which may be nil to keep
+// those of the source image.
+//
+// int rbd_migration_prepare(rados_ioctx_t ioctx, const char *image_name, rados_ioctx_t dest_ioctx, const char *dest_image_name, rbd_image_options_t opts);
+func MigrationPrepare(ioctx *rados.IOContext, name string,
+	destIoctx *rados.IOContext, destName string, opts *ImageOptions) error {
+	var c_name *C.char = C.CString(name)
+	var c_dest_name *C.char = C.CString(destName)
+	defer C.free(unsafe.Pointer(c_name))
+	defer C.free(unsafe.Pointer(c_dest_name))
+
+	if opts == nil {
+		opts = NewImageOptions()
+		defer opts.Destroy()
+	}
+
+	return GetError(C.rbd_migration_prepare(C.rados_ioctx_t(ioctx.Pointer()),
+		c_name, C.rados_ioctx_t(destIoctx.Pointer()), c_dest_name,
+		opts.options))
+}
+
+// MigrationPrepareImport prepares an import-only migration from an external
+// source described by the JSON source spec into destName in destIoctx. opts
+// may be nil.
+//
+// int rbd_migration_prepare_import(const char *source_spec, rados_ioctx_t dest_ioctx, const char *dest_image_name, rbd_image_options_t opts);
+func MigrationPrepareImport(sourceSpec string, destIoctx *rados.IOContext,
+	destName string, opts *ImageOptions) error {
+	var c_source_spec *C.char = C.CString(sourceSpec)
+	var c_dest_name *C.char = C.CString(destName)
+	defer C.free(unsafe.Pointer(c_source_spec))
+	defer C.free(unsafe.Pointer(c_dest_name))
+
+	if opts == nil {
+		opts = NewImageOptions()
+		defer opts.Destroy()
+	}
+
+	return GetError(C.rbd_migration_prepare_import(c_source_spec,
+		C.rados_ioctx_t(destIoctx.Pointer()), c_dest_name, opts.options))
+}
+
+// MigrationExecute copies the data of a prepared migration to the
+// destination image. The name is that of the destination image.
+//
+// int rbd_migration_execute(rados_ioctx_t ioctx, const char *image_name);
+func MigrationExecute(ioctx *rados.IOContext, name string) error {
+	var c_name *C.char = C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	return GetError(C.rbd_migration_execute(C.rados_ioctx_t(ioctx.Pointer()),
+		c_name))
+}
+
+// MigrationCommit completes an executed migration and removes the source
+// image.
+//
+// int rbd_migration_commit(rados_ioctx_t ioctx, const char *image_name);
+func MigrationCommit(ioctx *rados.IOContext, name string) error {
+	var c_name *C.char = C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	return GetError(C.rbd_migration_commit(C.rados_ioctx_t(ioctx.Pointer()),
+		c_name))
+}
+
+// MigrationAbort cancels a migration and restores the source image.
+//
+// int rbd_migration_abort(rados_ioctx_t ioctx, const char *image_name);
+func MigrationAbort(ioctx *rados.IOContext, name string) error {
+	var c_name *C.char = C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	return GetError(C.rbd_migration_abort(C.rados_ioctx_t(ioctx.Pointer()),
+		c_name))
+}
+
+// GetMigrationStatus returns the status of the migration of the image.
+//
+// int rbd_migration_status(rados_ioctx_t ioctx, const char *image_name, rbd_image_migration_status_t *status, size_t status_size);
+// void rbd_migration_status_cleanup(rbd_image_migration_status_t *status);
+func GetMigrationStatus(ioctx *rados.IOContext, name string) (*MigrationStatus, error) {
+	var c_name *C.char = C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	var c_status C.rbd_image_migration_status_t
+	ret := C.rbd_migration_status(C.rados_ioctx_t(ioctx.Pointer()), c_name,
+		&c_status, C.size_t(unsafe.Sizeof(c_status)))
+	if ret < 0 {
+		return nil, GetError(ret)
+	}
+	defer C.rbd_migration_status_cleanup(&c_status)
+
+	return &MigrationStatus{
+		SourcePoolId:        int64(c_status.source_pool_id),
+		SourcePoolNamespace: C.GoString(c_status.source_pool_namespace),
+		SourceImageName:     C.GoString(c_status.source_image_name),
+		SourceImageId:       C.GoString(c_status.source_image_id),
+		DestPoolId:          int64(c_status.dest_pool_id),
+		DestPoolNamespace:   C.GoString(c_status.dest_pool_namespace),
+		DestImageName:       C.GoString(c_status.dest_image_name),
+		DestImageId:         C.GoString(c_status.dest_image_id),
+		State:               MigrationState(c_status.state),
+		StateDescription:    C.GoString(c_status.state_description),
+	}, nil
+}
@@ -0,0 +1,438 @@
+package rbd
+
+// #cgo LDFLAGS: -lrbd
+// #include <errno.h>
+// #include <stdlib.h>
+// #include <stdbool.h>
+// #include <rados/librados.h>
+// #include <rbd/librbd.h>
+import "C"
+
+import (
+	"fmt"
+	"github.com/noahdesu/go-ceph/rados"
+	"time"
+	"unsafe"
+)
+
+// MirrorMode is the mirroring mode of a pool.
+type MirrorMode int
+
+const (
+	// MirrorModeDisabled disables mirroring for the pool.
+	MirrorModeDisabled = MirrorMode(C.RBD_MIRROR_MODE_DISABLED)
+	// MirrorModeImage mirrors only images that have mirroring enabled.
+	MirrorModeImage = MirrorMode(C.RBD_MIRROR_MODE_IMAGE)
+	// MirrorModePool mirrors all images with the journaling feature.
+	MirrorModePool = MirrorMode(C.RBD_MIRROR_MODE_POOL)
+)
+
+// ImageMirrorMode is the mechanism used to mirror a single image.
+type ImageMirrorMode int
+
+const (
+	ImageMirrorModeJournal  = ImageMirrorMode(C.RBD_MIRROR_IMAGE_MODE_JOURNAL)
+	ImageMirrorModeSnapshot = ImageMirrorMode(C.RBD_MIRROR_IMAGE_MODE_SNAPSHOT)
+)
+
+// MirrorPeerDirection is the direction in which data is mirrored with a peer.
+type MirrorPeerDirection int
+
+const (
+	MirrorPeerDirectionRx   = MirrorPeerDirection(C.RBD_MIRROR_PEER_DIRECTION_RX)
+	MirrorPeerDirectionTx   = MirrorPeerDirection(C.RBD_MIRROR_PEER_DIRECTION_TX)
+	MirrorPeerDirectionRxTx = MirrorPeerDirection(C.RBD_MIRROR_PEER_DIRECTION_RX_TX)
+)
+
+// MirrorPeerSite describes a remote cluster that a pool is mirrored with.
+type MirrorPeerSite struct {
+	Uuid       string
+	Direction  MirrorPeerDirection
+	SiteName   string
+	MirrorUuid string
+	ClientName string
+	LastSeen   time.Time
+}
+
+// int rbd_mirror_mode_get(rados_ioctx_t io_ctx, rbd_mirror_mode_t *mirror_mode);
+func MirrorModeGet(ioctx *rados.IOContext) (MirrorMode, error) {
+	var c_mode C.rbd_mirror_mode_t
+	ret := C.rbd_mirror_mode_get(C.rados_ioctx_t(ioctx.Pointer()), &c_mode)
+	if ret < 0 {
+		return MirrorModeDisabled, RBDError(ret)
+	}
+
+	return MirrorMode(c_mode), nil
+}
+
+// int rbd_mirror_mode_set(rados_ioctx_t io_ctx, rbd_mirror_mode_t mirror_mode);
+func MirrorModeSet(ioctx *rados.IOContext, mode MirrorMode) error {
+	return GetError(C.rbd_mirror_mode_set(C.rados_ioctx_t(ioctx.Pointer()),
+		C.rbd_mirror_mode_t(mode)))
+}
+
+// MirrorPeerSiteAdd adds a peer cluster to the pool and returns the UUID
+// assigned to the peer.
+//
+// int rbd_mirror_peer_site_add(rados_ioctx_t io_ctx, char *uuid, size_t uuid_max_length, rbd_mirror_peer_direction_t direction, const char *site_name, const char *client_name);
+func MirrorPeerSiteAdd(ioctx *rados.IOContext, direction MirrorPeerDirection,
+	siteName, clientName string) (string, error) {
+	var c_site_name *C.char = C.CString(siteName)
+	var c_client_name *C.char = C.CString(clientName)
+	defer C.free(unsafe.Pointer(c_site_name))
+	defer C.free(unsafe.Pointer(c_client_name))
+
+	buf := make([]byte, 64)
+	ret := C.rbd_mirror_peer_site_add(C.rados_ioctx_t(ioctx.Pointer()),
+		(*C.char)(unsafe.Pointer(&buf[0])), C.size_t(len(buf)),
+		C.rbd_mirror_peer_direction_t(direction), c_site_name, c_client_name)
+	if ret < 0 {
+		return "", RBDError(ret)
+	}
+
+	return C.GoString((*C.char)(unsafe.Pointer(&buf[0]))), nil
+}
+
+// int rbd_mirror_peer_site_remove(rados_ioctx_t io_ctx, const char *uuid);
+func MirrorPeerSiteRemove(ioctx *rados.IOContext, uuid string) error {
+	var c_uuid *C.char = C.CString(uuid)
+	defer C.free(unsafe.Pointer(c_uuid))
+
+	return GetError(C.rbd_mirror_peer_site_remove(
+		C.rados_ioctx_t(ioctx.Pointer()), c_uuid))
+}
+
+// int rbd_mirror_peer_site_list(rados_ioctx_t io_ctx, rbd_mirror_peer_site_t *peers, int *max_peers);
+// void rbd_mirror_peer_site_list_cleanup(rbd_mirror_peer_site_t *peers, int max_peers);
+func MirrorPeerSiteList(ioctx *rados.IOContext) ([]MirrorPeerSite, error) {
+	var c_max_peers C.int = 8
+	for {
+		c_peers := make([]C.rbd_mirror_peer_site_t, c_max_peers)
+		ret := C.rbd_mirror_peer_site_list(C.rados_ioctx_t(ioctx.Pointer()),
+			&c_peers[0], &c_max_peers)
+		if ret == -C.ERANGE {
+			continue
+		} else if ret < 0 {
+			return nil, RBDError(ret)
+		}
+
+		peers := make([]MirrorPeerSite, c_max_peers)
+		for i := range peers {
+			c_peer := &c_peers[i]
+			peers[i] = MirrorPeerSite{
+				Uuid:       C.GoString(c_peer.uuid),
+				Direction:  MirrorPeerDirection(c_peer.direction),
+				SiteName:   C.GoString(c_peer.site_name),
+				MirrorUuid: C.GoString(c_peer.mirror_uuid),
+				ClientName: C.GoString(c_peer.client_name),
+				LastSeen:   time.Unix(int64(c_peer.last_seen), 0),
+			}
+		}
+		C.rbd_mirror_peer_site_list_cleanup(&c_peers[0], c_max_peers)
+		return peers, nil
+	}
+}
+
+// MirrorPeerBootstrapCreate creates a bootstrap token that a peer cluster can
+// import with MirrorPeerBootstrapImport to set up mirroring of this pool.
+//
+// int rbd_mirror_peer_bootstrap_create(rados_ioctx_t io_ctx, char *token, size_t *max_len);
+func MirrorPeerBootstrapCreate(ioctx *rados.IOContext) (string, error) {
+	buf := make([]byte, 512)
+	for {
+		c_len := C.size_t(len(buf))
+		ret := C.rbd_mirror_peer_bootstrap_create(
+			C.rados_ioctx_t(ioctx.Pointer()),
+			(*C.char)(unsafe.Pointer(&buf[0])), &c_len)
+		if ret == -C.ERANGE {
+			buf = make([]byte, c_len)
+			continue
+		} else if ret < 0 {
+			return "", RBDError(ret)
+		}
+
+		return C.GoString((*C.char)(unsafe.Pointer(&buf[0]))), nil
+	}
+}
+
+// MirrorPeerBootstrapImport imports a bootstrap token created on a peer
+// cluster and adds the peer to this pool.
+//
+// int rbd_mirror_peer_bootstrap_import(rados_ioctx_t io_ctx, rbd_mirror_peer_direction_t direction, const char *token);
+func MirrorPeerBootstrapImport(ioctx *rados.IOContext,
+	direction MirrorPeerDirection, token string) error {
+	var c_token *C.char = C.CString(token)
+	defer C.free(unsafe.Pointer(c_token))
+
+	return GetError(C.rbd_mirror_peer_bootstrap_import(
+		C.rados_ioctx_t(ioctx.Pointer()),
+		C.rbd_mirror_peer_direction_t(direction), c_token))
+}
+
+// MirrorEnable enables mirroring of the image using the given mode. The pool
+// must be in MirrorModeImage.
+//
+// int rbd_mirror_image_enable2(rbd_image_t image, rbd_mirror_image_mode_t mode);
+func (image *Image) MirrorEnable(mode ImageMirrorMode) error {
+	if image.image == nil {
+		return RbdErrorImageNotOpen
+	}
+
+	return GetError(C.rbd_mirror_image_enable2(image.image,
+		C.rbd_mirror_image_mode_t(mode)))
+}
+
+// int rbd_mirror_image_disable(rbd_image_t image, bool force);
+func (image *Image) MirrorDisable(force bool) error {
+	if image.image == nil {
+		return RbdErrorImageNotOpen
+	}
+
+	return GetError(C.rbd_mirror_image_disable(image.image, C.bool(force)))
+}
+
+// int rbd_mirror_image_promote(rbd_image_t image, bool force);
+func (image *Image) MirrorPromote(force bool) error {
+	if image.image == nil {
+		return RbdErrorImageNotOpen
+	}
+
+	return GetError(C.rbd_mirror_image_promote(image.image, C.bool(force)))
+}
+
+// int rbd_mirror_image_demote(rbd_image_t image);
+func (image *Image) MirrorDemote() error {
+	if image.image == nil {
+		return RbdErrorImageNotOpen
+	}
+
+	return GetError(C.rbd_mirror_image_demote(image.image))
+}
+
+// MirrorResync flags a non-primary image to be resynchronized from the
+// primary image.
+//
+// int rbd_mirror_image_resync(rbd_image_t image);
+func (image *Image) MirrorResync() error {
+	if image.image == nil {
+		return RbdErrorImageNotOpen
+	}
+
+	return GetError(C.rbd_mirror_image_resync(image.image))
+}
+
+// MirrorImageState is the mirroring state of an image.
+type MirrorImageState int
+
+const (
+	MirrorImageDisabling = MirrorImageState(C.RBD_MIRROR_IMAGE_DISABLING)
+	MirrorImageEnabled   = MirrorImageState(C.RBD_MIRROR_IMAGE_ENABLED)
+	MirrorImageDisabled  = MirrorImageState(C.RBD_MIRROR_IMAGE_DISABLED)
+)
+
+// MirrorImageInfo describes the mirroring configuration of an image.
+type MirrorImageInfo struct {
+	GlobalId string
+	State    MirrorImageState
+	Primary  bool
+}
+
+// MirrorImageStatusState is the replication state of an image as reported
+// by an rbd-mirror daemon.
+type MirrorImageStatusState int
+
+const (
+	MirrorImageStatusStateUnknown        = MirrorImageStatusState(C.MIRROR_IMAGE_STATUS_STATE_UNKNOWN)
+	MirrorImageStatusStateError          = MirrorImageStatusState(C.MIRROR_IMAGE_STATUS_STATE_ERROR)
+	MirrorImageStatusStateSyncing        = MirrorImageStatusState(C.MIRROR_IMAGE_STATUS_STATE_SYNCING)
+	MirrorImageStatusStateStartingReplay = MirrorImageStatusState(C.MIRROR_IMAGE_STATUS_STATE_STARTING_REPLAY)
+	MirrorImageStatusStateReplaying      = MirrorImageStatusState(C.MIRROR_IMAGE_STATUS_STATE_REPLAYING)
+	MirrorImageStatusStateStoppingReplay = MirrorImageStatusState(C.MIRROR_IMAGE_STATUS_STATE_STOPPING_REPLAY)
+	MirrorImageStatusStateStopped        = MirrorImageStatusState(C.MIRROR_IMAGE_STATUS_STATE_STOPPED)
+)
+
+func (s MirrorImageStatusState) String() string {
+	switch s {
+	case MirrorImageStatusStateUnknown:
+		return "unknown"
+	case MirrorImageStatusStateError:
+		return "error"
+	case MirrorImageStatusStateSyncing:
+		return "syncing"
+	case MirrorImageStatusStateStartingReplay:
+		return "starting_replay"
+	case MirrorImageStatusStateReplaying:
+		return "replaying"
+	case MirrorImageStatusStateStoppingReplay:
+		return "stopping_replay"
+	case MirrorImageStatusStateStopped:
+		return "stopped"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(s))
+	}
+}
+
+// MirrorImageSiteStatus is the replication status of an image on one site.
+// The local site has an empty MirrorUuid.
+type MirrorImageSiteStatus struct {
+	MirrorUuid  string
+	State       MirrorImageStatusState
+	Description string
+	LastUpdate  time.Time
+	Up          bool
+}
+
+// MirrorImageGlobalStatus is the replication status of an image across all
+// sites.
+type MirrorImageGlobalStatus struct {
+	Name         string
+	Info         MirrorImageInfo
+	SiteStatuses []MirrorImageSiteStatus
+}
+
+// LocalStatus returns the status reported for the local site.
+func (status *MirrorImageGlobalStatus) LocalStatus() (MirrorImageSiteStatus, error) {
+	for _, s := range status.SiteStatuses {
+		if s.MirrorUuid == "" {
+			return s, nil
+		}
+	}
+	return MirrorImageSiteStatus{}, RbdErrorNotFound
+}
+
+func newMirrorImageInfo(c_info *C.rbd_mirror_image_info_t) MirrorImageInfo {
+	return MirrorImageInfo{
+		GlobalId: C.GoString(c_info.global_id),
+		State:    MirrorImageState(c_info.state),
+		Primary:  bool(c_info.primary),
+	}
+}
+
+func newMirrorImageGlobalStatus(c_status *C.rbd_mirror_image_global_status_t) MirrorImageGlobalStatus {
+	count := int(c_status.site_statuses_count)
+	status := MirrorImageGlobalStatus{
+		Name:         C.GoString(c_status.name),
+		Info:         newMirrorImageInfo(&c_status.info),
+		SiteStatuses: make([]MirrorImageSiteStatus, count),
+	}
+	if count == 0 {
+		return status
+	}
+
+	c_site_statuses := (*[1 << 20]C.rbd_mirror_image_site_status_t)(
+		unsafe.Pointer(c_status.site_statuses))[:count:count]
+	for i, s := range c_site_statuses {
+		status.SiteStatuses[i] = MirrorImageSiteStatus{
+			MirrorUuid:  C.GoString(s.mirror_uuid),
+			State:       MirrorImageStatusState(s.state),
+			Description: C.GoString(s.description),
+			LastUpdate:  time.Unix(int64(s.last_update), 0),
+			Up:          bool(s.up),
+		}
+	}
+	return status
+}
+
+// GetMirrorImageInfo returns the mirroring configuration of the image.
+//
+// int rbd_mirror_image_get_info(rbd_image_t image, rbd_mirror_image_info_t *mirror_image_info, size_t info_size);
+// void rbd_mirror_image_get_info_cleanup(rbd_mirror_image_info_t *mirror_image_info);
+func (image *Image) GetMirrorImageInfo() (*MirrorImageInfo, error) {
+	if image.image == nil {
+		return nil, RbdErrorImageNotOpen
+	}
+
+	var c_info C.rbd_mirror_image_info_t
+	ret := C.rbd_mirror_image_get_info(image.image, &c_info,
+		C.size_t(unsafe.Sizeof(c_info)))
+	if ret < 0 {
+		return nil, RBDError(ret)
+	}
+	defer C.rbd_mirror_image_get_info_cleanup(&c_info)
+
+	info := newMirrorImageInfo(&c_info)
+	return &info, nil
+}
+
+// GetGlobalMirrorStatus returns the replication status of the image on all
+// sites.
+//
+// int rbd_mirror_image_get_global_status(rbd_image_t image, rbd_mirror_image_global_status_t *mirror_image_global_status, size_t status_size);
+// void rbd_mirror_image_global_status_cleanup(rbd_mirror_image_global_status_t *mirror_image_global_status);
+func (image *Image) GetGlobalMirrorStatus() (*MirrorImageGlobalStatus, error) {
+	if image.image == nil {
+		return nil, RbdErrorImageNotOpen
+	}
+
+	var c_status C.rbd_mirror_image_global_status_t
+	ret := C.rbd_mirror_image_get_global_status(image.image, &c_status,
+		C.size_t(unsafe.Sizeof(c_status)))
+	if ret < 0 {
+		return nil, RBDError(ret)
+	}
+	defer C.rbd_mirror_image_global_status_cleanup(&c_status)
+
+	status := newMirrorImageGlobalStatus(&c_status)
+	return &status, nil
+}
+
+// MirrorImageStatusSummary returns the number of mirrored images in the pool
+// in each replication state.
+//
+// int rbd_mirror_image_status_summary(rados_ioctx_t io_ctx, rbd_mirror_image_status_state_t *states, int *counts, size_t *maxlen);
+func MirrorImageStatusSummary(ioctx *rados.IOContext) (map[MirrorImageStatusState]uint, error) {
+	var c_maxlen C.size_t = 32
+	c_states := make([]C.rbd_mirror_image_status_state_t, c_maxlen)
+	c_counts := make([]C.int, c_maxlen)
+
+	ret := C.rbd_mirror_image_status_summary(C.rados_ioctx_t(ioctx.Pointer()),
+		&c_states[0], &c_counts[0], &c_maxlen)
+	if ret < 0 {
+		return nil, RBDError(ret)
+	}
+
+	summary := make(map[MirrorImageStatusState]uint)
+	for i := 0; i < int(c_maxlen); i++ {
+		summary[MirrorImageStatusState(c_states[i])] = uint(c_counts[i])
+	}
+	return summary, nil
+}
+
+// MirrorImageStatusListFunc is the type of the function called for each
+// image visited by ListMirrorImageGlobalStatus.
+type MirrorImageStatusListFunc func(id string, status MirrorImageGlobalStatus)
+
+// ListMirrorImageGlobalStatus iterates over the mirrored images of the pool
+// and calls listFn with the id and replication status of each of them.
+//
+// int rbd_mirror_image_global_status_list(rados_ioctx_t io_ctx, const char *start_id, size_t max, char **image_ids, rbd_mirror_image_global_status_t *images, size_t *len);
+// void rbd_mirror_image_global_status_list_cleanup(char **image_ids, rbd_mirror_image_global_status_t *images, size_t len);
+func ListMirrorImageGlobalStatus(ioctx *rados.IOContext, listFn MirrorImageStatusListFunc) error {
+	const batch = 64
+	c_ids := make([]*C.char, batch)
+	c_statuses := make([]C.rbd_mirror_image_global_status_t, batch)
+	start := ""
+
+	for {
+		var c_len C.size_t
+		var c_start *C.char = C.CString(start)
+		ret := C.rbd_mirror_image_global_status_list(
+			C.rados_ioctx_t(ioctx.Pointer()), c_start, batch,
+			&c_ids[0], &c_statuses[0], &c_len)
+		C.free(unsafe.Pointer(c_start))
+		if ret < 0 {
+			return RBDError(ret)
+		}
+
+		for i := 0; i < int(c_len); i++ {
+			start = C.GoString(c_ids[i])
+			listFn(start, newMirrorImageGlobalStatus(&c_statuses[i]))
+		}
+		C.rbd_mirror_image_global_status_list_cleanup(&c_ids[0],
+			&c_statuses[0], c_len)
+
+		if c_len < batch {
+			return nil
+		}
+	}
+}
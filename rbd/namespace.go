@@ -0,0 +1,78 @@
+package rbd
+
+// #cgo LDFLAGS: -lrbd
+// #include <errno.h>
+// #include <stdlib.h>
+// #include <stdbool.h>
+// #include <rados/librados.h>
+// #include <rbd/librbd.h>
+import "C"
+
+import (
+	"github.com/noahdesu/go-ceph/rados"
+	"unsafe"
+)
+
+// NamespaceCreate creates a new RBD namespace in the pool. Images are created,
+// opened and listed in the namespace set on the IOContext with
+// rados.IOContext.SetNamespace.
+//
+// int rbd_namespace_create(rados_ioctx_t io, const char *namespace_name);
+func NamespaceCreate(ioctx *rados.IOContext, namespace string) error {
+	var c_namespace *C.char = C.CString(namespace)
+	defer C.free(unsafe.Pointer(c_namespace))
+
+	return GetError(C.rbd_namespace_create(C.rados_ioctx_t(ioctx.Pointer()),
+		c_namespace))
+}
+
+// NamespaceRemove removes an empty RBD namespace from the pool.
+//
+// int rbd_namespace_remove(rados_ioctx_t io, const char *namespace_name);
+func NamespaceRemove(ioctx *rados.IOContext, namespace string) error {
+	var c_namespace *C.char = C.CString(namespace)
+	defer C.free(unsafe.Pointer(c_namespace))
+
+	return GetError(C.rbd_namespace_remove(C.rados_ioctx_t(ioctx.Pointer()),
+		c_namespace))
+}
+
+// NamespaceExists reports whether the RBD namespace exists in the pool.
+//
+// int rbd_namespace_exists(rados_ioctx_t io, const char *namespace_name, bool *exists);
+func NamespaceExists(ioctx *rados.IOContext, namespace string) (bool, error) {
+	var c_namespace *C.char = C.CString(namespace)
+	defer C.free(unsafe.Pointer(c_namespace))
+
+	var c_exists C.bool
+	ret := C.rbd_namespace_exists(C.rados_ioctx_t(ioctx.Pointer()),
+		c_namespace, &c_exists)
+	if ret < 0 {
+		return false, RBDError(ret)
+	}
+
+	return bool(c_exists), nil
+}
+
+// NamespaceList returns the names of the RBD namespaces in the pool.
+//
+// int rbd_namespace_list(rados_ioctx_t io, char *namespace_names, size_t *size);
+func NamespaceList(ioctx *rados.IOContext) (names []string, err error) {
+	buf := make([]byte, 4096)
+	for {
+		size := C.size_t(len(buf))
+		ret := C.rbd_namespace_list(C.rados_ioctx_t(ioctx.Pointer()),
+			(*C.char)(unsafe.Pointer(&buf[0])), &size)
+		if ret == -C.ERANGE {
+			buf = make([]byte, size)
+			continue
+		} else if ret < 0 {
+			return nil, RBDError(ret)
+		}
+
+		if size == 0 {
+			return nil, nil
+		}
+		return split(buf[:size]), nil
+	}
+}
@@ -0,0 +1,56 @@
+package rbd
+
+/*
+#cgo LDFLAGS: -lrbd
+#include <errno.h>
+#include <stdint.h>
+#include <stdlib.h>
+#include <rados/librados.h>
+#include <rbd/librbd.h>
+
+extern int progressCallback(uint64_t offset, uint64_t total, uintptr_t index);
+
+static inline int wrap_rbd_rebuild_object_map(rbd_image_t image,
+		uintptr_t index) {
+	return rbd_rebuild_object_map(image,
+		(librbd_progress_fn_t)progressCallback, (void *)index);
+}
+*/
+import "C"
+
+// Image flags
+var RbdFlagObjectMapInvalid = uint64(C.RBD_FLAG_OBJECT_MAP_INVALID)
+var RbdFlagFastDiffInvalid = uint64(C.RBD_FLAG_FAST_DIFF_INVALID)
+
+// GetFlags returns the flags of the image. The RbdFlagObjectMapInvalid and
+// RbdFlagFastDiffInvalid flags indicate that the object map must be rebuilt.
+//
+// int rbd_get_flags(rbd_image_t image, uint64_t *flags);
+func (image *Image) GetFlags() (flags uint64, err error) {
+	if image.image == nil {
+		return 0, RbdErrorImageNotOpen
+	}
+
+	ret := C.rbd_get_flags(image.image, (*C.uint64_t)(&flags))
+	if ret < 0 {
+		return 0, RBDError(ret)
+	}
+
+	return flags, nil
+}
+
+// RebuildObjectMap rebuilds the object map of the image, clearing the
+// invalid flags. progressFn, if not nil, is called as the rebuild advances.
+//
+// int rbd_rebuild_object_map(rbd_image_t image, librbd_progress_fn_t cb, void *cbdata);
+func (image *Image) RebuildObjectMap(progressFn ProgressFunc) error {
+	if image.image == nil {
+		return RbdErrorImageNotOpen
+	}
+
+	index := progressCallbacks.Add(progressFn)
+	defer progressCallbacks.Remove(index)
+
+	return GetError(C.wrap_rbd_rebuild_object_map(image.image,
+		C.uintptr_t(index)))
+}
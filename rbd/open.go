@@ -0,0 +1,167 @@
+package rbd
+
+// #cgo LDFLAGS: -lrbd
+// #include <errno.h>
+// #include <stdlib.h>
+// #include <rados/librados.h>
+// #include <rbd/librbd.h>
+import "C"
+
+import (
+	"github.com/noahdesu/go-ceph/rados"
+	"unsafe"
+)
+
+// OpenImage opens the image with the given name for reading and writing.
+//
+// int rbd_open(rados_ioctx_t io, const char *name, rbd_image_t *image, const char *snap_name);
+func OpenImage(ioctx *rados.IOContext, name string) (*Image, error) {
+	image := GetImage(ioctx, name)
+	if err := image.Open(); err != nil {
+		return nil, err
+	}
+
+	return image, nil
+}
+
+// OpenImageReadOnly opens the image with the given name for reading only.
+// Unlike a read-write open it does not watch the image header nor take the
+// exclusive lock, so it can not interfere with other clients.
+//
+// int rbd_open_read_only(rados_ioctx_t io, const char *name, rbd_image_t *image, const char *snap_name);
+func OpenImageReadOnly(ioctx *rados.IOContext, name string) (*Image, error) {
+	image := GetImage(ioctx, name)
+	if err := image.Open(true); err != nil {
+		return nil, err
+	}
+
+	return image, nil
+}
+
+// OpenImageById opens the image with the given id for reading and writing.
+// Image ids, unlike names, do not change when an image is renamed.
+//
+// int rbd_open_by_id(rados_ioctx_t io, const char *id, rbd_image_t *image, const char *snap_name);
+func OpenImageById(ioctx *rados.IOContext, id string) (*Image, error) {
+	return openImageById(ioctx, id, nil, false)
+}
+
+// OpenImageByIdReadOnly opens the image with the given id for reading only.
+//
+// int rbd_open_by_id_read_only(rados_ioctx_t io, const char *id, rbd_image_t *image, const char *snap_name);
+func OpenImageByIdReadOnly(ioctx *rados.IOContext, id string) (*Image, error) {
+	return openImageById(ioctx, id, nil, true)
+}
+
+// OpenImageAtSnapshot opens the image with the given name at the snapshot
+// snapName. The image is opened read-only and all reads return the data as
+// it was when the snapshot was taken, which makes it suitable for consistent
+// exports of a live image.
+//
+// int rbd_open_read_only(rados_ioctx_t io, const char *name, rbd_image_t *image, const char *snap_name);
+func OpenImageAtSnapshot(ioctx *rados.IOContext, name, snapName string) (*Image, error) {
+	if snapName == "" {
+		return nil, RbdErrorSnapshotNameRequired
+	}
+
+	image := GetImage(ioctx, name)
+	if err := image.Open(snapName, true); err != nil {
+		return nil, err
+	}
+
+	return image, nil
+}
+
+// OpenImageByIdAtSnapshot opens the image with the given id read-only at the
+// snapshot snapName.
+//
+// int rbd_open_by_id_read_only(rados_ioctx_t io, const char *id, rbd_image_t *image, const char *snap_name);
+func OpenImageByIdAtSnapshot(ioctx *rados.IOContext, id, snapName string) (*Image, error) {
+	if snapName == "" {
+		return nil, RbdErrorSnapshotNameRequired
+	}
+
+	var c_snap_name *C.char = C.CString(snapName)
+	defer C.free(unsafe.Pointer(c_snap_name))
+
+	return openImageById(ioctx, id, c_snap_name, true)
+}
+
+func openImageById(ioctx *rados.IOContext, id string, c_snap_name *C.char,
+	read_only bool) (*Image, error) {
+	var c_image C.rbd_image_t
+	var c_id *C.char = C.CString(id)
+	defer C.free(unsafe.Pointer(c_id))
+
+	var ret C.int
+	if read_only {
+		ret = C.rbd_open_by_id_read_only(C.rados_ioctx_t(ioctx.Pointer()),
+			c_id, &c_image, c_snap_name)
+	} else {
+		ret = C.rbd_open_by_id(C.rados_ioctx_t(ioctx.Pointer()),
+			c_id, &c_image, c_snap_name)
+	}
+	if ret < 0 {
+		return nil, GetError(ret)
+	}
+
+	image := &Image{
+		ioctx: ioctx,
+		image: c_image,
+	}
+	name, err := image.GetName()
+	if err != nil {
+		C.rbd_close(c_image)
+		return nil, err
+	}
+	image.name = name
+
+	return image, nil
+}
+
+// GetId returns the id of the image.
+//
+// int rbd_get_id(rbd_image_t image, char *id, size_t id_len);
+func (image *Image) GetId() (string, error) {
+	if image.image == nil {
+		return "", RbdErrorImageNotOpen
+	}
+
+	buf := make([]byte, 64)
+	for {
+		ret := C.rbd_get_id(image.image,
+			(*C.char)(unsafe.Pointer(&buf[0])), C.size_t(len(buf)))
+		if ret == -C.ERANGE {
+			buf = make([]byte, len(buf)*2)
+			continue
+		} else if ret < 0 {
+			return "", RBDError(ret)
+		}
+
+		return C.GoString((*C.char)(unsafe.Pointer(&buf[0]))), nil
+	}
+}
+
+// GetName returns the current name of the open image.
+//
+// int rbd_get_name(rbd_image_t image, char *name, size_t *name_len);
+func (image *Image) GetName() (string, error) {
+	if image.image == nil {
+		return "", RbdErrorImageNotOpen
+	}
+
+	buf := make([]byte, 64)
+	for {
+		c_len := C.size_t(len(buf))
+		ret := C.rbd_get_name(image.image,
+			(*C.char)(unsafe.Pointer(&buf[0])), &c_len)
+		if ret == -C.ERANGE {
+			buf = make([]byte, c_len)
+			continue
+		} else if ret < 0 {
+			return "", RBDError(ret)
+		}
+
+		return C.GoString((*C.char)(unsafe.Pointer(&buf[0]))), nil
+	}
+}
@@ -0,0 +1,214 @@
+package rbd
+
+// #cgo LDFLAGS: -lrbd
+// #include <errno.h>
+// #include <stdlib.h>
+// #include <stdbool.h>
+// #include <rados/librados.h>
+// #include <rbd/librbd.h>
+import "C"
+
+import (
+	"github.com/noahdesu/go-ceph/rados"
+	"unsafe"
+)
+
+// ImageOption selects a parameter of new images in ImageOptions.
+type ImageOption int
+
+// Options holding a uint64 value.
+const (
+	ImageOptionFormat            = ImageOption(C.RBD_IMAGE_OPTION_FORMAT)
+	ImageOptionFeatures          = ImageOption(C.RBD_IMAGE_OPTION_FEATURES)
+	ImageOptionOrder             = ImageOption(C.RBD_IMAGE_OPTION_ORDER)
+	ImageOptionStripeUnit        = ImageOption(C.RBD_IMAGE_OPTION_STRIPE_UNIT)
+	ImageOptionStripeCount       = ImageOption(C.RBD_IMAGE_OPTION_STRIPE_COUNT)
+	ImageOptionJournalOrder      = ImageOption(C.RBD_IMAGE_OPTION_JOURNAL_ORDER)
+	ImageOptionJournalSplayWidth = ImageOption(C.RBD_IMAGE_OPTION_JOURNAL_SPLAY_WIDTH)
+	ImageOptionFeaturesSet       = ImageOption(C.RBD_IMAGE_OPTION_FEATURES_SET)
+	ImageOptionFeaturesClear     = ImageOption(C.RBD_IMAGE_OPTION_FEATURES_CLEAR)
+	ImageOptionFlatten           = ImageOption(C.RBD_IMAGE_OPTION_FLATTEN)
+	ImageOptionCloneFormat       = ImageOption(C.RBD_IMAGE_OPTION_CLONE_FORMAT)
+	ImageOptionMirrorImageMode   = ImageOption(C.RBD_IMAGE_OPTION_MIRROR_IMAGE_MODE)
+)
+
+// Options holding a string value.
+const (
+	ImageOptionJournalPool = ImageOption(C.RBD_IMAGE_OPTION_JOURNAL_POOL)
+	ImageOptionDataPool    = ImageOption(C.RBD_IMAGE_OPTION_DATA_POOL)
+)
+
+// ImageOptions holds the parameters of images created by CreateWithOptions,
+// CloneWithOptions, CopyWithOptions and MigrationPrepare. Options that are
+// not set take their value from the configuration. ImageOptions must be
+// released with Destroy.
+type ImageOptions struct {
+	options C.rbd_image_options_t
+}
+
+// NewImageOptions returns empty image options.
+//
+// void rbd_image_options_create(rbd_image_options_t* opts);
+func NewImageOptions() *ImageOptions {
+	opts := &ImageOptions{}
+	C.rbd_image_options_create(&opts.options)
+	return opts
+}
+
+// Destroy releases the image options.
+//
+// void rbd_image_options_destroy(rbd_image_options_t opts);
+func (opts *ImageOptions) Destroy() {
+	C.rbd_image_options_destroy(opts.options)
+}
+
+// SetString sets the string value of option.
+//
+// int rbd_image_options_set_string(rbd_image_options_t opts, int optname, const char* optval);
+func (opts *ImageOptions) SetString(option ImageOption, value string) error {
+	var c_value *C.char = C.CString(value)
+	defer C.free(unsafe.Pointer(c_value))
+
+	return GetError(C.rbd_image_options_set_string(opts.options,
+		C.int(option), c_value))
+}
+
+// GetString returns the string value of option.
+//
+// int rbd_image_options_get_string(rbd_image_options_t opts, int optname, char* optval, size_t maxlen);
+func (opts *ImageOptions) GetString(option ImageOption) (string, error) {
+	buf := make([]byte, 256)
+	for {
+		ret := C.rbd_image_options_get_string(opts.options, C.int(option),
+			(*C.char)(unsafe.Pointer(&buf[0])), C.size_t(len(buf)))
+		if ret == -C.E2BIG {
+			buf = make([]byte, len(buf)*2)
+			continue
+		} else if ret < 0 {
+			return "", GetError(ret)
+		}
+
+		return C.GoString((*C.char)(unsafe.Pointer(&buf[0]))), nil
+	}
+}
+
+// SetUint64 sets the integer value of option.
+//
+// int rbd_image_options_set_uint64(rbd_image_options_t opts, int optname, uint64_t optval);
+func (opts *ImageOptions) SetUint64(option ImageOption, value uint64) error {
+	return GetError(C.rbd_image_options_set_uint64(opts.options,
+		C.int(option), C.uint64_t(value)))
+}
+
+// GetUint64 returns the integer value of option.
+//
+// int rbd_image_options_get_uint64(rbd_image_options_t opts, int optname, uint64_t* optval);
+func (opts *ImageOptions) GetUint64(option ImageOption) (value uint64, err error) {
+	ret := C.rbd_image_options_get_uint64(opts.options, C.int(option),
+		(*C.uint64_t)(&value))
+	if ret < 0 {
+		return 0, GetError(ret)
+	}
+
+	return value, nil
+}
+
+// IsSet reports whether option has been set.
+//
+// int rbd_image_options_is_set(rbd_image_options_t opts, int optname, bool* is_set);
+func (opts *ImageOptions) IsSet(option ImageOption) (bool, error) {
+	var c_is_set C.bool
+	ret := C.rbd_image_options_is_set(opts.options, C.int(option), &c_is_set)
+	if ret < 0 {
+		return false, RBDError(ret)
+	}
+
+	return bool(c_is_set), nil
+}
+
+// Unset removes option, so that its value is again taken from the
+// configuration.
+//
+// int rbd_image_options_unset(rbd_image_options_t opts, int optname);
+func (opts *ImageOptions) Unset(option ImageOption) error {
+	return GetError(C.rbd_image_options_unset(opts.options, C.int(option)))
+}
+
+// Clear removes all options.
+//
+// void rbd_image_options_clear(rbd_image_options_t opts);
+func (opts *ImageOptions) Clear() {
+	C.rbd_image_options_clear(opts.options)
+}
+
+// IsEmpty reports whether no option is set.
+//
+// int rbd_image_options_is_empty(rbd_image_options_t opts);
+func (opts *ImageOptions) IsEmpty() bool {
+	return C.rbd_image_options_is_empty(opts.options) != 0
+}
+
+// CreateWithOptions creates a new image of the given size with the
+// parameters in opts, for example a custom striping or a separate data pool.
+//
+// int rbd_create4(rados_ioctx_t io, const char *name, uint64_t size, rbd_image_options_t opts);
+func CreateWithOptions(ioctx *rados.IOContext, name string, size uint64,
+	opts *ImageOptions) (*Image, error) {
+	var c_name *C.char = C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	ret := C.rbd_create4(C.rados_ioctx_t(ioctx.Pointer()), c_name,
+		C.uint64_t(size), opts.options)
+	if ret < 0 {
+		return nil, RBDError(ret)
+	}
+
+	return &Image{
+		ioctx: ioctx,
+		name:  name,
+	}, nil
+}
+
+// CloneWithOptions clones the snapshot snapname of the image into the new
+// image c_name in c_ioctx with the parameters in opts.
+//
+// int rbd_clone3(rados_ioctx_t p_ioctx, const char *p_name, const char *p_snapname, rados_ioctx_t c_ioctx, const char *c_name, rbd_image_options_t c_opts);
+func (image *Image) CloneWithOptions(snapname string, c_ioctx *rados.IOContext,
+	c_name string, opts *ImageOptions) (*Image, error) {
+	var c_p_name *C.char = C.CString(image.name)
+	var c_p_snapname *C.char = C.CString(snapname)
+	var c_c_name *C.char = C.CString(c_name)
+	defer C.free(unsafe.Pointer(c_p_name))
+	defer C.free(unsafe.Pointer(c_p_snapname))
+	defer C.free(unsafe.Pointer(c_c_name))
+
+	ret := C.rbd_clone3(C.rados_ioctx_t(image.ioctx.Pointer()),
+		c_p_name, c_p_snapname,
+		C.rados_ioctx_t(c_ioctx.Pointer()),
+		c_c_name, opts.options)
+	if ret < 0 {
+		return nil, RBDError(ret)
+	}
+
+	return &Image{
+		ioctx: c_ioctx,
+		name:  c_name,
+	}, nil
+}
+
+// CopyWithOptions copies the data of the image into the new image destName
+// in destIoctx, which is created with the parameters in opts.
+//
+// int rbd_copy3(rbd_image_t src, rados_ioctx_t dest_io_ctx, const char *destname, rbd_image_options_t dest_opts);
+func (image *Image) CopyWithOptions(destIoctx *rados.IOContext, destName string,
+	opts *ImageOptions) error {
+	if image.image == nil {
+		return RbdErrorImageNotOpen
+	}
+
+	var c_destname *C.char = C.CString(destName)
+	defer C.free(unsafe.Pointer(c_destname))
+
+	return GetError(C.rbd_copy3(image.image,
+		C.rados_ioctx_t(destIoctx.Pointer()), c_destname, opts.options))
+}
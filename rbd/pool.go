@@ -0,0 +1,199 @@
+package rbd
+
+// #cgo LDFLAGS: -lrbd
+// #include <errno.h>
+// #include <stdlib.h>
+// #include <stdbool.h>
+// #include <rados/librados.h>
+// #include <rbd/librbd.h>
+import "C"
+
+import (
+	"bytes"
+	"github.com/noahdesu/go-ceph/rados"
+	"unsafe"
+)
+
+// PoolInit initializes a pool for use by RBD. It must be called on new pools
+// before images are created in them.
+//
+// int rbd_pool_init(rados_ioctx_t io, bool force);
+func PoolInit(ioctx *rados.IOContext, force bool) error {
+	return GetError(C.rbd_pool_init(C.rados_ioctx_t(ioctx.Pointer()),
+		C.bool(force)))
+}
+
+// GetPoolMetadata returns the value of the pool-level metadata key.
+//
+// int rbd_pool_metadata_get(rados_ioctx_t io, const char *key, char *value, size_t *val_len);
+func GetPoolMetadata(ioctx *rados.IOContext, key string) (string, error) {
+	var c_key *C.char = C.CString(key)
+	defer C.free(unsafe.Pointer(c_key))
+
+	buf := make([]byte, 64)
+	for {
+		c_len := C.size_t(len(buf))
+		ret := C.rbd_pool_metadata_get(C.rados_ioctx_t(ioctx.Pointer()),
+			c_key, (*C.char)(unsafe.Pointer(&buf[0])), &c_len)
+		if ret == -C.ERANGE {
+			buf = make([]byte, c_len)
+			continue
+		} else if ret < 0 {
+			return "", GetError(ret)
+		}
+
+		return C.GoString((*C.char)(unsafe.Pointer(&buf[0]))), nil
+	}
+}
+
+// SetPoolMetadata sets the pool-level metadata key to value. Keys prefixed
+// with "conf_" override the librbd configuration of all images in the pool.
+//
+// int rbd_pool_metadata_set(rados_ioctx_t io, const char *key, const char *value);
+func SetPoolMetadata(ioctx *rados.IOContext, key, value string) error {
+	var c_key *C.char = C.CString(key)
+	var c_value *C.char = C.CString(value)
+	defer C.free(unsafe.Pointer(c_key))
+	defer C.free(unsafe.Pointer(c_value))
+
+	return GetError(C.rbd_pool_metadata_set(C.rados_ioctx_t(ioctx.Pointer()),
+		c_key, c_value))
+}
+
+// RemovePoolMetadata removes the pool-level metadata key.
+//
+// int rbd_pool_metadata_remove(rados_ioctx_t io, const char *key);
+func RemovePoolMetadata(ioctx *rados.IOContext, key string) error {
+	var c_key *C.char = C.CString(key)
+	defer C.free(unsafe.Pointer(c_key))
+
+	return GetError(C.rbd_pool_metadata_remove(C.rados_ioctx_t(ioctx.Pointer()),
+		c_key))
+}
+
+// poolMetadataPageSize is the number of keys ListPoolMetadata requests per
+// call. The OSDs return at most 64 keys per call regardless of a larger max.
+const poolMetadataPageSize = 64
+
+// ListPoolMetadata returns all pool-level metadata as a map of keys to values.
+//
+// int rbd_pool_metadata_list(rados_ioctx_t io, const char *start, uint64_t max, char *keys, size_t *key_len, char *values, size_t *vals_len);
+func ListPoolMetadata(ioctx *rados.IOContext) (map[string]string, error) {
+	metadata := make(map[string]string)
+	start := ""
+	for {
+		keys, values, err := listPoolMetadataPage(ioctx, start, poolMetadataPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for i, key := range keys {
+			metadata[key] = values[i]
+		}
+		if len(keys) < poolMetadataPageSize {
+			return metadata, nil
+		}
+		start = keys[len(keys)-1]
+	}
+}
+
+// listPoolMetadataPage returns up to max metadata keys and values that
+// sort after start.
+func listPoolMetadataPage(ioctx *rados.IOContext, start string, max int) ([]string, []string, error) {
+	var c_start *C.char = C.CString(start)
+	defer C.free(unsafe.Pointer(c_start))
+
+	keys_buf := make([]byte, 1024)
+	values_buf := make([]byte, 1024)
+	for {
+		c_keys_len := C.size_t(len(keys_buf))
+		c_values_len := C.size_t(len(values_buf))
+		ret := C.rbd_pool_metadata_list(C.rados_ioctx_t(ioctx.Pointer()),
+			c_start, C.uint64_t(max),
+			(*C.char)(unsafe.Pointer(&keys_buf[0])), &c_keys_len,
+			(*C.char)(unsafe.Pointer(&values_buf[0])), &c_values_len)
+		if ret == -C.ERANGE {
+			keys_buf = make([]byte, c_keys_len)
+			values_buf = make([]byte, c_values_len)
+			continue
+		} else if ret < 0 {
+			return nil, nil, RBDError(ret)
+		}
+
+		if c_keys_len == 0 {
+			return nil, nil, nil
+		}
+		// values may be empty, so they are split without dropping empty
+		// elements to keep them aligned with the keys
+		var keys, values []string
+		for _, key := range bytes.Split(keys_buf[:c_keys_len-1], []byte{0}) {
+			keys = append(keys, string(key))
+		}
+		for _, value := range bytes.Split(values_buf[:c_values_len-1], []byte{0}) {
+			values = append(values, string(value))
+		}
+		return keys, values, nil
+	}
+}
+
+// PoolStats summarizes the images of a pool and of its trash.
+// ImageMaxProvisionedBytes includes the provisioned size of all snapshots.
+type PoolStats struct {
+	Images                   uint64
+	ImageProvisionedBytes    uint64
+	ImageMaxProvisionedBytes uint64
+	ImageSnapshots           uint64
+	TrashImages              uint64
+	TrashProvisionedBytes    uint64
+	TrashMaxProvisionedBytes uint64
+	TrashSnapshots           uint64
+}
+
+// GetPoolStats returns the image statistics of the pool.
+//
+// int rbd_pool_stats_option_add_uint64(rbd_pool_stats_t stats, int stat_option, uint64_t* stat_val);
+// int rbd_pool_stats_get(rados_ioctx_t io, rbd_pool_stats_t stats);
+func GetPoolStats(ioctx *rados.IOContext) (*PoolStats, error) {
+	var c_stats C.rbd_pool_stats_t
+	C.rbd_pool_stats_create(&c_stats)
+	defer C.rbd_pool_stats_destroy(c_stats)
+
+	options := []C.int{
+		C.RBD_POOL_STAT_OPTION_IMAGES,
+		C.RBD_POOL_STAT_OPTION_IMAGE_PROVISIONED_BYTES,
+		C.RBD_POOL_STAT_OPTION_IMAGE_MAX_PROVISIONED_BYTES,
+		C.RBD_POOL_STAT_OPTION_IMAGE_SNAPSHOTS,
+		C.RBD_POOL_STAT_OPTION_TRASH_IMAGES,
+		C.RBD_POOL_STAT_OPTION_TRASH_PROVISIONED_BYTES,
+		C.RBD_POOL_STAT_OPTION_TRASH_MAX_PROVISIONED_BYTES,
+		C.RBD_POOL_STAT_OPTION_TRASH_SNAPSHOTS,
+	}
+
+	// librbd keeps the value pointers until rbd_pool_stats_get, so they
+	// must point to C memory
+	c_values := (*[1 << 16]C.uint64_t)(C.calloc(C.size_t(len(options)),
+		C.size_t(unsafe.Sizeof(C.uint64_t(0)))))[:len(options):len(options)]
+	defer C.free(unsafe.Pointer(&c_values[0]))
+
+	for i, option := range options {
+		ret := C.rbd_pool_stats_option_add_uint64(c_stats, option, &c_values[i])
+		if ret < 0 {
+			return nil, RBDError(ret)
+		}
+	}
+
+	ret := C.rbd_pool_stats_get(C.rados_ioctx_t(ioctx.Pointer()), c_stats)
+	if ret < 0 {
+		return nil, GetError(ret)
+	}
+
+	return &PoolStats{
+		Images:                   uint64(c_values[0]),
+		ImageProvisionedBytes:    uint64(c_values[1]),
+		ImageMaxProvisionedBytes: uint64(c_values[2]),
+		ImageSnapshots:           uint64(c_values[3]),
+		TrashImages:              uint64(c_values[4]),
+		TrashProvisionedBytes:    uint64(c_values[5]),
+		TrashMaxProvisionedBytes: uint64(c_values[6]),
+		TrashSnapshots:           uint64(c_values[7]),
+	}, nil
+}
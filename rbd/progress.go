@@ -0,0 +1,210 @@
+package rbd
+
+/*
+#cgo LDFLAGS: -lrbd
+#include <errno.h>
+#include <stdint.h>
+#include <stdlib.h>
+#include <stdbool.h>
+#include <rados/librados.h>
+#include <rbd/librbd.h>
+
+extern int progressCallback(uint64_t offset, uint64_t total, uintptr_t index);
+
+static inline int wrap_rbd_remove_with_progress(rados_ioctx_t io,
+		const char *name, uintptr_t index) {
+	return rbd_remove_with_progress(io, name,
+		(librbd_progress_fn_t)progressCallback, (void *)index);
+}
+
+static inline int wrap_rbd_copy_with_progress(rbd_image_t image,
+		rados_ioctx_t dest_p, const char *destname, uintptr_t index) {
+	return rbd_copy_with_progress(image, dest_p, destname,
+		(librbd_progress_fn_t)progressCallback, (void *)index);
+}
+
+static inline int wrap_rbd_deep_copy_with_progress(rbd_image_t image,
+		rados_ioctx_t dest_io_ctx, const char *destname,
+		rbd_image_options_t dest_opts, uintptr_t index) {
+	return rbd_deep_copy_with_progress(image, dest_io_ctx, destname,
+		dest_opts, (librbd_progress_fn_t)progressCallback, (void *)index);
+}
+
+static inline int wrap_rbd_migration_execute_with_progress(
+		rados_ioctx_t ioctx, const char *image_name, uintptr_t index) {
+	return rbd_migration_execute_with_progress(ioctx, image_name,
+		(librbd_progress_fn_t)progressCallback, (void *)index);
+}
+
+static inline int wrap_rbd_migration_commit_with_progress(
+		rados_ioctx_t ioctx, const char *image_name, uintptr_t index) {
+	return rbd_migration_commit_with_progress(ioctx, image_name,
+		(librbd_progress_fn_t)progressCallback, (void *)index);
+}
+
+static inline int wrap_rbd_migration_abort_with_progress(
+		rados_ioctx_t ioctx, const char *image_name, uintptr_t index) {
+	return rbd_migration_abort_with_progress(ioctx, image_name,
+		(librbd_progress_fn_t)progressCallback, (void *)index);
+}
+
+static inline int wrap_rbd_trash_remove_with_progress(rados_ioctx_t io,
+		const char *id, bool force, uintptr_t index) {
+	return rbd_trash_remove_with_progress(io, id, force,
+		(librbd_progress_fn_t)progressCallback, (void *)index);
+}
+*/
+import "C"
+
+import (
+	"github.com/noahdesu/go-ceph/internal/callbacks"
+	"github.com/noahdesu/go-ceph/rados"
+	"unsafe"
+)
+
+// ProgressFunc is the type of the function called to report the progress of
+// long running operations. offset is the amount of work done out of total.
+type ProgressFunc func(offset, total uint64)
+
+var progressCallbacks = callbacks.New()
+
+//export progressCallback
+func progressCallback(offset, total C.uint64_t, index C.uintptr_t) C.int {
+	v := progressCallbacks.Lookup(uintptr(index))
+	if progressFn, ok := v.(ProgressFunc); ok && progressFn != nil {
+		progressFn(uint64(offset), uint64(total))
+	}
+	return 0
+}
+
+// RemoveWithProgress removes the image like Remove and calls progressFn as
+// the data objects of the image are deleted.
+//
+// int rbd_remove_with_progress(rados_ioctx_t io, const char *name, librbd_progress_fn_t cb, void *cbdata);
+func (image *Image) RemoveWithProgress(progressFn ProgressFunc) error {
+	var c_name *C.char = C.CString(image.name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	index := progressCallbacks.Add(progressFn)
+	defer progressCallbacks.Remove(index)
+
+	return GetError(C.wrap_rbd_remove_with_progress(
+		C.rados_ioctx_t(image.ioctx.Pointer()), c_name, C.uintptr_t(index)))
+}
+
+// CopyWithProgress copies the data of the image into the new image destName
+// in destIoctx and calls progressFn as the copy advances.
+//
+// int rbd_copy_with_progress(rbd_image_t image, rados_ioctx_t dest_p, const char *destname, librbd_progress_fn_t cb, void *cbdata);
+func (image *Image) CopyWithProgress(destIoctx *rados.IOContext, destName string,
+	progressFn ProgressFunc) error {
+	if image.image == nil {
+		return RbdErrorImageNotOpen
+	}
+
+	var c_destname *C.char = C.CString(destName)
+	defer C.free(unsafe.Pointer(c_destname))
+
+	index := progressCallbacks.Add(progressFn)
+	defer progressCallbacks.Remove(index)
+
+	return GetError(C.wrap_rbd_copy_with_progress(image.image,
+		C.rados_ioctx_t(destIoctx.Pointer()), c_destname, C.uintptr_t(index)))
+}
+
+// DeepCopy copies the image including its snapshots into the new image
+// destName in destIoctx. Unlike Copy, a deep copy of a clone is again a
+// clone of the same parent.
+func (image *Image) DeepCopy(destIoctx *rados.IOContext, destName string) error {
+	return image.DeepCopyWithProgress(destIoctx, destName, nil)
+}
+
+// DeepCopyWithProgress deep copies the image like DeepCopy and calls
+// progressFn as the copy advances.
+//
+// int rbd_deep_copy_with_progress(rbd_image_t image, rados_ioctx_t dest_io_ctx, const char *destname, rbd_image_options_t dest_opts, librbd_progress_fn_t cb, void *cbdata);
+func (image *Image) DeepCopyWithProgress(destIoctx *rados.IOContext,
+	destName string, progressFn ProgressFunc) error {
+	if image.image == nil {
+		return RbdErrorImageNotOpen
+	}
+
+	var c_destname *C.char = C.CString(destName)
+	defer C.free(unsafe.Pointer(c_destname))
+
+	var c_opts C.rbd_image_options_t
+	C.rbd_image_options_create(&c_opts)
+	defer C.rbd_image_options_destroy(c_opts)
+
+	index := progressCallbacks.Add(progressFn)
+	defer progressCallbacks.Remove(index)
+
+	return GetError(C.wrap_rbd_deep_copy_with_progress(image.image,
+		C.rados_ioctx_t(destIoctx.Pointer()), c_destname, c_opts,
+		C.uintptr_t(index)))
+}
+
+// MigrationExecuteWithProgress executes the migration like MigrationExecute
+// and calls progressFn as the data is copied.
+//
+// int rbd_migration_execute_with_progress(rados_ioctx_t ioctx, const char *image_name, librbd_progress_fn_t cb, void *cbdata);
+func MigrationExecuteWithProgress(ioctx *rados.IOContext, name string,
+	progressFn ProgressFunc) error {
+	var c_name *C.char = C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	index := progressCallbacks.Add(progressFn)
+	defer progressCallbacks.Remove(index)
+
+	return GetError(C.wrap_rbd_migration_execute_with_progress(
+		C.rados_ioctx_t(ioctx.Pointer()), c_name, C.uintptr_t(index)))
+}
+
+// MigrationCommitWithProgress commits the migration like MigrationCommit and
+// calls progressFn as the source image is removed.
+//
+// int rbd_migration_commit_with_progress(rados_ioctx_t ioctx, const char *image_name, librbd_progress_fn_t cb, void *cbdata);
+func MigrationCommitWithProgress(ioctx *rados.IOContext, name string,
+	progressFn ProgressFunc) error {
+	var c_name *C.char = C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	index := progressCallbacks.Add(progressFn)
+	defer progressCallbacks.Remove(index)
+
+	return GetError(C.wrap_rbd_migration_commit_with_progress(
+		C.rados_ioctx_t(ioctx.Pointer()), c_name, C.uintptr_t(index)))
+}
+
+// MigrationAbortWithProgress aborts the migration like MigrationAbort and
+// calls progressFn as the destination image is removed.
+//
+// int rbd_migration_abort_with_progress(rados_ioctx_t ioctx, const char *image_name, librbd_progress_fn_t cb, void *cbdata);
+func MigrationAbortWithProgress(ioctx *rados.IOContext, name string,
+	progressFn ProgressFunc) error {
+	var c_name *C.char = C.CString(name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	index := progressCallbacks.Add(progressFn)
+	defer progressCallbacks.Remove(index)
+
+	return GetError(C.wrap_rbd_migration_abort_with_progress(
+		C.rados_ioctx_t(ioctx.Pointer()), c_name, C.uintptr_t(index)))
+}
+
+// TrashRemoveWithProgress deletes the trashed image like TrashRemove and
+// calls progressFn as the data objects of the image are deleted.
+//
+// int rbd_trash_remove_with_progress(rados_ioctx_t io, const char *id, bool force, librbd_progress_fn_t cb, void *cbdata);
+func TrashRemoveWithProgress(ioctx *rados.IOContext, id string, force bool,
+	progressFn ProgressFunc) error {
+	var c_id *C.char = C.CString(id)
+	defer C.free(unsafe.Pointer(c_id))
+
+	index := progressCallbacks.Add(progressFn)
+	defer progressCallbacks.Remove(index)
+
+	return GetError(C.wrap_rbd_trash_remove_with_progress(
+		C.rados_ioctx_t(ioctx.Pointer()), c_id, C.bool(force),
+		C.uintptr_t(index)))
+}
@@ -21,10 +21,21 @@ type RBDError int
 
 var RbdErrorImageNotOpen = errors.New("RBD image not open")
 var RbdErrorNotFound = errors.New("RBD image not found")
+var RbdErrorSnapshotNameRequired = errors.New("RBD snapshot name required")
+var RbdErrorCallbackRequired = errors.New("RBD callback function required")
 
 //Rdb feature
 var RbdFeatureLayering = uint64(1 << 0)
 var RbdFeatureStripingV2 = uint64(1 << 1)
+var RbdFeatureExclusiveLock = uint64(1 << 2)
+var RbdFeatureObjectMap = uint64(1 << 3)
+var RbdFeatureFastDiff = uint64(1 << 4)
+var RbdFeatureDeepFlatten = uint64(1 << 5)
+var RbdFeatureJournaling = uint64(1 << 6)
+var RbdFeatureDataPool = uint64(1 << 7)
+
+// Flags for WriteZeroes
+const RbdWriteZeroesFlagThickProvision = int(C.RBD_WRITE_ZEROES_FLAG_THICK_PROVISION)
 
 //
 type ImageInfo struct {
@@ -119,6 +130,9 @@ func GetImageNames(ioctx *rados.IOContext) (names []string, err error) {
 		} else if ret < 0 {
 			return nil, RBDError(ret)
 		}
+		if size == 0 {
+			return names, nil
+		}
 		tmp := bytes.Split(buf[:size-1], []byte{0})
 		for _, s := range tmp {
 			if len(s) > 0 {
@@ -130,6 +144,41 @@ func GetImageNames(ioctx *rados.IOContext) (names []string, err error) {
 	}
 }
 
+// ImageSpec identifies an image by both its name and its id. Unlike the
+// name, the id of an image does not change when the image is renamed.
+type ImageSpec struct {
+	Id   string
+	Name string
+}
+
+// GetImageList returns the name and id of every image in the pool.
+//
+// int rbd_list2(rados_ioctx_t io, rbd_image_spec_t *images, size_t *max_images);
+// void rbd_image_spec_list_cleanup(rbd_image_spec_t *images, size_t num_images);
+func GetImageList(ioctx *rados.IOContext) ([]ImageSpec, error) {
+	var c_max_images C.size_t = 32
+	for {
+		c_images := make([]C.rbd_image_spec_t, c_max_images)
+		ret := C.rbd_list2(C.rados_ioctx_t(ioctx.Pointer()), &c_images[0],
+			&c_max_images)
+		if ret == -C.ERANGE {
+			continue
+		} else if ret < 0 {
+			return nil, RBDError(ret)
+		}
+
+		images := make([]ImageSpec, c_max_images)
+		for i := range images {
+			images[i] = ImageSpec{
+				Id:   C.GoString(c_images[i].id),
+				Name: C.GoString(c_images[i].name),
+			}
+		}
+		C.rbd_image_spec_list_cleanup(&c_images[0], c_max_images)
+		return images, nil
+	}
+}
+
 //
 func GetImage(ioctx *rados.IOContext, name string) *Image {
 	return &Image{
@@ -668,6 +717,26 @@ func (image *Image) Discard(ofs uint64, length uint64) error {
 		C.uint64_t(length)))
 }
 
+// WriteZeroes zeroes length bytes of the image starting at ofs. Unlike
+// Discard, the range is guaranteed to read back as zeroes. With
+// RbdWriteZeroesFlagThickProvision the zeroes are written out instead of
+// deallocating the backing objects.
+//
+// int rbd_write_zeroes(rbd_image_t image, uint64_t ofs, size_t len, int zero_flags, int op_flags);
+func (image *Image) WriteZeroes(ofs uint64, length uint64, flags int) error {
+	if image.image == nil {
+		return RbdErrorImageNotOpen
+	}
+
+	ret := C.rbd_write_zeroes(image.image, C.uint64_t(ofs),
+		C.size_t(length), C.int(flags), 0)
+	if ret < 0 {
+		return RBDError(ret)
+	}
+
+	return nil
+}
+
 func (image *Image) ReadAt(data []byte, off int64) (n int, err error) {
 	_, err = image.Seek(off, 0)
 	if err != nil {
@@ -684,11 +753,29 @@ func (image *Image) WriteAt(data []byte, off int64) (n int, err error) {
 	return image.Write(data)
 }
 
+// Flush writes back all data held in the librbd cache.
+//
 // int rbd_flush(rbd_image_t image);
 func (image *Image) Flush() error {
+	if image.image == nil {
+		return RbdErrorImageNotOpen
+	}
+
 	return GetError(C.rbd_flush(image.image))
 }
 
+// InvalidateCache flushes and then drops all data held in the librbd cache,
+// so that subsequent reads are served from the cluster.
+//
+// int rbd_invalidate_cache(rbd_image_t image);
+func (image *Image) InvalidateCache() error {
+	if image.image == nil {
+		return RbdErrorImageNotOpen
+	}
+
+	return GetError(C.rbd_invalidate_cache(image.image))
+}
+
 // int rbd_snap_list(rbd_image_t image, rbd_snap_info_t *snaps, int *max_snaps);
 // void rbd_snap_list_end(rbd_snap_info_t *snaps);
 func (image *Image) GetSnapshotNames() (snaps []SnapInfo, err error) {
@@ -3,12 +3,14 @@ package rbd_test
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"github.com/noahdesu/go-ceph/rados"
 	"github.com/noahdesu/go-ceph/rbd"
 	"github.com/stretchr/testify/assert"
 	"os/exec"
 	"sort"
 	"testing"
+	"time"
 )
 
 func GetUUID() string {
@@ -89,6 +91,9 @@ func TestIOReaderWriter(t *testing.T) {
 	err = img.Flush()
 	assert.NoError(t, err)
 
+	err = img.InvalidateCache()
+	assert.NoError(t, err)
+
 	_, err = img.Seek(0, 0)
 	assert.NoError(t, err)
 
@@ -256,3 +261,1640 @@ func TestNotFound(t *testing.T) {
 	conn.DeletePool(poolname)
 	conn.Shutdown()
 }
+
+func TestTrashImage(t *testing.T) {
+	conn, _ := rados.NewConn()
+	conn.ReadDefaultConfigFile()
+	conn.Connect()
+
+	poolname := GetUUID()
+	err := conn.MakePool(poolname)
+	assert.NoError(t, err)
+
+	ioctx, err := conn.OpenIOContext(poolname)
+	assert.NoError(t, err)
+
+	name := GetUUID()
+	img, err := rbd.Create(ioctx, name, 1<<22)
+	assert.NoError(t, err)
+
+	err = img.Trash(time.Hour)
+	assert.NoError(t, err)
+
+	trashList, err := rbd.GetTrashList(ioctx)
+	assert.NoError(t, err)
+	assert.Equal(t, len(trashList), 1)
+	assert.Equal(t, trashList[0].Name, name)
+
+	// the deferment delay has not expired yet
+	err = rbd.TrashRemove(ioctx, trashList[0].Id, false)
+	assert.Error(t, err)
+
+	err = rbd.TrashRestore(ioctx, trashList[0].Id, "")
+	assert.NoError(t, err)
+
+	imageNames, err := rbd.GetImageNames(ioctx)
+	assert.NoError(t, err)
+	assert.Equal(t, imageNames, []string{name})
+
+	err = img.Trash(0)
+	assert.NoError(t, err)
+
+	err = rbd.TrashPurge(ioctx, time.Now().Add(time.Minute), -1)
+	assert.NoError(t, err)
+
+	trashList, err = rbd.GetTrashList(ioctx)
+	assert.NoError(t, err)
+	assert.Equal(t, len(trashList), 0)
+
+	ioctx.Destroy()
+	conn.DeletePool(poolname)
+	conn.Shutdown()
+}
+
+func TestNamespace(t *testing.T) {
+	conn, _ := rados.NewConn()
+	conn.ReadDefaultConfigFile()
+	conn.Connect()
+
+	poolname := GetUUID()
+	err := conn.MakePool(poolname)
+	assert.NoError(t, err)
+
+	ioctx, err := conn.OpenIOContext(poolname)
+	assert.NoError(t, err)
+
+	err = rbd.NamespaceCreate(ioctx, "ns1")
+	assert.NoError(t, err)
+
+	exists, err := rbd.NamespaceExists(ioctx, "ns1")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	namespaces, err := rbd.NamespaceList(ioctx)
+	assert.NoError(t, err)
+	assert.Equal(t, namespaces, []string{"ns1"})
+
+	ioctx.SetNamespace("ns1")
+	name := GetUUID()
+	_, err = rbd.Create(ioctx, name, 1<<22)
+	assert.NoError(t, err)
+
+	imageNames, err := rbd.GetImageNames(ioctx)
+	assert.NoError(t, err)
+	assert.Equal(t, imageNames, []string{name})
+
+	err = rbd.GetImage(ioctx, name).Remove()
+	assert.NoError(t, err)
+
+	ioctx.SetNamespace("")
+	err = rbd.NamespaceRemove(ioctx, "ns1")
+	assert.NoError(t, err)
+
+	exists, err = rbd.NamespaceExists(ioctx, "ns1")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	ioctx.Destroy()
+	conn.DeletePool(poolname)
+	conn.Shutdown()
+}
+
+func TestPoolMetadata(t *testing.T) {
+	conn, _ := rados.NewConn()
+	conn.ReadDefaultConfigFile()
+	conn.Connect()
+
+	poolname := GetUUID()
+	err := conn.MakePool(poolname)
+	assert.NoError(t, err)
+
+	ioctx, err := conn.OpenIOContext(poolname)
+	assert.NoError(t, err)
+
+	err = rbd.PoolInit(ioctx, false)
+	assert.NoError(t, err)
+
+	err = rbd.SetPoolMetadata(ioctx, "conf_rbd_qos_iops_limit", "1000")
+	assert.NoError(t, err)
+
+	err = rbd.SetPoolMetadata(ioctx, "empty", "")
+	assert.NoError(t, err)
+
+	value, err := rbd.GetPoolMetadata(ioctx, "conf_rbd_qos_iops_limit")
+	assert.NoError(t, err)
+	assert.Equal(t, value, "1000")
+
+	metadata, err := rbd.ListPoolMetadata(ioctx)
+	assert.NoError(t, err)
+	assert.Equal(t, metadata, map[string]string{
+		"conf_rbd_qos_iops_limit": "1000",
+		"empty":                   "",
+	})
+
+	err = rbd.RemovePoolMetadata(ioctx, "conf_rbd_qos_iops_limit")
+	assert.NoError(t, err)
+
+	_, err = rbd.GetPoolMetadata(ioctx, "conf_rbd_qos_iops_limit")
+	assert.Equal(t, err, rbd.RbdErrorNotFound)
+
+	ioctx.Destroy()
+	conn.DeletePool(poolname)
+	conn.Shutdown()
+}
+
+func TestPoolMetadataPaging(t *testing.T) {
+	conn, _ := rados.NewConn()
+	conn.ReadDefaultConfigFile()
+	conn.Connect()
+
+	poolname := GetUUID()
+	err := conn.MakePool(poolname)
+	assert.NoError(t, err)
+
+	ioctx, err := conn.OpenIOContext(poolname)
+	assert.NoError(t, err)
+
+	err = rbd.PoolInit(ioctx, false)
+	assert.NoError(t, err)
+
+	// more than the 64 keys the OSDs return per call
+	expected := map[string]string{}
+	for i := 0; i < 150; i++ {
+		key := fmt.Sprintf("key%03d", i)
+		value := fmt.Sprintf("value%d", i)
+		err = rbd.SetPoolMetadata(ioctx, key, value)
+		assert.NoError(t, err)
+		expected[key] = value
+	}
+
+	metadata, err := rbd.ListPoolMetadata(ioctx)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, metadata)
+
+	ioctx.Destroy()
+	conn.DeletePool(poolname)
+	conn.Shutdown()
+}
+
+func TestMigration(t *testing.T) {
+	conn, _ := rados.NewConn()
+	conn.ReadDefaultConfigFile()
+	conn.Connect()
+
+	poolname := GetUUID()
+	err := conn.MakePool(poolname)
+	assert.NoError(t, err)
+
+	ioctx, err := conn.OpenIOContext(poolname)
+	assert.NoError(t, err)
+
+	name := GetUUID()
+	_, err = rbd.Create(ioctx, name, 1<<22, rbd.RbdFeatureLayering)
+	assert.NoError(t, err)
+
+	destName := GetUUID()
+	err = rbd.MigrationPrepare(ioctx, name, ioctx, destName, nil)
+	assert.NoError(t, err)
+
+	status, err := rbd.GetMigrationStatus(ioctx, destName)
+	assert.NoError(t, err)
+	assert.Equal(t, status.SourceImageName, name)
+	assert.Equal(t, status.DestImageName, destName)
+	assert.Equal(t, status.State, rbd.MigrationStatePrepared)
+
+	err = rbd.MigrationExecute(ioctx, destName)
+	assert.NoError(t, err)
+
+	err = rbd.MigrationCommit(ioctx, destName)
+	assert.NoError(t, err)
+
+	imageNames, err := rbd.GetImageNames(ioctx)
+	assert.NoError(t, err)
+	assert.Equal(t, imageNames, []string{destName})
+
+	err = rbd.GetImage(ioctx, destName).Remove()
+	assert.NoError(t, err)
+
+	ioctx.Destroy()
+	conn.DeletePool(poolname)
+	conn.Shutdown()
+}
+
+func TestMirroring(t *testing.T) {
+	conn, _ := rados.NewConn()
+	conn.ReadDefaultConfigFile()
+	conn.Connect()
+
+	poolname := GetUUID()
+	err := conn.MakePool(poolname)
+	assert.NoError(t, err)
+
+	ioctx, err := conn.OpenIOContext(poolname)
+	assert.NoError(t, err)
+
+	mode, err := rbd.MirrorModeGet(ioctx)
+	assert.NoError(t, err)
+	assert.Equal(t, mode, rbd.MirrorModeDisabled)
+
+	err = rbd.MirrorModeSet(ioctx, rbd.MirrorModeImage)
+	assert.NoError(t, err)
+
+	mode, err = rbd.MirrorModeGet(ioctx)
+	assert.NoError(t, err)
+	assert.Equal(t, mode, rbd.MirrorModeImage)
+
+	uuid, err := rbd.MirrorPeerSiteAdd(ioctx, rbd.MirrorPeerDirectionRxTx,
+		"remote", "client.rbd-mirror-peer")
+	assert.NoError(t, err)
+
+	peers, err := rbd.MirrorPeerSiteList(ioctx)
+	assert.NoError(t, err)
+	assert.Equal(t, len(peers), 1)
+	assert.Equal(t, peers[0].Uuid, uuid)
+	assert.Equal(t, peers[0].SiteName, "remote")
+
+	err = rbd.MirrorPeerSiteRemove(ioctx, uuid)
+	assert.NoError(t, err)
+
+	token, err := rbd.MirrorPeerBootstrapCreate(ioctx)
+	assert.NoError(t, err)
+	assert.NotEqual(t, token, "")
+
+	name := GetUUID()
+	img, err := rbd.Create(ioctx, name, 1<<22, rbd.RbdFeatureLayering)
+	assert.NoError(t, err)
+
+	err = img.Open()
+	assert.NoError(t, err)
+
+	err = img.MirrorEnable(rbd.ImageMirrorModeSnapshot)
+	assert.NoError(t, err)
+
+	info, err := img.GetMirrorImageInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, info.State, rbd.MirrorImageEnabled)
+	assert.True(t, info.Primary)
+
+	status, err := img.GetGlobalMirrorStatus()
+	assert.NoError(t, err)
+	assert.Equal(t, status.Name, name)
+	assert.Equal(t, status.Info.GlobalId, info.GlobalId)
+
+	_, err = rbd.MirrorImageStatusSummary(ioctx)
+	assert.NoError(t, err)
+
+	statuses := map[string]rbd.MirrorImageGlobalStatus{}
+	err = rbd.ListMirrorImageGlobalStatus(ioctx,
+		func(id string, status rbd.MirrorImageGlobalStatus) {
+			statuses[id] = status
+		})
+	assert.NoError(t, err)
+	assert.Equal(t, len(statuses), 1)
+
+	err = img.MirrorDemote()
+	assert.NoError(t, err)
+
+	err = img.MirrorPromote(false)
+	assert.NoError(t, err)
+
+	err = img.MirrorDisable(false)
+	assert.NoError(t, err)
+
+	err = img.Close()
+	assert.NoError(t, err)
+
+	err = img.Remove()
+	assert.NoError(t, err)
+
+	err = rbd.MirrorModeSet(ioctx, rbd.MirrorModeDisabled)
+	assert.NoError(t, err)
+
+	ioctx.Destroy()
+	conn.DeletePool(poolname)
+	conn.Shutdown()
+}
+
+func TestGroups(t *testing.T) {
+	conn, _ := rados.NewConn()
+	conn.ReadDefaultConfigFile()
+	conn.Connect()
+
+	poolname := GetUUID()
+	err := conn.MakePool(poolname)
+	assert.NoError(t, err)
+
+	ioctx, err := conn.OpenIOContext(poolname)
+	assert.NoError(t, err)
+
+	err = rbd.GroupCreate(ioctx, "group1")
+	assert.NoError(t, err)
+
+	err = rbd.GroupRename(ioctx, "group1", "vm-disks")
+	assert.NoError(t, err)
+
+	groups, err := rbd.GroupList(ioctx)
+	assert.NoError(t, err)
+	assert.Equal(t, groups, []string{"vm-disks"})
+
+	createdList := []string{}
+	for i := 0; i < 2; i++ {
+		name := GetUUID()
+		_, err := rbd.Create(ioctx, name, 1<<22, rbd.RbdFeatureLayering)
+		assert.NoError(t, err)
+		err = rbd.GroupImageAdd(ioctx, "vm-disks", ioctx, name)
+		assert.NoError(t, err)
+		createdList = append(createdList, name)
+	}
+
+	images, err := rbd.GroupImageList(ioctx, "vm-disks")
+	assert.NoError(t, err)
+	assert.Equal(t, len(images), 2)
+
+	err = rbd.GroupSnapCreate(ioctx, "vm-disks", "snap1")
+	assert.NoError(t, err)
+
+	snaps, err := rbd.GroupSnapList(ioctx, "vm-disks")
+	assert.NoError(t, err)
+	assert.Equal(t, snaps, []rbd.GroupSnapInfo{
+		{Name: "snap1", State: rbd.GroupSnapStateComplete},
+	})
+
+	err = rbd.GroupSnapRollback(ioctx, "vm-disks", "snap1")
+	assert.NoError(t, err)
+
+	err = rbd.GroupSnapRemove(ioctx, "vm-disks", "snap1")
+	assert.NoError(t, err)
+
+	for _, name := range createdList {
+		err = rbd.GroupImageRemove(ioctx, "vm-disks", ioctx, name)
+		assert.NoError(t, err)
+		err = rbd.GetImage(ioctx, name).Remove()
+		assert.NoError(t, err)
+	}
+
+	err = rbd.GroupRemove(ioctx, "vm-disks")
+	assert.NoError(t, err)
+
+	ioctx.Destroy()
+	conn.DeletePool(poolname)
+	conn.Shutdown()
+}
+
+func TestManagedLock(t *testing.T) {
+	conn, _ := rados.NewConn()
+	conn.ReadDefaultConfigFile()
+	conn.Connect()
+
+	poolname := GetUUID()
+	err := conn.MakePool(poolname)
+	assert.NoError(t, err)
+
+	ioctx, err := conn.OpenIOContext(poolname)
+	assert.NoError(t, err)
+
+	name := GetUUID()
+	img, err := rbd.Create(ioctx, name, 1<<22,
+		rbd.RbdFeatureLayering|rbd.RbdFeatureExclusiveLock)
+	assert.NoError(t, err)
+
+	err = img.Open()
+	assert.NoError(t, err)
+
+	err = img.LockAcquire(rbd.LockModeExclusive)
+	assert.NoError(t, err)
+
+	owner, err := img.IsExclusiveLockOwner()
+	assert.NoError(t, err)
+	assert.True(t, owner)
+
+	mode, owners, err := img.LockGetOwners()
+	assert.NoError(t, err)
+	assert.Equal(t, mode, rbd.LockModeExclusive)
+	assert.Equal(t, len(owners), 1)
+
+	err = img.LockRelease()
+	assert.NoError(t, err)
+
+	owner, err = img.IsExclusiveLockOwner()
+	assert.NoError(t, err)
+	assert.False(t, owner)
+
+	_, owners, err = img.LockGetOwners()
+	assert.NoError(t, err)
+	assert.Equal(t, len(owners), 0)
+
+	err = img.Close()
+	assert.NoError(t, err)
+
+	err = img.Remove()
+	assert.NoError(t, err)
+
+	ioctx.Destroy()
+	conn.DeletePool(poolname)
+	conn.Shutdown()
+}
+
+func TestListWatchers(t *testing.T) {
+	conn, _ := rados.NewConn()
+	conn.ReadDefaultConfigFile()
+	conn.Connect()
+
+	poolname := GetUUID()
+	err := conn.MakePool(poolname)
+	assert.NoError(t, err)
+
+	ioctx, err := conn.OpenIOContext(poolname)
+	assert.NoError(t, err)
+
+	name := GetUUID()
+	img, err := rbd.Create(ioctx, name, 1<<22, rbd.RbdFeatureLayering)
+	assert.NoError(t, err)
+
+	err = img.Open()
+	assert.NoError(t, err)
+
+	watchers, err := img.ListWatchers()
+	assert.NoError(t, err)
+	assert.Equal(t, len(watchers), 1)
+
+	err = img.Close()
+	assert.NoError(t, err)
+
+	// a read-only open does not watch the image
+	err = img.Open(true)
+	assert.NoError(t, err)
+
+	watchers, err = img.ListWatchers()
+	assert.NoError(t, err)
+	assert.Equal(t, len(watchers), 0)
+
+	err = img.Close()
+	assert.NoError(t, err)
+
+	err = img.Remove()
+	assert.NoError(t, err)
+
+	ioctx.Destroy()
+	conn.DeletePool(poolname)
+	conn.Shutdown()
+}
+
+func TestUpdateWatch(t *testing.T) {
+	conn, _ := rados.NewConn()
+	conn.ReadDefaultConfigFile()
+	conn.Connect()
+
+	poolname := GetUUID()
+	err := conn.MakePool(poolname)
+	assert.NoError(t, err)
+
+	ioctx, err := conn.OpenIOContext(poolname)
+	assert.NoError(t, err)
+
+	name := GetUUID()
+	img, err := rbd.Create(ioctx, name, 1<<22, rbd.RbdFeatureLayering)
+	assert.NoError(t, err)
+
+	err = img.Open()
+	assert.NoError(t, err)
+
+	_, err = img.UpdateWatch(nil)
+	assert.Equal(t, err, rbd.RbdErrorCallbackRequired)
+
+	updated := make(chan bool, 16)
+	watch, err := img.UpdateWatch(func() {
+		updated <- true
+	})
+	assert.NoError(t, err)
+
+	err = img.Resize(1 << 23)
+	assert.NoError(t, err)
+
+	select {
+	case <-updated:
+	case <-time.After(10 * time.Second):
+		t.Error("timed out waiting for update notification")
+	}
+
+	err = watch.Unwatch()
+	assert.NoError(t, err)
+
+	err = img.Close()
+	assert.NoError(t, err)
+
+	err = img.Remove()
+	assert.NoError(t, err)
+
+	ioctx.Destroy()
+	conn.DeletePool(poolname)
+	conn.Shutdown()
+}
+
+func TestEncryption(t *testing.T) {
+	conn, _ := rados.NewConn()
+	conn.ReadDefaultConfigFile()
+	conn.Connect()
+
+	poolname := GetUUID()
+	err := conn.MakePool(poolname)
+	assert.NoError(t, err)
+
+	ioctx, err := conn.OpenIOContext(poolname)
+	assert.NoError(t, err)
+
+	name := GetUUID()
+	img, err := rbd.Create(ioctx, name, 1<<25, rbd.RbdFeatureLayering)
+	assert.NoError(t, err)
+
+	err = img.Open()
+	assert.NoError(t, err)
+
+	passphrase := []byte("sesame-open")
+	err = img.EncryptionFormat(rbd.EncryptionOptionsLUKS2{
+		Alg:        rbd.EncryptionAlgorithmAES256,
+		Passphrase: passphrase,
+	})
+	assert.NoError(t, err)
+
+	err = img.Close()
+	assert.NoError(t, err)
+
+	err = img.Open()
+	assert.NoError(t, err)
+
+	err = img.EncryptionLoad(rbd.EncryptionOptionsLUKS{Passphrase: passphrase})
+	assert.NoError(t, err)
+
+	bytes_in := []byte("input data")
+	_, err = img.WriteAt(bytes_in, 0)
+	assert.NoError(t, err)
+
+	bytes_out := make([]byte, len(bytes_in))
+	_, err = img.ReadAt(bytes_out, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, bytes_in, bytes_out)
+
+	err = img.Close()
+	assert.NoError(t, err)
+
+	err = img.Remove()
+	assert.NoError(t, err)
+
+	ioctx.Destroy()
+	conn.DeletePool(poolname)
+	conn.Shutdown()
+}
+
+func TestRebuildObjectMap(t *testing.T) {
+	conn, _ := rados.NewConn()
+	conn.ReadDefaultConfigFile()
+	conn.Connect()
+
+	poolname := GetUUID()
+	err := conn.MakePool(poolname)
+	assert.NoError(t, err)
+
+	ioctx, err := conn.OpenIOContext(poolname)
+	assert.NoError(t, err)
+
+	name := GetUUID()
+	img, err := rbd.Create(ioctx, name, 1<<24, rbd.RbdFeatureLayering|
+		rbd.RbdFeatureExclusiveLock|rbd.RbdFeatureObjectMap)
+	assert.NoError(t, err)
+
+	err = img.Open()
+	assert.NoError(t, err)
+
+	flags, err := img.GetFlags()
+	assert.NoError(t, err)
+	assert.Equal(t, flags&rbd.RbdFlagObjectMapInvalid, uint64(0))
+
+	calls := 0
+	err = img.RebuildObjectMap(func(offset, total uint64) {
+		calls++
+	})
+	assert.NoError(t, err)
+	assert.True(t, calls > 0)
+
+	err = img.Close()
+	assert.NoError(t, err)
+
+	err = img.Remove()
+	assert.NoError(t, err)
+
+	ioctx.Destroy()
+	conn.DeletePool(poolname)
+	conn.Shutdown()
+}
+
+func TestWriteZeroes(t *testing.T) {
+	conn, _ := rados.NewConn()
+	conn.ReadDefaultConfigFile()
+	conn.Connect()
+
+	poolname := GetUUID()
+	err := conn.MakePool(poolname)
+	assert.NoError(t, err)
+
+	ioctx, err := conn.OpenIOContext(poolname)
+	assert.NoError(t, err)
+
+	name := GetUUID()
+	img, err := rbd.Create(ioctx, name, 1<<22)
+	assert.NoError(t, err)
+
+	err = img.Open()
+	assert.NoError(t, err)
+
+	bytes_in := []byte("input data")
+	_, err = img.WriteAt(bytes_in, 0)
+	assert.NoError(t, err)
+
+	err = img.WriteZeroes(0, uint64(len(bytes_in)), 0)
+	assert.NoError(t, err)
+
+	bytes_out := make([]byte, len(bytes_in))
+	_, err = img.ReadAt(bytes_out, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, bytes_out, make([]byte, len(bytes_in)))
+
+	err = img.Close()
+	assert.NoError(t, err)
+
+	err = img.Remove()
+	assert.NoError(t, err)
+
+	ioctx.Destroy()
+	conn.DeletePool(poolname)
+	conn.Shutdown()
+}
+
+func TestOpenImage(t *testing.T) {
+	conn, _ := rados.NewConn()
+	conn.ReadDefaultConfigFile()
+	conn.Connect()
+
+	poolname := GetUUID()
+	err := conn.MakePool(poolname)
+	assert.NoError(t, err)
+
+	ioctx, err := conn.OpenIOContext(poolname)
+	assert.NoError(t, err)
+
+	name := GetUUID()
+	_, err = rbd.Create(ioctx, name, 1<<22, rbd.RbdFeatureLayering)
+	assert.NoError(t, err)
+
+	img, err := rbd.OpenImage(ioctx, name)
+	assert.NoError(t, err)
+
+	id, err := img.GetId()
+	assert.NoError(t, err)
+
+	err = img.Close()
+	assert.NoError(t, err)
+
+	img, err = rbd.OpenImageReadOnly(ioctx, name)
+	assert.NoError(t, err)
+
+	_, err = img.Write([]byte("input data"))
+	assert.Error(t, err)
+
+	err = img.Close()
+	assert.NoError(t, err)
+
+	img, err = rbd.OpenImageById(ioctx, id)
+	assert.NoError(t, err)
+
+	err = img.Rename(name + "-renamed")
+	assert.NoError(t, err)
+
+	err = img.Close()
+	assert.NoError(t, err)
+
+	img, err = rbd.OpenImageByIdReadOnly(ioctx, id)
+	assert.NoError(t, err)
+
+	imgName, err := img.GetName()
+	assert.NoError(t, err)
+	assert.Equal(t, imgName, name+"-renamed")
+
+	err = img.Close()
+	assert.NoError(t, err)
+
+	err = img.Remove()
+	assert.NoError(t, err)
+
+	ioctx.Destroy()
+	conn.DeletePool(poolname)
+	conn.Shutdown()
+}
+
+func TestOpenImageAtSnapshot(t *testing.T) {
+	conn, _ := rados.NewConn()
+	conn.ReadDefaultConfigFile()
+	conn.Connect()
+
+	poolname := GetUUID()
+	err := conn.MakePool(poolname)
+	assert.NoError(t, err)
+
+	ioctx, err := conn.OpenIOContext(poolname)
+	assert.NoError(t, err)
+
+	name := GetUUID()
+	img, err := rbd.Create(ioctx, name, 1<<22, rbd.RbdFeatureLayering)
+	assert.NoError(t, err)
+
+	err = img.Open()
+	assert.NoError(t, err)
+
+	bytes_in := []byte("before snap")
+	_, err = img.WriteAt(bytes_in, 0)
+	assert.NoError(t, err)
+
+	snapshot, err := img.CreateSnapshot("mysnap")
+	assert.NoError(t, err)
+
+	_, err = img.WriteAt([]byte("after  snap"), 0)
+	assert.NoError(t, err)
+
+	id, err := img.GetId()
+	assert.NoError(t, err)
+
+	for _, open := range []func() (*rbd.Image, error){
+		func() (*rbd.Image, error) {
+			return rbd.OpenImageAtSnapshot(ioctx, name, "mysnap")
+		},
+		func() (*rbd.Image, error) {
+			return rbd.OpenImageByIdAtSnapshot(ioctx, id, "mysnap")
+		},
+	} {
+		snapImg, err := open()
+		assert.NoError(t, err)
+
+		bytes_out := make([]byte, len(bytes_in))
+		_, err = snapImg.ReadAt(bytes_out, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, bytes_in, bytes_out)
+
+		_, err = snapImg.WriteAt(bytes_in, 0)
+		assert.Error(t, err)
+
+		err = snapImg.Close()
+		assert.NoError(t, err)
+	}
+
+	_, err = rbd.OpenImageAtSnapshot(ioctx, name, "")
+	assert.Equal(t, err, rbd.RbdErrorSnapshotNameRequired)
+
+	err = snapshot.Remove()
+	assert.NoError(t, err)
+
+	err = img.Close()
+	assert.NoError(t, err)
+
+	err = img.Remove()
+	assert.NoError(t, err)
+
+	ioctx.Destroy()
+	conn.DeletePool(poolname)
+	conn.Shutdown()
+}
+
+func TestConfigList(t *testing.T) {
+	conn, _ := rados.NewConn()
+	conn.ReadDefaultConfigFile()
+	conn.Connect()
+
+	poolname := GetUUID()
+	err := conn.MakePool(poolname)
+	assert.NoError(t, err)
+
+	ioctx, err := conn.OpenIOContext(poolname)
+	assert.NoError(t, err)
+
+	err = rbd.SetPoolMetadata(ioctx, "conf_rbd_cache", "false")
+	assert.NoError(t, err)
+
+	options, err := rbd.PoolConfigList(ioctx)
+	assert.NoError(t, err)
+	found := false
+	for _, o := range options {
+		if o.Name == "rbd_cache" {
+			found = true
+			assert.Equal(t, o.Value, "false")
+			assert.Equal(t, o.Source, rbd.ConfigSourcePool)
+		}
+	}
+	assert.True(t, found)
+
+	name := GetUUID()
+	img, err := rbd.Create(ioctx, name, 1<<22)
+	assert.NoError(t, err)
+
+	err = img.Open()
+	assert.NoError(t, err)
+
+	options, err = img.ConfigList()
+	assert.NoError(t, err)
+	assert.True(t, len(options) > 0)
+
+	err = img.Close()
+	assert.NoError(t, err)
+
+	err = img.Remove()
+	assert.NoError(t, err)
+
+	ioctx.Destroy()
+	conn.DeletePool(poolname)
+	conn.Shutdown()
+}
+
+func TestJournalInfo(t *testing.T) {
+	conn, _ := rados.NewConn()
+	conn.ReadDefaultConfigFile()
+	conn.Connect()
+
+	poolname := GetUUID()
+	err := conn.MakePool(poolname)
+	assert.NoError(t, err)
+
+	ioctx, err := conn.OpenIOContext(poolname)
+	assert.NoError(t, err)
+
+	name := GetUUID()
+	img, err := rbd.Create(ioctx, name, 1<<22, rbd.RbdFeatureLayering|
+		rbd.RbdFeatureExclusiveLock|rbd.RbdFeatureJournaling)
+	assert.NoError(t, err)
+
+	err = img.Open()
+	assert.NoError(t, err)
+
+	_, err = img.Write([]byte("journaled"))
+	assert.NoError(t, err)
+
+	err = img.Flush()
+	assert.NoError(t, err)
+
+	info, err := img.GetJournalInfo()
+	assert.NoError(t, err)
+	assert.True(t, info.Order > 0)
+	assert.True(t, info.SplayWidth > 0)
+
+	master, ok := info.Client("")
+	assert.True(t, ok)
+	assert.Equal(t, master.State, rbd.JournalClientStateConnected)
+
+	behind, ok := info.EntriesBehind("")
+	assert.True(t, ok)
+	assert.Equal(t, behind, uint64(0))
+
+	_, ok = info.EntriesBehind("no-such-client")
+	assert.False(t, ok)
+
+	err = img.Close()
+	assert.NoError(t, err)
+
+	err = img.Remove()
+	assert.NoError(t, err)
+
+	ioctx.Destroy()
+	conn.DeletePool(poolname)
+	conn.Shutdown()
+}
+
+func TestDiskUsage(t *testing.T) {
+	conn, _ := rados.NewConn()
+	conn.ReadDefaultConfigFile()
+	conn.Connect()
+
+	poolname := GetUUID()
+	err := conn.MakePool(poolname)
+	assert.NoError(t, err)
+
+	ioctx, err := conn.OpenIOContext(poolname)
+	assert.NoError(t, err)
+
+	usage, err := rbd.GetPoolDiskUsage(ioctx)
+	assert.NoError(t, err)
+	assert.Len(t, usage, 0)
+
+	name := GetUUID()
+	img, err := rbd.Create(ioctx, name, 1<<24, rbd.RbdFeatureLayering|
+		rbd.RbdFeatureExclusiveLock|rbd.RbdFeatureObjectMap|
+		rbd.RbdFeatureFastDiff)
+	assert.NoError(t, err)
+
+	err = img.Open()
+	assert.NoError(t, err)
+
+	_, err = img.WriteAt(make([]byte, 4096), 0)
+	assert.NoError(t, err)
+
+	_, err = img.CreateSnapshot("snap1")
+	assert.NoError(t, err)
+
+	_, err = img.WriteAt(make([]byte, 4096), 1<<23)
+	assert.NoError(t, err)
+
+	usage, err = img.DiskUsage()
+	assert.NoError(t, err)
+	assert.Len(t, usage, 2)
+	assert.Equal(t, usage[0].Snapshot, "snap1")
+	assert.Equal(t, usage[0].ProvisionedBytes, uint64(1<<24))
+	assert.True(t, usage[0].UsedBytes > 0)
+	assert.Equal(t, usage[1].Snapshot, "")
+	assert.True(t, usage[1].UsedBytes > 0)
+
+	extents := 0
+	err = img.DiffIterate("snap1", 0, 1<<24, false, false,
+		func(offset, length uint64, exists bool) {
+			assert.Equal(t, offset, uint64(1<<23))
+			extents++
+		})
+	assert.NoError(t, err)
+	assert.Equal(t, extents, 1)
+
+	usage, err = rbd.GetPoolDiskUsage(ioctx)
+	assert.NoError(t, err)
+	assert.Len(t, usage, 2)
+
+	err = img.GetSnapshot("snap1").Remove()
+	assert.NoError(t, err)
+
+	err = img.Close()
+	assert.NoError(t, err)
+
+	err = img.Remove()
+	assert.NoError(t, err)
+
+	ioctx.Destroy()
+	conn.DeletePool(poolname)
+	conn.Shutdown()
+}
+
+func TestGetImageList(t *testing.T) {
+	conn, _ := rados.NewConn()
+	conn.ReadDefaultConfigFile()
+	conn.Connect()
+
+	poolname := GetUUID()
+	err := conn.MakePool(poolname)
+	assert.NoError(t, err)
+
+	ioctx, err := conn.OpenIOContext(poolname)
+	assert.NoError(t, err)
+
+	images, err := rbd.GetImageList(ioctx)
+	assert.NoError(t, err)
+	assert.Len(t, images, 0)
+
+	createdList := []string{}
+	for i := 0; i < 40; i++ {
+		name := GetUUID()
+		_, err := rbd.Create(ioctx, name, 1<<22, rbd.RbdFeatureLayering)
+		assert.NoError(t, err)
+		createdList = append(createdList, name)
+	}
+
+	images, err = rbd.GetImageList(ioctx)
+	assert.NoError(t, err)
+	assert.Len(t, images, len(createdList))
+
+	imageNames := []string{}
+	for _, spec := range images {
+		assert.NotEqual(t, spec.Id, "")
+		imageNames = append(imageNames, spec.Name)
+
+		img, err := rbd.OpenImageById(ioctx, spec.Id)
+		assert.NoError(t, err)
+		name, err := img.GetName()
+		assert.NoError(t, err)
+		assert.Equal(t, name, spec.Name)
+		err = img.Close()
+		assert.NoError(t, err)
+	}
+
+	sort.Strings(createdList)
+	sort.Strings(imageNames)
+	assert.Equal(t, createdList, imageNames)
+
+	for _, name := range createdList {
+		img := rbd.GetImage(ioctx, name)
+		err := img.Remove()
+		assert.NoError(t, err)
+	}
+
+	ioctx.Destroy()
+	conn.DeletePool(poolname)
+	conn.Shutdown()
+}
+
+func TestListLinkedChildren(t *testing.T) {
+	conn, _ := rados.NewConn()
+	conn.ReadDefaultConfigFile()
+	conn.Connect()
+
+	poolname := GetUUID()
+	err := conn.MakePool(poolname)
+	assert.NoError(t, err)
+
+	ioctx, err := conn.OpenIOContext(poolname)
+	assert.NoError(t, err)
+
+	name := GetUUID()
+	img, err := rbd.Create(ioctx, name, 1<<22, rbd.RbdFeatureLayering)
+	assert.NoError(t, err)
+
+	err = img.Open()
+	assert.NoError(t, err)
+
+	snapshot, err := img.CreateSnapshot("mysnap")
+	assert.NoError(t, err)
+
+	err = snapshot.Protect()
+	assert.NoError(t, err)
+
+	childName := GetUUID()
+	child, err := img.Clone("mysnap", ioctx, childName, rbd.RbdFeatureLayering)
+	assert.NoError(t, err)
+
+	err = child.Open()
+	assert.NoError(t, err)
+
+	childSnapshot, err := child.CreateSnapshot("childsnap")
+	assert.NoError(t, err)
+
+	err = childSnapshot.Protect()
+	assert.NoError(t, err)
+
+	grandchildName := GetUUID()
+	grandchild, err := child.Clone("childsnap", ioctx, grandchildName,
+		rbd.RbdFeatureLayering)
+	assert.NoError(t, err)
+
+	snapImg, err := rbd.OpenImageAtSnapshot(ioctx, name, "mysnap")
+	assert.NoError(t, err)
+
+	children, err := snapImg.ListLinkedChildren()
+	assert.NoError(t, err)
+	assert.Len(t, children, 1)
+	assert.Equal(t, children[0].PoolName, poolname)
+	assert.Equal(t, children[0].ImageName, childName)
+	assert.False(t, children[0].Trash)
+
+	descendants, err := snapImg.ListDescendants()
+	assert.NoError(t, err)
+	assert.Len(t, descendants, 2)
+
+	err = snapImg.Close()
+	assert.NoError(t, err)
+
+	err = grandchild.Remove()
+	assert.NoError(t, err)
+
+	err = childSnapshot.Unprotect()
+	assert.NoError(t, err)
+
+	err = childSnapshot.Remove()
+	assert.NoError(t, err)
+
+	err = child.Close()
+	assert.NoError(t, err)
+
+	err = child.Remove()
+	assert.NoError(t, err)
+
+	err = snapshot.Unprotect()
+	assert.NoError(t, err)
+
+	err = snapshot.Remove()
+	assert.NoError(t, err)
+
+	err = img.Close()
+	assert.NoError(t, err)
+
+	err = img.Remove()
+	assert.NoError(t, err)
+
+	ioctx.Destroy()
+	conn.DeletePool(poolname)
+	conn.Shutdown()
+}
+
+func TestSnapshotLimit(t *testing.T) {
+	conn, _ := rados.NewConn()
+	conn.ReadDefaultConfigFile()
+	conn.Connect()
+
+	poolname := GetUUID()
+	err := conn.MakePool(poolname)
+	assert.NoError(t, err)
+
+	ioctx, err := conn.OpenIOContext(poolname)
+	assert.NoError(t, err)
+
+	name := GetUUID()
+	img, err := rbd.Create(ioctx, name, 1<<22, rbd.RbdFeatureLayering)
+	assert.NoError(t, err)
+
+	err = img.Open()
+	assert.NoError(t, err)
+
+	limit, err := img.GetSnapshotLimit()
+	assert.NoError(t, err)
+	assert.Equal(t, limit, rbd.RbdNoSnapshotLimit)
+
+	err = img.SetSnapshotLimit(1)
+	assert.NoError(t, err)
+
+	limit, err = img.GetSnapshotLimit()
+	assert.NoError(t, err)
+	assert.Equal(t, limit, uint64(1))
+
+	snapshot, err := img.CreateSnapshot("snap1")
+	assert.NoError(t, err)
+
+	_, err = img.CreateSnapshot("snap2")
+	assert.Error(t, err)
+
+	err = img.SetSnapshotLimit(rbd.RbdNoSnapshotLimit)
+	assert.NoError(t, err)
+
+	err = snapshot.Remove()
+	assert.NoError(t, err)
+
+	err = img.Close()
+	assert.NoError(t, err)
+
+	err = img.Remove()
+	assert.NoError(t, err)
+
+	ioctx.Destroy()
+	conn.DeletePool(poolname)
+	conn.Shutdown()
+}
+
+func TestSnapshotNamespaces(t *testing.T) {
+	conn, _ := rados.NewConn()
+	conn.ReadDefaultConfigFile()
+	// clone v2 allows removing snapshots with clones, which moves them to
+	// the trash namespace
+	conn.SetConfigOption("rbd_default_clone_format", "2")
+	conn.Connect()
+
+	poolname := GetUUID()
+	err := conn.MakePool(poolname)
+	assert.NoError(t, err)
+
+	ioctx, err := conn.OpenIOContext(poolname)
+	assert.NoError(t, err)
+
+	name := GetUUID()
+	img, err := rbd.Create(ioctx, name, 1<<22, rbd.RbdFeatureLayering)
+	assert.NoError(t, err)
+
+	err = img.Open()
+	assert.NoError(t, err)
+
+	before := time.Now().Add(-time.Minute)
+	snapshot, err := img.CreateSnapshot("mysnap")
+	assert.NoError(t, err)
+
+	err = snapshot.Rename("renamed")
+	assert.NoError(t, err)
+
+	snaps, err := img.GetSnapshotNames()
+	assert.NoError(t, err)
+	assert.Len(t, snaps, 1)
+	assert.Equal(t, snaps[0].Name, "renamed")
+	snapId := snaps[0].Id
+
+	nsType, err := img.GetSnapNamespaceType(snapId)
+	assert.NoError(t, err)
+	assert.Equal(t, nsType, rbd.SnapNamespaceTypeUser)
+
+	timestamp, err := img.GetSnapTimestamp(snapId)
+	assert.NoError(t, err)
+	assert.True(t, timestamp.After(before))
+
+	childName := GetUUID()
+	child, err := img.Clone("renamed", ioctx, childName, rbd.RbdFeatureLayering)
+	assert.NoError(t, err)
+
+	err = snapshot.Remove()
+	assert.NoError(t, err)
+
+	nsType, err = img.GetSnapNamespaceType(snapId)
+	assert.NoError(t, err)
+	assert.Equal(t, nsType, rbd.SnapNamespaceTypeTrash)
+
+	originalName, err := img.GetSnapTrashNamespace(snapId)
+	assert.NoError(t, err)
+	assert.Equal(t, originalName, "renamed")
+
+	err = child.Remove()
+	assert.NoError(t, err)
+
+	err = img.Close()
+	assert.NoError(t, err)
+
+	err = img.Remove()
+	assert.NoError(t, err)
+
+	ioctx.Destroy()
+	conn.DeletePool(poolname)
+	conn.Shutdown()
+}
+
+func TestImageTimestamps(t *testing.T) {
+	conn, _ := rados.NewConn()
+	conn.ReadDefaultConfigFile()
+	conn.SetConfigOption("rbd_atime_update_interval", "0")
+	conn.SetConfigOption("rbd_mtime_update_interval", "0")
+	conn.Connect()
+
+	poolname := GetUUID()
+	err := conn.MakePool(poolname)
+	assert.NoError(t, err)
+
+	ioctx, err := conn.OpenIOContext(poolname)
+	assert.NoError(t, err)
+
+	before := time.Now().Add(-time.Minute)
+	name := GetUUID()
+	img, err := rbd.Create(ioctx, name, 1<<22, rbd.RbdFeatureLayering)
+	assert.NoError(t, err)
+
+	err = img.Open()
+	assert.NoError(t, err)
+
+	created, err := img.GetCreateTimestamp()
+	assert.NoError(t, err)
+	assert.True(t, created.After(before))
+
+	_, err = img.WriteAt([]byte("data"), 0)
+	assert.NoError(t, err)
+
+	modified, err := img.GetModifyTimestamp()
+	assert.NoError(t, err)
+	assert.False(t, modified.Before(created))
+
+	_, err = img.ReadAt(make([]byte, 4), 0)
+	assert.NoError(t, err)
+
+	accessed, err := img.GetAccessTimestamp()
+	assert.NoError(t, err)
+	assert.False(t, accessed.Before(created))
+
+	err = img.Close()
+	assert.NoError(t, err)
+
+	_, err = img.GetCreateTimestamp()
+	assert.Equal(t, err, rbd.RbdErrorImageNotOpen)
+
+	err = img.Remove()
+	assert.NoError(t, err)
+
+	ioctx.Destroy()
+	conn.DeletePool(poolname)
+	conn.Shutdown()
+}
+
+func TestProgress(t *testing.T) {
+	conn, _ := rados.NewConn()
+	conn.ReadDefaultConfigFile()
+	conn.Connect()
+
+	poolname := GetUUID()
+	err := conn.MakePool(poolname)
+	assert.NoError(t, err)
+
+	ioctx, err := conn.OpenIOContext(poolname)
+	assert.NoError(t, err)
+
+	name := GetUUID()
+	img, err := rbd.Create(ioctx, name, 1<<24, rbd.RbdFeatureLayering)
+	assert.NoError(t, err)
+
+	err = img.Open()
+	assert.NoError(t, err)
+
+	_, err = img.WriteAt([]byte("progress"), 1<<23)
+	assert.NoError(t, err)
+
+	calls := 0
+	progressFn := func(offset, total uint64) {
+		assert.True(t, offset <= total)
+		calls++
+	}
+
+	copyName := GetUUID()
+	err = img.CopyWithProgress(ioctx, copyName, progressFn)
+	assert.NoError(t, err)
+	assert.True(t, calls > 0)
+
+	calls = 0
+	deepCopyName := GetUUID()
+	err = img.DeepCopyWithProgress(ioctx, deepCopyName, progressFn)
+	assert.NoError(t, err)
+	assert.True(t, calls > 0)
+
+	err = img.Close()
+	assert.NoError(t, err)
+
+	calls = 0
+	err = rbd.GetImage(ioctx, copyName).RemoveWithProgress(progressFn)
+	assert.NoError(t, err)
+	assert.True(t, calls > 0)
+
+	destName := GetUUID()
+	err = rbd.MigrationPrepare(ioctx, deepCopyName, ioctx, destName, nil)
+	assert.NoError(t, err)
+
+	calls = 0
+	err = rbd.MigrationExecuteWithProgress(ioctx, destName, progressFn)
+	assert.NoError(t, err)
+	assert.True(t, calls > 0)
+
+	err = rbd.MigrationCommitWithProgress(ioctx, destName, progressFn)
+	assert.NoError(t, err)
+
+	dest, err := rbd.OpenImage(ioctx, destName)
+	assert.NoError(t, err)
+	id, err := dest.GetId()
+	assert.NoError(t, err)
+	err = dest.Close()
+	assert.NoError(t, err)
+	err = dest.Trash(0)
+	assert.NoError(t, err)
+
+	calls = 0
+	err = rbd.TrashRemoveWithProgress(ioctx, id, true, progressFn)
+	assert.NoError(t, err)
+	assert.True(t, calls > 0)
+
+	err = img.Remove()
+	assert.NoError(t, err)
+
+	ioctx.Destroy()
+	conn.DeletePool(poolname)
+	conn.Shutdown()
+}
+
+func TestImageOptions(t *testing.T) {
+	conn, _ := rados.NewConn()
+	conn.ReadDefaultConfigFile()
+	conn.Connect()
+
+	poolname := GetUUID()
+	err := conn.MakePool(poolname)
+	assert.NoError(t, err)
+
+	ioctx, err := conn.OpenIOContext(poolname)
+	assert.NoError(t, err)
+
+	opts := rbd.NewImageOptions()
+	defer opts.Destroy()
+	assert.True(t, opts.IsEmpty())
+
+	err = opts.SetUint64(rbd.ImageOptionFeatures,
+		rbd.RbdFeatureLayering|rbd.RbdFeatureStripingV2)
+	assert.NoError(t, err)
+	err = opts.SetUint64(rbd.ImageOptionOrder, 22)
+	assert.NoError(t, err)
+	err = opts.SetUint64(rbd.ImageOptionStripeUnit, 1<<16)
+	assert.NoError(t, err)
+	err = opts.SetUint64(rbd.ImageOptionStripeCount, 8)
+	assert.NoError(t, err)
+	assert.False(t, opts.IsEmpty())
+
+	order, err := opts.GetUint64(rbd.ImageOptionOrder)
+	assert.NoError(t, err)
+	assert.Equal(t, order, uint64(22))
+
+	err = opts.SetString(rbd.ImageOptionDataPool, poolname)
+	assert.NoError(t, err)
+	dataPool, err := opts.GetString(rbd.ImageOptionDataPool)
+	assert.NoError(t, err)
+	assert.Equal(t, dataPool, poolname)
+
+	isSet, err := opts.IsSet(rbd.ImageOptionDataPool)
+	assert.NoError(t, err)
+	assert.True(t, isSet)
+
+	err = opts.Unset(rbd.ImageOptionDataPool)
+	assert.NoError(t, err)
+	isSet, err = opts.IsSet(rbd.ImageOptionDataPool)
+	assert.NoError(t, err)
+	assert.False(t, isSet)
+
+	name := GetUUID()
+	img, err := rbd.CreateWithOptions(ioctx, name, 1<<24, opts)
+	assert.NoError(t, err)
+
+	err = img.Open()
+	assert.NoError(t, err)
+
+	stripeUnit, err := img.GetStripeUnit()
+	assert.NoError(t, err)
+	assert.Equal(t, stripeUnit, uint64(1<<16))
+
+	stripeCount, err := img.GetStripeCount()
+	assert.NoError(t, err)
+	assert.Equal(t, stripeCount, uint64(8))
+
+	copyOpts := rbd.NewImageOptions()
+	defer copyOpts.Destroy()
+	err = copyOpts.SetUint64(rbd.ImageOptionOrder, 23)
+	assert.NoError(t, err)
+
+	copyName := GetUUID()
+	err = img.CopyWithOptions(ioctx, copyName, copyOpts)
+	assert.NoError(t, err)
+
+	snapshot, err := img.CreateSnapshot("mysnap")
+	assert.NoError(t, err)
+	err = snapshot.Protect()
+	assert.NoError(t, err)
+
+	cloneName := GetUUID()
+	clone, err := img.CloneWithOptions("mysnap", ioctx, cloneName, copyOpts)
+	assert.NoError(t, err)
+
+	for _, other := range []*rbd.Image{rbd.GetImage(ioctx, copyName), clone} {
+		err = other.Open()
+		assert.NoError(t, err)
+		info, err := other.Stat()
+		assert.NoError(t, err)
+		assert.Equal(t, info.Order, 23)
+		err = other.Close()
+		assert.NoError(t, err)
+		err = other.Remove()
+		assert.NoError(t, err)
+	}
+
+	err = snapshot.Unprotect()
+	assert.NoError(t, err)
+	err = snapshot.Remove()
+	assert.NoError(t, err)
+
+	err = img.Close()
+	assert.NoError(t, err)
+
+	destName := GetUUID()
+	err = rbd.MigrationPrepare(ioctx, name, ioctx, destName, copyOpts)
+	assert.NoError(t, err)
+	err = rbd.MigrationExecute(ioctx, destName)
+	assert.NoError(t, err)
+	err = rbd.MigrationCommit(ioctx, destName)
+	assert.NoError(t, err)
+
+	dest, err := rbd.OpenImage(ioctx, destName)
+	assert.NoError(t, err)
+	info, err := dest.Stat()
+	assert.NoError(t, err)
+	assert.Equal(t, info.Order, 23)
+	err = dest.Close()
+	assert.NoError(t, err)
+
+	err = dest.Remove()
+	assert.NoError(t, err)
+
+	ioctx.Destroy()
+	conn.DeletePool(poolname)
+	conn.Shutdown()
+}
+
+func TestPoolStats(t *testing.T) {
+	conn, _ := rados.NewConn()
+	conn.ReadDefaultConfigFile()
+	conn.Connect()
+
+	poolname := GetUUID()
+	err := conn.MakePool(poolname)
+	assert.NoError(t, err)
+
+	ioctx, err := conn.OpenIOContext(poolname)
+	assert.NoError(t, err)
+
+	stats, err := rbd.GetPoolStats(ioctx)
+	assert.NoError(t, err)
+	assert.Equal(t, *stats, rbd.PoolStats{})
+
+	name := GetUUID()
+	img, err := rbd.Create(ioctx, name, 1<<22, rbd.RbdFeatureLayering)
+	assert.NoError(t, err)
+
+	err = img.Open()
+	assert.NoError(t, err)
+	snapshot, err := img.CreateSnapshot("mysnap")
+	assert.NoError(t, err)
+
+	trashName := GetUUID()
+	_, err = rbd.Create(ioctx, trashName, 1<<23, rbd.RbdFeatureLayering)
+	assert.NoError(t, err)
+	err = rbd.GetImage(ioctx, trashName).Trash(0)
+	assert.NoError(t, err)
+
+	stats, err = rbd.GetPoolStats(ioctx)
+	assert.NoError(t, err)
+	assert.Equal(t, stats.Images, uint64(1))
+	assert.Equal(t, stats.ImageProvisionedBytes, uint64(1<<22))
+	assert.Equal(t, stats.ImageMaxProvisionedBytes, uint64(2<<22))
+	assert.Equal(t, stats.ImageSnapshots, uint64(1))
+	assert.Equal(t, stats.TrashImages, uint64(1))
+	assert.Equal(t, stats.TrashProvisionedBytes, uint64(1<<23))
+
+	trash, err := rbd.GetTrashList(ioctx)
+	assert.NoError(t, err)
+	for _, info := range trash {
+		err = rbd.TrashRemove(ioctx, info.Id, true)
+		assert.NoError(t, err)
+	}
+
+	err = snapshot.Remove()
+	assert.NoError(t, err)
+
+	err = img.Close()
+	assert.NoError(t, err)
+
+	err = img.Remove()
+	assert.NoError(t, err)
+
+	ioctx.Destroy()
+	conn.DeletePool(poolname)
+	conn.Shutdown()
+}
+
+func TestSnapshotIds(t *testing.T) {
+	conn, _ := rados.NewConn()
+	conn.ReadDefaultConfigFile()
+	conn.Connect()
+
+	poolname := GetUUID()
+	err := conn.MakePool(poolname)
+	assert.NoError(t, err)
+
+	ioctx, err := conn.OpenIOContext(poolname)
+	assert.NoError(t, err)
+
+	name := GetUUID()
+	img, err := rbd.Create(ioctx, name, 1<<22, rbd.RbdFeatureLayering)
+	assert.NoError(t, err)
+
+	err = img.Open()
+	assert.NoError(t, err)
+
+	bytes_in := []byte("before snap")
+	_, err = img.WriteAt(bytes_in, 0)
+	assert.NoError(t, err)
+
+	snapshot, err := img.CreateSnapshot("mysnap")
+	assert.NoError(t, err)
+
+	_, err = img.WriteAt([]byte("after  snap"), 0)
+	assert.NoError(t, err)
+
+	snapId, err := img.GetSnapId("mysnap")
+	assert.NoError(t, err)
+
+	err = snapshot.Rename("renamed")
+	assert.NoError(t, err)
+
+	snapName, err := img.GetSnapName(snapId)
+	assert.NoError(t, err)
+	assert.Equal(t, snapName, "renamed")
+
+	_, err = img.GetSnapId("mysnap")
+	assert.Equal(t, err, rbd.RbdErrorNotFound)
+
+	err = img.SetSnapById(snapId)
+	assert.NoError(t, err)
+
+	bytes_out := make([]byte, len(bytes_in))
+	_, err = img.ReadAt(bytes_out, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, bytes_in, bytes_out)
+
+	err = img.Close()
+	assert.NoError(t, err)
+
+	err = img.Open()
+	assert.NoError(t, err)
+
+	err = snapshot.Remove()
+	assert.NoError(t, err)
+
+	err = img.Close()
+	assert.NoError(t, err)
+
+	err = img.Remove()
+	assert.NoError(t, err)
+
+	ioctx.Destroy()
+	conn.DeletePool(poolname)
+	conn.Shutdown()
+}
@@ -0,0 +1,199 @@
+package rbd
+
+// #cgo LDFLAGS: -lrbd
+// #include <errno.h>
+// #include <stdlib.h>
+// #include <stdint.h>
+// #include <rados/librados.h>
+// #include <rbd/librbd.h>
+import "C"
+
+import (
+	"time"
+	"unsafe"
+)
+
+// SnapNamespaceType indicates who created a snapshot. Only snapshots in the
+// user namespace are created and managed by users directly.
+type SnapNamespaceType int
+
+const (
+	SnapNamespaceTypeUser   = SnapNamespaceType(C.RBD_SNAP_NAMESPACE_TYPE_USER)
+	SnapNamespaceTypeGroup  = SnapNamespaceType(C.RBD_SNAP_NAMESPACE_TYPE_GROUP)
+	SnapNamespaceTypeTrash  = SnapNamespaceType(C.RBD_SNAP_NAMESPACE_TYPE_TRASH)
+	SnapNamespaceTypeMirror = SnapNamespaceType(C.RBD_SNAP_NAMESPACE_TYPE_MIRROR)
+)
+
+// RbdNoSnapshotLimit is the snapshot limit of an image without a limit.
+const RbdNoSnapshotLimit = uint64(C.UINT64_MAX)
+
+// GetSnapshotLimit returns the maximum number of snapshots the image may
+// have, or RbdNoSnapshotLimit if the number is not limited.
+//
+// int rbd_snap_get_limit(rbd_image_t image, uint64_t *limit);
+func (image *Image) GetSnapshotLimit() (limit uint64, err error) {
+	if image.image == nil {
+		return 0, RbdErrorImageNotOpen
+	}
+
+	ret := C.rbd_snap_get_limit(image.image, (*C.uint64_t)(&limit))
+	if ret < 0 {
+		return 0, RBDError(ret)
+	}
+
+	return limit, nil
+}
+
+// SetSnapshotLimit limits the number of snapshots the image may have.
+// Creating more snapshots fails with -EDQUOT. The limit can not be set below
+// the current number of snapshots. Use RbdNoSnapshotLimit to clear it.
+//
+// int rbd_snap_set_limit(rbd_image_t image, uint64_t limit);
+func (image *Image) SetSnapshotLimit(limit uint64) error {
+	if image.image == nil {
+		return RbdErrorImageNotOpen
+	}
+
+	return GetError(C.rbd_snap_set_limit(image.image, C.uint64_t(limit)))
+}
+
+// GetSnapNamespaceType returns the namespace type of the snapshot with the
+// given id.
+//
+// int rbd_snap_get_namespace_type(rbd_image_t image, uint64_t snap_id, rbd_snap_namespace_type_t *namespace_type);
+func (image *Image) GetSnapNamespaceType(snapId uint64) (SnapNamespaceType, error) {
+	if image.image == nil {
+		return 0, RbdErrorImageNotOpen
+	}
+
+	var c_type C.rbd_snap_namespace_type_t
+	ret := C.rbd_snap_get_namespace_type(image.image, C.uint64_t(snapId),
+		&c_type)
+	if ret < 0 {
+		return 0, GetError(ret)
+	}
+
+	return SnapNamespaceType(c_type), nil
+}
+
+// GetSnapTrashNamespace returns the original name of a snapshot in the trash
+// namespace. Removing a snapshot that still has clones moves it there.
+//
+// int rbd_snap_get_trash_namespace(rbd_image_t image, uint64_t snap_id, char* original_name, size_t max_length);
+func (image *Image) GetSnapTrashNamespace(snapId uint64) (string, error) {
+	if image.image == nil {
+		return "", RbdErrorImageNotOpen
+	}
+
+	buf := make([]byte, 64)
+	for {
+		ret := C.rbd_snap_get_trash_namespace(image.image, C.uint64_t(snapId),
+			(*C.char)(unsafe.Pointer(&buf[0])), C.size_t(len(buf)))
+		if ret == -C.ERANGE {
+			buf = make([]byte, len(buf)*2)
+			continue
+		} else if ret < 0 {
+			return "", GetError(ret)
+		}
+
+		return C.GoString((*C.char)(unsafe.Pointer(&buf[0]))), nil
+	}
+}
+
+// GetSnapTimestamp returns the time the snapshot with the given id was
+// created.
+//
+// int rbd_snap_get_timestamp(rbd_image_t image, uint64_t snap_id, struct timespec *timestamp);
+func (image *Image) GetSnapTimestamp(snapId uint64) (time.Time, error) {
+	if image.image == nil {
+		return time.Time{}, RbdErrorImageNotOpen
+	}
+
+	var c_timestamp C.struct_timespec
+	ret := C.rbd_snap_get_timestamp(image.image, C.uint64_t(snapId),
+		&c_timestamp)
+	if ret < 0 {
+		return time.Time{}, GetError(ret)
+	}
+
+	return time.Unix(int64(c_timestamp.tv_sec), int64(c_timestamp.tv_nsec)), nil
+}
+
+// Rename renames the snapshot to destName.
+//
+// int rbd_snap_rename(rbd_image_t image, const char *snapname, const char* dstsnapsname);
+func (snapshot *Snapshot) Rename(destName string) error {
+	if snapshot.image.image == nil {
+		return RbdErrorImageNotOpen
+	}
+
+	var c_snapname *C.char = C.CString(snapshot.name)
+	defer C.free(unsafe.Pointer(c_snapname))
+	var c_destname *C.char = C.CString(destName)
+	defer C.free(unsafe.Pointer(c_destname))
+
+	ret := C.rbd_snap_rename(snapshot.image.image, c_snapname, c_destname)
+	if ret < 0 {
+		return GetError(ret)
+	}
+
+	snapshot.name = destName
+	return nil
+}
+
+// GetSnapId returns the id of the snapshot with the given name. Unlike the
+// name, the id of a snapshot does not change when the snapshot is renamed.
+//
+// int rbd_snap_get_id(rbd_image_t image, const char *snapname, uint64_t *snap_id);
+func (image *Image) GetSnapId(snapName string) (snapId uint64, err error) {
+	if image.image == nil {
+		return 0, RbdErrorImageNotOpen
+	}
+
+	var c_snapname *C.char = C.CString(snapName)
+	defer C.free(unsafe.Pointer(c_snapname))
+
+	ret := C.rbd_snap_get_id(image.image, c_snapname, (*C.uint64_t)(&snapId))
+	if ret < 0 {
+		return 0, GetError(ret)
+	}
+
+	return snapId, nil
+}
+
+// GetSnapName returns the current name of the snapshot with the given id.
+//
+// int rbd_snap_get_name(rbd_image_t image, uint64_t snap_id, char *snapname, size_t *name_len);
+func (image *Image) GetSnapName(snapId uint64) (string, error) {
+	if image.image == nil {
+		return "", RbdErrorImageNotOpen
+	}
+
+	buf := make([]byte, 64)
+	for {
+		c_len := C.size_t(len(buf))
+		ret := C.rbd_snap_get_name(image.image, C.uint64_t(snapId),
+			(*C.char)(unsafe.Pointer(&buf[0])), &c_len)
+		if ret == -C.ERANGE {
+			buf = make([]byte, c_len)
+			continue
+		} else if ret < 0 {
+			return "", GetError(ret)
+		}
+
+		return C.GoString((*C.char)(unsafe.Pointer(&buf[0]))), nil
+	}
+}
+
+// SetSnapById sets the image to the snapshot with the given id, like
+// Snapshot.Set does by name. Subsequent reads return the data of the
+// snapshot.
+//
+// int rbd_snap_set_by_id(rbd_image_t image, uint64_t snap_id);
+func (image *Image) SetSnapById(snapId uint64) error {
+	if image.image == nil {
+		return RbdErrorImageNotOpen
+	}
+
+	return GetError(C.rbd_snap_set_by_id(image.image, C.uint64_t(snapId)))
+}
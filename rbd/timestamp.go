@@ -0,0 +1,61 @@
+package rbd
+
+// #cgo LDFLAGS: -lrbd
+// #include <errno.h>
+// #include <stdlib.h>
+// #include <time.h>
+// #include <rados/librados.h>
+// #include <rbd/librbd.h>
+import "C"
+
+import (
+	"time"
+)
+
+type timestampFunc func(image C.rbd_image_t, timestamp *C.struct_timespec) C.int
+
+func (image *Image) getTimestamp(timestampFn timestampFunc) (time.Time, error) {
+	if image.image == nil {
+		return time.Time{}, RbdErrorImageNotOpen
+	}
+
+	var c_timestamp C.struct_timespec
+	ret := timestampFn(image.image, &c_timestamp)
+	if ret < 0 {
+		return time.Time{}, RBDError(ret)
+	}
+
+	return time.Unix(int64(c_timestamp.tv_sec), int64(c_timestamp.tv_nsec)), nil
+}
+
+// GetCreateTimestamp returns the time the image was created.
+//
+// int rbd_get_create_timestamp(rbd_image_t image, struct timespec *timestamp);
+func (image *Image) GetCreateTimestamp() (time.Time, error) {
+	return image.getTimestamp(func(image C.rbd_image_t,
+		timestamp *C.struct_timespec) C.int {
+		return C.rbd_get_create_timestamp(image, timestamp)
+	})
+}
+
+// GetAccessTimestamp returns the time the image was last read. The access
+// time is only updated once per rbd_atime_update_interval seconds.
+//
+// int rbd_get_access_timestamp(rbd_image_t image, struct timespec *timestamp);
+func (image *Image) GetAccessTimestamp() (time.Time, error) {
+	return image.getTimestamp(func(image C.rbd_image_t,
+		timestamp *C.struct_timespec) C.int {
+		return C.rbd_get_access_timestamp(image, timestamp)
+	})
+}
+
+// GetModifyTimestamp returns the time the image was last written. The
+// modify time is only updated once per rbd_mtime_update_interval seconds.
+//
+// int rbd_get_modify_timestamp(rbd_image_t image, struct timespec *timestamp);
+func (image *Image) GetModifyTimestamp() (time.Time, error) {
+	return image.getTimestamp(func(image C.rbd_image_t,
+		timestamp *C.struct_timespec) C.int {
+		return C.rbd_get_modify_timestamp(image, timestamp)
+	})
+}
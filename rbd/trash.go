@@ -0,0 +1,137 @@
+package rbd
+
+// #cgo LDFLAGS: -lrbd
+// #include <errno.h>
+// #include <stdlib.h>
+// #include <stdbool.h>
+// #include <rados/librados.h>
+// #include <rbd/librbd.h>
+import "C"
+
+import (
+	"github.com/noahdesu/go-ceph/rados"
+	"time"
+	"unsafe"
+)
+
+// TrashImageSource indicates why an image was moved to the trash.
+type TrashImageSource int
+
+const (
+	TrashImageSourceUser       = TrashImageSource(C.RBD_TRASH_IMAGE_SOURCE_USER)
+	TrashImageSourceMirroring  = TrashImageSource(C.RBD_TRASH_IMAGE_SOURCE_MIRRORING)
+	TrashImageSourceMigration  = TrashImageSource(C.RBD_TRASH_IMAGE_SOURCE_MIGRATION)
+	TrashImageSourceRemoving   = TrashImageSource(C.RBD_TRASH_IMAGE_SOURCE_REMOVING)
+	TrashImageSourceUserParent = TrashImageSource(C.RBD_TRASH_IMAGE_SOURCE_USER_PARENT)
+)
+
+// TrashInfo describes an image that has been moved to the trash.
+type TrashInfo struct {
+	Id               string
+	Name             string
+	Source           TrashImageSource
+	DeletionTime     time.Time
+	DefermentEndTime time.Time
+}
+
+func newTrashInfo(c_info *C.rbd_trash_image_info_t) TrashInfo {
+	return TrashInfo{
+		Id:               C.GoString(c_info.id),
+		Name:             C.GoString(c_info.name),
+		Source:           TrashImageSource(c_info.source),
+		DeletionTime:     time.Unix(int64(c_info.deletion_time), 0),
+		DefermentEndTime: time.Unix(int64(c_info.deferment_end_time), 0),
+	}
+}
+
+// Trash moves the image to the trash. The image can not be removed from the
+// trash until delay has expired, unless the removal is forced.
+//
+// int rbd_trash_move(rados_ioctx_t io, const char *name, uint64_t delay);
+func (image *Image) Trash(delay time.Duration) error {
+	var c_name *C.char = C.CString(image.name)
+	defer C.free(unsafe.Pointer(c_name))
+
+	return GetError(C.rbd_trash_move(C.rados_ioctx_t(image.ioctx.Pointer()),
+		c_name, C.uint64_t(delay.Seconds())))
+}
+
+// GetTrashInfo returns information about the trashed image with the given id.
+//
+// int rbd_trash_get(rados_ioctx_t io, const char *id, rbd_trash_image_info_t *info);
+// void rbd_trash_get_cleanup(rbd_trash_image_info_t *info);
+func GetTrashInfo(ioctx *rados.IOContext, id string) (*TrashInfo, error) {
+	var c_id *C.char = C.CString(id)
+	defer C.free(unsafe.Pointer(c_id))
+
+	var c_info C.rbd_trash_image_info_t
+	ret := C.rbd_trash_get(C.rados_ioctx_t(ioctx.Pointer()), c_id, &c_info)
+	if ret < 0 {
+		return nil, GetError(ret)
+	}
+	defer C.rbd_trash_get_cleanup(&c_info)
+
+	info := newTrashInfo(&c_info)
+	return &info, nil
+}
+
+// GetTrashList returns the images currently in the trash of the pool.
+//
+// int rbd_trash_list(rados_ioctx_t io, rbd_trash_image_info_t *trash_entries, size_t *num_entries);
+// void rbd_trash_list_cleanup(rbd_trash_image_info_t *trash_entries, size_t num_entries);
+func GetTrashList(ioctx *rados.IOContext) ([]TrashInfo, error) {
+	var c_num_entries C.size_t = 32
+	for {
+		c_entries := make([]C.rbd_trash_image_info_t, c_num_entries)
+		ret := C.rbd_trash_list(C.rados_ioctx_t(ioctx.Pointer()),
+			&c_entries[0], &c_num_entries)
+		if ret == -C.ERANGE {
+			continue
+		} else if ret < 0 {
+			return nil, RBDError(ret)
+		}
+
+		entries := make([]TrashInfo, c_num_entries)
+		for i := range entries {
+			entries[i] = newTrashInfo(&c_entries[i])
+		}
+		C.rbd_trash_list_cleanup(&c_entries[0], c_num_entries)
+		return entries, nil
+	}
+}
+
+// TrashRemove permanently deletes the trashed image with the given id. The
+// deferment delay of the image is ignored if force is true.
+//
+// int rbd_trash_remove(rados_ioctx_t io, const char *id, bool force);
+func TrashRemove(ioctx *rados.IOContext, id string, force bool) error {
+	var c_id *C.char = C.CString(id)
+	defer C.free(unsafe.Pointer(c_id))
+
+	return GetError(C.rbd_trash_remove(C.rados_ioctx_t(ioctx.Pointer()),
+		c_id, C.bool(force)))
+}
+
+// TrashRestore moves the trashed image with the given id back into the pool
+// under name. If name is empty the original name of the image is used.
+//
+// int rbd_trash_restore(rados_ioctx_t io, const char *id, const char *name);
+func TrashRestore(ioctx *rados.IOContext, id, name string) error {
+	var c_id *C.char = C.CString(id)
+	var c_name *C.char = C.CString(name)
+	defer C.free(unsafe.Pointer(c_id))
+	defer C.free(unsafe.Pointer(c_name))
+
+	return GetError(C.rbd_trash_restore(C.rados_ioctx_t(ioctx.Pointer()),
+		c_id, c_name))
+}
+
+// TrashPurge removes the images in the trash whose deferment delay ended
+// before expire. If threshold is between 0 and 1, images are only removed
+// while the pool usage ratio is above it; pass -1 to disable the check.
+//
+// int rbd_trash_purge(rados_ioctx_t io, time_t expire_ts, float threshold);
+func TrashPurge(ioctx *rados.IOContext, expire time.Time, threshold float32) error {
+	return GetError(C.rbd_trash_purge(C.rados_ioctx_t(ioctx.Pointer()),
+		C.time_t(expire.Unix()), C.float(threshold)))
+}
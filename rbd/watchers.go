@@ -0,0 +1,130 @@
+package rbd
+
+/*
+#cgo LDFLAGS: -lrbd
+#include <errno.h>
+#include <stdint.h>
+#include <stdlib.h>
+#include <rados/librados.h>
+#include <rbd/librbd.h>
+
+extern void imageUpdateCallback(uintptr_t arg);
+
+static inline int wrap_rbd_update_watch(rbd_image_t image, uint64_t *handle,
+		uintptr_t arg) {
+	return rbd_update_watch(image, handle,
+		(rbd_update_callback_t)imageUpdateCallback, (void *)arg);
+}
+*/
+import "C"
+
+import (
+	"github.com/noahdesu/go-ceph/internal/callbacks"
+)
+
+// UpdateWatchFunc is the type of the function called when the header of a
+// watched image changes.
+type UpdateWatchFunc func()
+
+// Watch is a registration for header update notifications of an image.
+type Watch struct {
+	image  *Image
+	handle C.uint64_t
+	index  uintptr
+}
+
+var updateWatchCallbacks = callbacks.New()
+
+// ImageWatcher describes a client that has the image open.
+type ImageWatcher struct {
+	Addr   string
+	Id     int64
+	Cookie uint64
+}
+
+// ListWatchers returns the clients that are watching the image header,
+// which is every client that has the image open read-write.
+//
+// int rbd_watchers_list(rbd_image_t image, rbd_image_watcher_t *watchers, size_t *max_watchers);
+// void rbd_watchers_list_cleanup(rbd_image_watcher_t *watchers, size_t num_watchers);
+func (image *Image) ListWatchers() ([]ImageWatcher, error) {
+	if image.image == nil {
+		return nil, RbdErrorImageNotOpen
+	}
+
+	var c_max_watchers C.size_t = 8
+	for {
+		c_watchers := make([]C.rbd_image_watcher_t, c_max_watchers)
+		ret := C.rbd_watchers_list(image.image, &c_watchers[0],
+			&c_max_watchers)
+		if ret == -C.ERANGE {
+			continue
+		} else if ret < 0 {
+			return nil, RBDError(ret)
+		}
+
+		watchers := make([]ImageWatcher, c_max_watchers)
+		for i := range watchers {
+			watchers[i] = ImageWatcher{
+				Addr:   C.GoString(c_watchers[i].addr),
+				Id:     int64(c_watchers[i].id),
+				Cookie: uint64(c_watchers[i].cookie),
+			}
+		}
+		C.rbd_watchers_list_cleanup(&c_watchers[0], c_max_watchers)
+		return watchers, nil
+	}
+}
+
+// UpdateWatch registers updateFn to be called whenever the header of the
+// image changes, e.g. after a resize, a snapshot or a change of flags. The
+// image must stay open until the watch is removed with Unwatch. updateFn
+// must not be nil.
+//
+// int rbd_update_watch(rbd_image_t image, uint64_t *handle, rbd_update_callback_t watch_cb, void *arg);
+func (image *Image) UpdateWatch(updateFn UpdateWatchFunc) (*Watch, error) {
+	if image.image == nil {
+		return nil, RbdErrorImageNotOpen
+	}
+	if updateFn == nil {
+		return nil, RbdErrorCallbackRequired
+	}
+
+	watch := &Watch{
+		image: image,
+		index: updateWatchCallbacks.Add(updateFn),
+	}
+	ret := C.wrap_rbd_update_watch(image.image, &watch.handle,
+		C.uintptr_t(watch.index))
+	if ret < 0 {
+		updateWatchCallbacks.Remove(watch.index)
+		return nil, RBDError(ret)
+	}
+
+	return watch, nil
+}
+
+// Unwatch removes the update watch. The callback is not called anymore once
+// Unwatch returns.
+//
+// int rbd_update_unwatch(rbd_image_t image, uint64_t handle);
+func (watch *Watch) Unwatch() error {
+	if watch.image.image == nil {
+		return RbdErrorImageNotOpen
+	}
+
+	ret := C.rbd_update_unwatch(watch.image.image, watch.handle)
+	if ret < 0 {
+		return RBDError(ret)
+	}
+	updateWatchCallbacks.Remove(watch.index)
+	return nil
+}
+
+//export imageUpdateCallback
+func imageUpdateCallback(index C.uintptr_t) {
+	v := updateWatchCallbacks.Lookup(uintptr(index))
+	if updateFn, ok := v.(UpdateWatchFunc); ok {
+		updateFn()
+	}
+}
@@ -0,0 +1,207 @@
+// Package admin manages the users, buckets and other resources of the RADOS
+// gateway through its Admin Ops REST API. Unlike the other admin packages it
+// needs no cluster connection, only the S3 credentials of an RGW user with
+// the admin caps for the resources it manages.
+package admin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrInvalidEndpoint is returned by New if the endpoint is not an http or
+// https URL.
+var ErrInvalidEndpoint = errors.New("rgw admin: invalid endpoint")
+
+// ErrNoCredentials is returned by New if the access or secret key is empty.
+var ErrNoCredentials = errors.New("rgw admin: missing credentials")
+
+// HTTPClient sends the requests of an API, like *http.Client.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// API is a client of the Admin Ops API of an RGW endpoint.
+type API struct {
+	endpoint  *url.URL
+	accessKey string
+	secretKey string
+	client    HTTPClient
+}
+
+// New returns an API that sends requests to the RGW at endpoint, like
+// "http://rgw.example.com:8080", through client, signed with the S3 keys of
+// an admin user. A nil client uses http.DefaultClient.
+func New(endpoint, accessKey, secretKey string, client HTTPClient) (*API, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, ErrInvalidEndpoint
+	}
+	if accessKey == "" || secretKey == "" {
+		return nil, ErrNoCredentials
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	return &API{
+		endpoint:  u,
+		accessKey: accessKey,
+		secretKey: secretKey,
+		client:    client,
+	}, nil
+}
+
+// Error is the error of a request that the RGW rejected. Code is the S3
+// style reason, like "NoSuchUser" or "AccessDenied".
+type Error struct {
+	StatusCode int
+	Code       string `json:"Code"`
+	RequestID  string `json:"RequestId"`
+	HostID     string `json:"HostId"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("rgw admin: %d %s", e.StatusCode, e.Code)
+}
+
+// IsCode reports whether err is an *Error with the given Code.
+func IsCode(err error, code string) bool {
+	var e *Error
+	return errors.As(err, &e) && e.Code == code
+}
+
+// request is an Admin Ops request. resource is the path below /admin, like
+// "user", and params its query. Requests of the S3 and SNS APIs set path,
+// like "/bucket/object", instead of resource. body is sent with the
+// contentType.
+type request struct {
+	method      string
+	resource    string
+	path        string
+	params      url.Values
+	body        []byte
+	contentType string
+}
+
+// send sends the request and returns the headers and body of the reply.
+func (api *API) send(ctx context.Context, r request) (http.Header, []byte, error) {
+	path := r.path
+	if path == "" {
+		path = "/admin/" + r.resource
+	}
+	u := *api.endpoint
+	u.Path += path
+	u.RawPath = escapePath(u.Path)
+	u.RawQuery = encodeQuery(r.params)
+	req, err := http.NewRequestWithContext(ctx, r.method, u.String(),
+		bytes.NewReader(r.body))
+	if err != nil {
+		return nil, nil, err
+	}
+	if r.contentType != "" {
+		req.Header.Set("Content-Type", r.contentType)
+	}
+	sign(req, r.body, api.accessKey, api.secretKey, signingRegion, time.Now())
+
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	reply, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, nil, newError(resp.StatusCode, reply)
+	}
+	return resp.Header, reply, nil
+}
+
+// newError returns the Error of a reply with the status code. The Admin Ops
+// API explains errors in JSON, the S3 and SNS APIs in XML.
+func newError(status int, reply []byte) *Error {
+	e := &Error{StatusCode: status}
+	if json.Unmarshal(reply, e) == nil && e.Code != "" {
+		return e
+	}
+
+	var x struct {
+		Code      string `xml:"Code"`
+		RequestID string `xml:"RequestId"`
+		HostID    string `xml:"HostId"`
+		Error     struct {
+			Code string `xml:"Code"`
+		} `xml:"Error"`
+	}
+	if xml.Unmarshal(reply, &x) == nil {
+		e.Code, e.RequestID, e.HostID = x.Code, x.RequestID, x.HostID
+		if e.Code == "" {
+			e.Code = x.Error.Code
+		}
+	}
+	if e.Code == "" {
+		e.Code = http.StatusText(status)
+	}
+	return e
+}
+
+// call sends the request and decodes the JSON reply into out, unless out is
+// nil.
+func (api *API) call(ctx context.Context, r request, out interface{}) error {
+	_, reply, err := api.send(ctx, r)
+	if err != nil {
+		return err
+	}
+	if out == nil || len(bytes.TrimSpace(reply)) == 0 {
+		return nil
+	}
+	return json.Unmarshal(reply, out)
+}
+
+// setBool adds the boolean parameter key to params if v is set.
+func setBool(params url.Values, key string, v bool) {
+	if v {
+		params.Set(key, "true")
+	}
+}
+
+// setString adds the parameter key to params if v is not empty.
+func setString(params url.Values, key, v string) {
+	if v != "" {
+		params.Set(key, v)
+	}
+}
+
+// callXML sends the request and decodes the XML reply into out, unless out
+// is nil.
+func (api *API) callXML(ctx context.Context, r request, out interface{}) error {
+	_, reply, err := api.send(ctx, r)
+	if err != nil || out == nil {
+		return err
+	}
+	return xml.Unmarshal(reply, out)
+}
+
+// action sends a request of the SNS or IAM compatible APIs, which take the
+// Action and its parameters as a form, and decodes the XML reply into out,
+// unless out is nil.
+func (api *API) action(ctx context.Context, form url.Values, out interface{}) error {
+	return api.callXML(ctx, request{
+		method:      "POST",
+		path:        "/",
+		body:        []byte(encodeQuery(form)),
+		contentType: "application/x-www-form-urlencoded",
+	}, out)
+}
@@ -0,0 +1,717 @@
+package admin_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/noahdesu/go-ceph/rgw/admin"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRGW records the requests of the tests and replies to them with a
+// canned status and body.
+type fakeRGW struct {
+	server *httptest.Server
+	method string
+	path   string
+	query  url.Values
+	body   string
+	status int
+	reply  string
+	header http.Header
+}
+
+func newFakeRGW(t *testing.T) (*fakeRGW, *admin.API) {
+	f := &fakeRGW{}
+	f.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"),
+			"AWS4-HMAC-SHA256 Credential=access/"))
+		body, _ := io.ReadAll(r.Body)
+		f.method, f.path, f.query, f.body = r.Method, r.URL.Path, r.URL.Query(), string(body)
+		for name, values := range f.header {
+			w.Header()[name] = values
+		}
+		if f.status != 0 {
+			w.WriteHeader(f.status)
+		}
+		io.WriteString(w, f.reply)
+	}))
+	t.Cleanup(f.server.Close)
+
+	api, err := admin.New(f.server.URL, "access", "secret", nil)
+	assert.NoError(t, err)
+	return f, api
+}
+
+func (f *fakeRGW) respond(status int, reply string) {
+	f.status, f.reply = status, reply
+}
+
+func TestNew(t *testing.T) {
+	_, err := admin.New("rgw.example.com", "access", "secret", nil)
+	assert.Equal(t, admin.ErrInvalidEndpoint, err)
+	_, err = admin.New("http://rgw.example.com", "", "secret", nil)
+	assert.Equal(t, admin.ErrNoCredentials, err)
+	_, err = admin.New("http://rgw.example.com/", "access", "secret", nil)
+	assert.NoError(t, err)
+}
+
+func TestErrors(t *testing.T) {
+	f, api := newFakeRGW(t)
+	ctx := context.Background()
+
+	f.respond(404, `{"Code":"NoSuchUser","RequestId":"tx1","HostId":"host"}`)
+	_, err := api.GetUserQuota(ctx, "nobody")
+	assert.True(t, admin.IsCode(err, "NoSuchUser"))
+	if e, ok := err.(*admin.Error); assert.True(t, ok) {
+		assert.Equal(t, 404, e.StatusCode)
+		assert.Equal(t, "tx1", e.RequestID)
+	}
+
+	f.respond(403, "")
+	_, err = api.GetUserQuota(ctx, "nobody")
+	assert.True(t, admin.IsCode(err, "Forbidden"))
+}
+
+func TestQuotas(t *testing.T) {
+	f, api := newFakeRGW(t)
+	ctx := context.Background()
+
+	f.respond(200, `{"enabled":true,"check_on_raw":false,"max_size":1073741824,"max_size_kb":1048576,"max_objects":-1}`)
+	q, err := api.GetUserQuota(ctx, "alice")
+	assert.NoError(t, err)
+	assert.Equal(t, &admin.Quota{Enabled: true, MaxSize: 1 << 30, MaxObjects: admin.Unlimited}, q)
+	assert.Equal(t, "GET", f.method)
+	assert.Equal(t, "/admin/user", f.path)
+	assert.Equal(t, "alice", f.query.Get("uid"))
+	assert.Equal(t, "user", f.query.Get("quota-type"))
+	assert.Contains(t, f.query, "quota")
+
+	f.respond(200, "")
+	err = api.SetUserBucketQuota(ctx, "alice", admin.Quota{Enabled: true, MaxSize: admin.Unlimited, MaxObjects: 1000})
+	assert.NoError(t, err)
+	assert.Equal(t, "PUT", f.method)
+	assert.Equal(t, "bucket", f.query.Get("quota-type"))
+	assert.Equal(t, "true", f.query.Get("enabled"))
+	assert.Equal(t, "-1", f.query.Get("max-size"))
+	assert.Equal(t, "1000", f.query.Get("max-objects"))
+
+	f.respond(200, `{"bucket":"photos","owner":"alice","bucket_quota":{"enabled":false,"check_on_raw":false,"max_size":-1,"max_size_kb":0,"max_objects":-1}}`)
+	q, err = api.GetBucketQuota(ctx, "photos")
+	assert.NoError(t, err)
+	assert.Equal(t, &admin.Quota{MaxSize: admin.Unlimited, MaxObjects: admin.Unlimited}, q)
+	assert.Equal(t, "/admin/bucket", f.path)
+	assert.Equal(t, "photos", f.query.Get("bucket"))
+
+	f.respond(200, "")
+	err = api.SetBucketQuota(ctx, "alice", "photos", admin.Quota{Enabled: true, MaxSize: 1 << 20, MaxObjects: admin.Unlimited})
+	assert.NoError(t, err)
+	assert.Equal(t, "PUT", f.method)
+	assert.Equal(t, "/admin/bucket", f.path)
+	assert.Equal(t, "photos", f.query.Get("bucket"))
+	assert.Equal(t, "1048576", f.query.Get("max-size"))
+}
+
+const testBucketInfo = `{
+	"bucket": "photos",
+	"num_shards": 11,
+	"tenant": "",
+	"zonegroup": "8ea5fa2b-9e14-4d1d-8b9b-8d7a0b4d3c7e",
+	"placement_rule": "default-placement",
+	"explicit_placement": {"data_pool": "", "data_extra_pool": "", "index_pool": ""},
+	"id": "7cfe4ac4-4d52-4d3c-9a5b-3c3b5d8e2c1a.4137.1",
+	"marker": "7cfe4ac4-4d52-4d3c-9a5b-3c3b5d8e2c1a.4137.1",
+	"index_type": "Normal",
+	"owner": "alice",
+	"mtime": "2023-03-15T10:20:30.123456Z",
+	"creation_time": "2023-03-15T10:20:30.123456Z",
+	"usage": {
+		"rgw.main": {
+			"size": 1024,
+			"size_actual": 4096,
+			"size_utilized": 1024,
+			"size_kb": 1,
+			"size_kb_actual": 4,
+			"size_kb_utilized": 1,
+			"num_objects": 1
+		}
+	},
+	"bucket_quota": {"enabled": false, "check_on_raw": false, "max_size": -1, "max_size_kb": 0, "max_objects": -1}
+}`
+
+func TestBuckets(t *testing.T) {
+	f, api := newFakeRGW(t)
+	ctx := context.Background()
+
+	f.respond(200, `["photos","videos"]`)
+	names, err := api.ListBuckets(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"photos", "videos"}, names)
+	assert.Equal(t, "/admin/bucket", f.path)
+	assert.Empty(t, f.query)
+
+	names, err = api.ListUserBuckets(ctx, "alice")
+	assert.NoError(t, err)
+	assert.Len(t, names, 2)
+	assert.Equal(t, "alice", f.query.Get("uid"))
+
+	f.respond(200, testBucketInfo)
+	b, err := api.GetBucketInfo(ctx, "photos")
+	assert.NoError(t, err)
+	assert.Equal(t, "photos", f.query.Get("bucket"))
+	assert.Equal(t, "true", f.query.Get("stats"))
+	assert.Equal(t, "alice", b.Owner)
+	assert.Equal(t, uint64(11), b.NumShards)
+	assert.Equal(t, "default-placement", b.PlacementRule)
+	assert.Equal(t, uint64(1), b.Usage["rgw.main"].NumObjects)
+	assert.Equal(t, uint64(4096), b.Usage["rgw.main"].SizeActual)
+
+	f.respond(200, "["+testBucketInfo+"]")
+	buckets, err := api.ListBucketsWithStats(ctx, "")
+	assert.NoError(t, err)
+	assert.Len(t, buckets, 1)
+	assert.NotContains(t, f.query, "uid")
+}
+
+func TestBucketLink(t *testing.T) {
+	f, api := newFakeRGW(t)
+	ctx := context.Background()
+
+	err := api.LinkBucket(ctx, admin.BucketLinkInput{Bucket: "photos", UID: "bob"})
+	assert.NoError(t, err)
+	assert.Equal(t, "PUT", f.method)
+	assert.Equal(t, "/admin/bucket", f.path)
+	assert.Equal(t, url.Values{"bucket": {"photos"}, "uid": {"bob"}}, f.query)
+
+	err = api.LinkBucket(ctx, admin.BucketLinkInput{
+		Bucket:        "photos",
+		UID:           "bob",
+		BucketID:      "7cfe4ac4.4137.1",
+		NewBucketName: "bob-photos",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "7cfe4ac4.4137.1", f.query.Get("bucket-id"))
+	assert.Equal(t, "bob-photos", f.query.Get("new-bucket-name"))
+
+	err = api.UnlinkBucket(ctx, "alice", "photos")
+	assert.NoError(t, err)
+	assert.Equal(t, "POST", f.method)
+	assert.Equal(t, url.Values{"bucket": {"photos"}, "uid": {"alice"}}, f.query)
+
+	f.respond(404, `{"Code":"NoSuchBucket"}`)
+	err = api.UnlinkBucket(ctx, "alice", "photos")
+	assert.True(t, admin.IsCode(err, "NoSuchBucket"))
+}
+
+func TestKeys(t *testing.T) {
+	f, api := newFakeRGW(t)
+	ctx := context.Background()
+
+	f.respond(200, `[{"user":"alice","access_key":"AK1","secret_key":"SK1"},{"user":"alice","access_key":"AK2","secret_key":"SK2"}]`)
+	keys, err := api.CreateKey(ctx, admin.KeySpec{UID: "alice", KeyType: admin.KeyTypeS3})
+	assert.NoError(t, err)
+	assert.Len(t, keys, 2)
+	assert.Equal(t, admin.UserKey{User: "alice", AccessKey: "AK2", SecretKey: "SK2"}, keys[1])
+	assert.Equal(t, "PUT", f.method)
+	assert.Equal(t, "/admin/user", f.path)
+	assert.Contains(t, f.query, "key")
+	assert.Equal(t, "s3", f.query.Get("key-type"))
+	assert.Equal(t, "true", f.query.Get("generate-key"))
+
+	_, err = api.CreateKey(ctx, admin.KeySpec{
+		UID:       "alice",
+		KeyType:   admin.KeyTypeS3,
+		AccessKey: "AK3",
+		SecretKey: "SK3",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "AK3", f.query.Get("access-key"))
+	assert.Equal(t, "SK3", f.query.Get("secret-key"))
+	assert.NotContains(t, f.query, "generate-key")
+
+	f.respond(200, `[{"user":"alice:swift","secret_key":"SWIFT"}]`)
+	keys, err = api.CreateKey(ctx, admin.KeySpec{UID: "alice", SubUser: "alice:swift", KeyType: admin.KeyTypeSwift})
+	assert.NoError(t, err)
+	assert.Equal(t, []admin.UserKey{{User: "alice:swift", SecretKey: "SWIFT"}}, keys)
+	assert.Equal(t, "alice:swift", f.query.Get("subuser"))
+
+	f.respond(200, "")
+	err = api.RemoveKey(ctx, admin.KeySpec{UID: "alice", KeyType: admin.KeyTypeS3, AccessKey: "AK1", SecretKey: "ignored"})
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE", f.method)
+	assert.Equal(t, "AK1", f.query.Get("access-key"))
+	assert.NotContains(t, f.query, "secret-key")
+}
+
+func TestUserCaps(t *testing.T) {
+	f, api := newFakeRGW(t)
+	ctx := context.Background()
+
+	f.respond(200, `[{"type":"buckets","perm":"*"},{"type":"users","perm":"read"}]`)
+	caps, err := api.AddUserCaps(ctx, "alice", "users=read;buckets=*")
+	assert.NoError(t, err)
+	assert.Equal(t, []admin.UserCap{{Type: "buckets", Perm: "*"}, {Type: "users", Perm: "read"}}, caps)
+	assert.Equal(t, "PUT", f.method)
+	assert.Equal(t, "/admin/user", f.path)
+	assert.Contains(t, f.query, "caps")
+	assert.Equal(t, "users=read;buckets=*", f.query.Get("user-caps"))
+
+	f.respond(200, `[{"type":"users","perm":"read"}]`)
+	caps, err = api.RemoveUserCaps(ctx, "alice", "buckets=*")
+	assert.NoError(t, err)
+	assert.Len(t, caps, 1)
+	assert.Equal(t, "DELETE", f.method)
+	assert.Equal(t, "buckets=*", f.query.Get("user-caps"))
+}
+
+func TestSubusers(t *testing.T) {
+	f, api := newFakeRGW(t)
+	ctx := context.Background()
+
+	f.respond(200, `[{"id":"alice:swift","permissions":"full-control"}]`)
+	subusers, err := api.CreateSubuser(ctx, admin.SubuserSpec{
+		UID:    "alice",
+		Name:   "alice:swift",
+		Access: admin.SubuserAccessFull,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []admin.Subuser{{ID: "alice:swift", Permissions: "full-control"}}, subusers)
+	assert.Equal(t, "PUT", f.method)
+	assert.Equal(t, "/admin/user", f.path)
+	assert.Equal(t, url.Values{
+		"uid":             {"alice"},
+		"subuser":         {"alice:swift"},
+		"access":          {"full"},
+		"key-type":        {"swift"},
+		"generate-secret": {"true"},
+	}, f.query)
+
+	_, err = api.CreateSubuser(ctx, admin.SubuserSpec{UID: "alice", Name: "alice:ro", SecretKey: "secret"})
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", f.query.Get("secret-key"))
+	assert.NotContains(t, f.query, "generate-secret")
+
+	_, err = api.CreateSubuser(ctx, admin.SubuserSpec{UID: "alice", Name: "alice:nokey", NoKey: true})
+	assert.NoError(t, err)
+	assert.NotContains(t, f.query, "key-type")
+
+	f.respond(200, `[{"id":"alice:swift","permissions":"read"}]`)
+	subusers, err = api.ModifySubuser(ctx, admin.SubuserSpec{
+		UID:    "alice",
+		Name:   "alice:swift",
+		Access: admin.SubuserAccessRead,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "read", subusers[0].Permissions)
+	assert.Equal(t, "POST", f.method)
+	assert.Equal(t, "read", f.query.Get("access"))
+
+	f.respond(200, "")
+	err = api.RemoveSubuser(ctx, "alice", "alice:swift", false)
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE", f.method)
+	assert.NotContains(t, f.query, "purge-keys")
+	err = api.RemoveSubuser(ctx, "alice", "alice:swift", true)
+	assert.NoError(t, err)
+	assert.Equal(t, "false", f.query.Get("purge-keys"))
+}
+
+func TestCheckBucketIndex(t *testing.T) {
+	f, api := newFakeRGW(t)
+	ctx := context.Background()
+
+	f.respond(200, `{
+		"invalid_multipart_entries": [],
+		"check_result": {
+			"existing_header": {"usage": {"rgw.main": {"size": 1024, "num_objects": 1}}},
+			"calculated_header": {"usage": {"rgw.main": {"size": 1024, "num_objects": 1}}}
+		}
+	}`)
+	result, err := api.CheckBucketIndex(ctx, admin.BucketCheckInput{Bucket: "photos"})
+	assert.NoError(t, err)
+	assert.True(t, result.Consistent())
+	assert.Equal(t, "GET", f.method)
+	assert.Equal(t, "/admin/bucket", f.path)
+	assert.Equal(t, url.Values{"index": {""}, "bucket": {"photos"}}, f.query)
+
+	f.respond(200, `{
+		"invalid_multipart_entries": ["_multipart_obj.2~abc.1"],
+		"check_result": {
+			"existing_header": {"usage": {"rgw.main": {"size": 2048, "num_objects": 2}}},
+			"calculated_header": {"usage": {"rgw.main": {"size": 1024, "num_objects": 1}}}
+		}
+	}`)
+	result, err = api.CheckBucketIndex(ctx, admin.BucketCheckInput{
+		Bucket:       "photos",
+		CheckObjects: true,
+		Fix:          true,
+	})
+	assert.NoError(t, err)
+	assert.False(t, result.Consistent())
+	assert.Len(t, result.InvalidMultipartEntries, 1)
+	assert.Equal(t, uint64(2), result.CheckResult.ExistingHeader.Usage["rgw.main"].NumObjects)
+	assert.Equal(t, "true", f.query.Get("check-objects"))
+	assert.Equal(t, "true", f.query.Get("fix"))
+}
+
+func TestObjects(t *testing.T) {
+	f, api := newFakeRGW(t)
+	ctx := context.Background()
+
+	f.header = http.Header{
+		"Content-Length": {"1024"},
+		"Content-Type":   {"image/png"},
+		"Etag":           {`"d41d8cd98f00b204e9800998ecf8427e"`},
+		"Last-Modified":  {"Wed, 15 Mar 2023 10:20:30 GMT"},
+		"X-Amz-Meta-Tag": {"holiday"},
+	}
+	info, err := api.GetObjectInfo(ctx, "photos", "2023/beach photo.png", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "HEAD", f.method)
+	assert.Equal(t, "/photos/2023/beach photo.png", f.path)
+	assert.Equal(t, int64(1024), info.Size)
+	assert.Equal(t, "image/png", info.ContentType)
+	assert.Equal(t, "d41d8cd98f00b204e9800998ecf8427e", info.ETag)
+	assert.Equal(t, time.Date(2023, 3, 15, 10, 20, 30, 0, time.UTC), info.LastModified.UTC())
+	assert.Equal(t, map[string]string{"tag": "holiday"}, info.Metadata)
+
+	_, err = api.GetObjectInfo(ctx, "photos", "beach.png", "v1")
+	assert.NoError(t, err)
+	assert.Equal(t, "v1", f.query.Get("versionId"))
+
+	f.header = nil
+	f.respond(404, "")
+	_, err = api.GetObjectInfo(ctx, "photos", "missing.png", "")
+	assert.True(t, admin.IsCode(err, "Not Found"))
+
+	f.respond(200, "")
+	err = api.RemoveObject(ctx, "photos", "2023/beach photo.png")
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE", f.method)
+	assert.Equal(t, "/admin/bucket", f.path)
+	assert.Equal(t, url.Values{"bucket": {"photos"}, "object": {"2023/beach photo.png"}}, f.query)
+}
+
+func TestTopics(t *testing.T) {
+	f, api := newFakeRGW(t)
+	ctx := context.Background()
+
+	f.respond(200, `<CreateTopicResponse xmlns="https://sns.amazonaws.com/doc/2010-03-31/">
+		<CreateTopicResult><TopicArn>arn:aws:sns:default::uploads</TopicArn></CreateTopicResult>
+		<ResponseMetadata><RequestId>tx1</RequestId></ResponseMetadata>
+	</CreateTopicResponse>`)
+	arn, err := api.CreateTopic(ctx, admin.CreateTopicInput{
+		Name:         "uploads",
+		PushEndpoint: "amqp://rabbit.example.com:5672",
+		Persistent:   true,
+		Attributes:   map[string]string{"amqp-exchange": "ex1"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "arn:aws:sns:default::uploads", arn)
+	assert.Equal(t, "POST", f.method)
+	assert.Equal(t, "/", f.path)
+	form, err := url.ParseQuery(f.body)
+	assert.NoError(t, err)
+	assert.Equal(t, url.Values{
+		"Action":                   {"CreateTopic"},
+		"Name":                     {"uploads"},
+		"Attributes.entry.1.key":   {"amqp-exchange"},
+		"Attributes.entry.1.value": {"ex1"},
+		"Attributes.entry.2.key":   {"persistent"},
+		"Attributes.entry.2.value": {"true"},
+		"Attributes.entry.3.key":   {"push-endpoint"},
+		"Attributes.entry.3.value": {"amqp://rabbit.example.com:5672"},
+	}, form)
+
+	f.respond(200, `<ListTopicsResponse xmlns="https://sns.amazonaws.com/doc/2010-03-31/">
+		<ListTopicsResult><Topics><member>
+			<User>alice</User><Name>uploads</Name>
+			<EndPoint>
+				<EndpointAddress>amqp://rabbit.example.com:5672</EndpointAddress>
+				<EndpointArgs>persistent=true</EndpointArgs>
+				<EndpointTopic>uploads</EndpointTopic>
+				<HasStoredSecret>false</HasStoredSecret>
+				<Persistent>true</Persistent>
+			</EndPoint>
+			<TopicArn>arn:aws:sns:default::uploads</TopicArn>
+			<OpaqueData></OpaqueData>
+		</member></Topics></ListTopicsResult>
+	</ListTopicsResponse>`)
+	topics, err := api.ListTopics(ctx)
+	assert.NoError(t, err)
+	if assert.Len(t, topics, 1) {
+		assert.Equal(t, "uploads", topics[0].Name)
+		assert.Equal(t, "amqp://rabbit.example.com:5672", topics[0].Endpoint.Address)
+		assert.True(t, topics[0].Endpoint.Persistent)
+	}
+
+	f.respond(200, `<GetTopicResponse><GetTopicResult><Topic>
+		<User>alice</User><Name>uploads</Name><TopicArn>arn:aws:sns:default::uploads</TopicArn>
+	</Topic></GetTopicResult></GetTopicResponse>`)
+	topic, err := api.GetTopic(ctx, arn)
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", topic.User)
+
+	f.respond(404, `<ErrorResponse><Error><Code>NotFound</Code></Error><RequestId>tx2</RequestId></ErrorResponse>`)
+	err = api.DeleteTopic(ctx, arn)
+	assert.True(t, admin.IsCode(err, "NotFound"))
+}
+
+func TestBucketNotifications(t *testing.T) {
+	f, api := newFakeRGW(t)
+	ctx := context.Background()
+
+	config := admin.NotificationConfiguration{
+		TopicConfigurations: []admin.TopicConfiguration{{
+			ID:     "new-photos",
+			Topic:  "arn:aws:sns:default::uploads",
+			Events: []string{"s3:ObjectCreated:*"},
+			Filter: &admin.NotificationFilter{
+				KeyRules: []admin.FilterRule{{Name: "suffix", Value: ".png"}},
+			},
+		}},
+	}
+	err := api.PutBucketNotification(ctx, "photos", config)
+	assert.NoError(t, err)
+	assert.Equal(t, "PUT", f.method)
+	assert.Equal(t, "/photos", f.path)
+	assert.Contains(t, f.query, "notification")
+	assert.Equal(t, `<NotificationConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">`+
+		`<TopicConfiguration><Id>new-photos</Id><Topic>arn:aws:sns:default::uploads</Topic>`+
+		`<Event>s3:ObjectCreated:*</Event><Filter><S3Key><FilterRule><Name>suffix</Name><Value>.png</Value>`+
+		`</FilterRule></S3Key></Filter></TopicConfiguration></NotificationConfiguration>`, f.body)
+
+	f.respond(200, f.body)
+	got, err := api.GetBucketNotification(ctx, "photos")
+	assert.NoError(t, err)
+	assert.Equal(t, config.TopicConfigurations, got.TopicConfigurations)
+
+	f.respond(200, "")
+	err = api.DeleteBucketNotification(ctx, "photos", "new-photos")
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE", f.method)
+	assert.Equal(t, "new-photos", f.query.Get("notification"))
+}
+
+const testTrustPolicy = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":["arn:aws:iam:::user/alice"]},"Action":["sts:AssumeRole"]}]}`
+
+func TestRoles(t *testing.T) {
+	f, api := newFakeRGW(t)
+	ctx := context.Background()
+
+	f.respond(200, `<CreateRoleResponse><CreateRoleResult><Role>
+		<RoleId>c5f3b8f2</RoleId><RoleName>reader</RoleName><Path>/tenant/</Path>
+		<Arn>arn:aws:iam:::role/tenant/reader</Arn><CreateDate>2023-03-15T10:20:30.123Z</CreateDate>
+		<MaxSessionDuration>7200</MaxSessionDuration>
+		<AssumeRolePolicyDocument>`+testTrustPolicy+`</AssumeRolePolicyDocument>
+	</Role></CreateRoleResult></CreateRoleResponse>`)
+	role, err := api.CreateRole(ctx, admin.CreateRoleInput{
+		Name:                     "reader",
+		Path:                     "/tenant/",
+		AssumeRolePolicyDocument: testTrustPolicy,
+		MaxSessionDuration:       7200,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "arn:aws:iam:::role/tenant/reader", role.ARN)
+	assert.Equal(t, 7200, role.MaxSessionDuration)
+	assert.Equal(t, testTrustPolicy, role.AssumeRolePolicyDocument)
+	form, err := url.ParseQuery(f.body)
+	assert.NoError(t, err)
+	assert.Equal(t, url.Values{
+		"Action":                   {"CreateRole"},
+		"RoleName":                 {"reader"},
+		"Path":                     {"/tenant/"},
+		"AssumeRolePolicyDocument": {testTrustPolicy},
+		"MaxSessionDuration":       {"7200"},
+	}, form)
+
+	f.respond(200, `<GetRoleResponse><GetRoleResult><Role><RoleName>reader</RoleName></Role></GetRoleResult></GetRoleResponse>`)
+	role, err = api.GetRole(ctx, "reader")
+	assert.NoError(t, err)
+	assert.Equal(t, "reader", role.Name)
+
+	f.respond(200, `<ListRolesResponse><ListRolesResult><Roles>
+		<member><RoleName>reader</RoleName></member><member><RoleName>writer</RoleName></member>
+	</Roles></ListRolesResult></ListRolesResponse>`)
+	roles, err := api.ListRoles(ctx, "/tenant/")
+	assert.NoError(t, err)
+	assert.Len(t, roles, 2)
+	form, _ = url.ParseQuery(f.body)
+	assert.Equal(t, "/tenant/", form.Get("PathPrefix"))
+
+	f.respond(200, "")
+	err = api.PutRolePolicy(ctx, "reader", "read-photos", `{"Statement":[]}`)
+	assert.NoError(t, err)
+	form, _ = url.ParseQuery(f.body)
+	assert.Equal(t, "PutRolePolicy", form.Get("Action"))
+	assert.Equal(t, `{"Statement":[]}`, form.Get("PolicyDocument"))
+
+	f.respond(200, `<GetRolePolicyResponse><GetRolePolicyResult>
+		<PolicyName>read-photos</PolicyName><RoleName>reader</RoleName>
+		<PolicyDocument>{"Statement":[]}</PolicyDocument>
+	</GetRolePolicyResult></GetRolePolicyResponse>`)
+	policy, err := api.GetRolePolicy(ctx, "reader", "read-photos")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"Statement":[]}`, policy)
+
+	f.respond(200, `<ListRolePoliciesResponse><ListRolePoliciesResult><PolicyNames>
+		<member>read-photos</member>
+	</PolicyNames></ListRolePoliciesResult></ListRolePoliciesResponse>`)
+	names, err := api.ListRolePolicies(ctx, "reader")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"read-photos"}, names)
+
+	f.respond(200, "")
+	err = api.DeleteRolePolicy(ctx, "reader", "read-photos")
+	assert.NoError(t, err)
+
+	f.respond(409, `<ErrorResponse><Error><Code>DeleteConflict</Code></Error></ErrorResponse>`)
+	err = api.DeleteRole(ctx, "reader")
+	assert.True(t, admin.IsCode(err, "DeleteConflict"))
+}
+
+func TestRateLimits(t *testing.T) {
+	f, api := newFakeRGW(t)
+	ctx := context.Background()
+
+	f.respond(200, `{"user_ratelimit":{"max_read_ops":100,"max_write_ops":50,"max_read_bytes":0,"max_write_bytes":1048576,"enabled":true}}`)
+	rl, err := api.GetUserRateLimit(ctx, "alice")
+	assert.NoError(t, err)
+	assert.Equal(t, &admin.RateLimit{Enabled: true, MaxReadOps: 100, MaxWriteOps: 50, MaxWriteBytes: 1 << 20}, rl)
+	assert.Equal(t, "GET", f.method)
+	assert.Equal(t, "/admin/ratelimit", f.path)
+	assert.Equal(t, url.Values{"ratelimit-scope": {"user"}, "uid": {"alice"}}, f.query)
+
+	f.respond(200, "")
+	err = api.SetBucketRateLimit(ctx, "photos", admin.RateLimit{Enabled: true, MaxReadOps: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, "POST", f.method)
+	assert.Equal(t, url.Values{
+		"ratelimit-scope": {"bucket"},
+		"bucket":          {"photos"},
+		"enabled":         {"true"},
+		"max-read-ops":    {"10"},
+		"max-write-ops":   {"0"},
+		"max-read-bytes":  {"0"},
+		"max-write-bytes": {"0"},
+	}, f.query)
+
+	f.respond(200, `{"bucket_ratelimit":{"max_read_ops":10,"enabled":true}}`)
+	rl, err = api.GetBucketRateLimit(ctx, "photos")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), rl.MaxReadOps)
+
+	f.respond(200, `{"bucket_ratelimit":{"enabled":false},"user_ratelimit":{"enabled":false},"anonymous_ratelimit":{"max_read_ops":5,"enabled":true}}`)
+	limits, err := api.GetGlobalRateLimits(ctx)
+	assert.NoError(t, err)
+	assert.True(t, limits.Anonymous.Enabled)
+	assert.Equal(t, int64(5), limits.Anonymous.MaxReadOps)
+	assert.Equal(t, "true", f.query.Get("global"))
+
+	f.respond(200, "")
+	err = api.SetGlobalRateLimit(ctx, admin.RateLimitAnonymous, admin.RateLimit{})
+	assert.NoError(t, err)
+	assert.Equal(t, "anonymous", f.query.Get("ratelimit-scope"))
+	assert.Equal(t, "false", f.query.Get("enabled"))
+	err = api.SetUserRateLimit(ctx, "alice", admin.RateLimit{Enabled: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", f.query.Get("uid"))
+}
+
+func TestMultisite(t *testing.T) {
+	f, api := newFakeRGW(t)
+	ctx := context.Background()
+
+	f.respond(200, `{"id":"r1","name":"gold","current_period":"p1","epoch":2}`)
+	realm, err := api.GetRealm(ctx, "gold")
+	assert.NoError(t, err)
+	assert.Equal(t, &admin.Realm{ID: "r1", Name: "gold", CurrentPeriod: "p1", Epoch: 2}, realm)
+	assert.Equal(t, "/admin/realm", f.path)
+	assert.Equal(t, "gold", f.query.Get("name"))
+
+	f.respond(200, `{"default_info":"r1","realms":["gold"]}`)
+	realms, err := api.ListRealms(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, &admin.RealmList{Default: "r1", Realms: []string{"gold"}}, realms)
+	assert.Contains(t, f.query, "list")
+
+	f.respond(200, `{
+		"id": "p1", "epoch": 3, "predecessor_uuid": "p0",
+		"period_map": {"id": "p1", "zonegroups": [{
+			"id": "zg1", "name": "us", "api_name": "us", "is_master": "true",
+			"endpoints": ["http://rgw1:8080"], "master_zone": "z1",
+			"zones": [
+				{"id": "z1", "name": "us-east", "endpoints": ["http://rgw1:8080"], "log_meta": "false", "log_data": "true", "read_only": "false", "sync_from_all": "true"},
+				{"id": "z2", "name": "us-west", "endpoints": ["http://rgw2:8080"], "log_meta": false, "log_data": true, "read_only": false, "sync_from_all": true}
+			],
+			"default_placement": "default-placement", "realm_id": "r1"
+		}]},
+		"master_zonegroup": "zg1", "master_zone": "z1",
+		"realm_id": "r1", "realm_name": "gold", "realm_epoch": 2
+	}`)
+	period, err := api.GetPeriod(ctx, "")
+	assert.NoError(t, err)
+	assert.Empty(t, f.query)
+	assert.Equal(t, "z1", period.MasterZone)
+	if assert.Len(t, period.PeriodMap.Zonegroups, 1) {
+		zg := period.PeriodMap.Zonegroups[0]
+		assert.True(t, bool(zg.IsMaster))
+		if assert.Len(t, zg.Zones, 2) {
+			assert.True(t, bool(zg.Zones[0].LogData))
+			assert.True(t, bool(zg.Zones[1].LogData))
+			assert.False(t, bool(zg.Zones[1].LogMeta))
+		}
+	}
+	_, err = api.GetPeriod(ctx, "p0")
+	assert.NoError(t, err)
+	assert.Equal(t, "p0", f.query.Get("period_id"))
+
+	f.respond(200, `{
+		"id": "z2", "name": "us-west", "realm_id": "r1",
+		"domain_root": "us-west.rgw.meta:root",
+		"placement_pools": [{"key": "default-placement", "val": {
+			"index_pool": "us-west.rgw.buckets.index",
+			"storage_classes": {"STANDARD": {"data_pool": "us-west.rgw.buckets.data"}},
+			"data_extra_pool": "us-west.rgw.buckets.non-ec"
+		}}]
+	}`)
+	zone, err := api.GetZone(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "zone", f.query.Get("type"))
+	assert.Equal(t, "us-west", zone.Name)
+	if assert.Len(t, zone.PlacementPools, 1) {
+		assert.Equal(t, "us-west.rgw.buckets.data",
+			zone.PlacementPools[0].Val.StorageClasses["STANDARD"].DataPool)
+	}
+
+	f.respond(200, `{
+		"info": {"status": "sync", "num_shards": 2, "period": "p1", "realm_epoch": 2},
+		"markers": [
+			{"key": 0, "val": {"state": 1, "marker": "1_1678875630.123_5.1", "total_entries": 10, "pos": 10, "timestamp": "2023-03-15T10:20:30Z"}},
+			{"key": 1, "val": {"state": 0, "marker": "", "total_entries": 4, "pos": 2}}
+		]
+	}`)
+	status, err := api.MetadataSyncStatus(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "/admin/log", f.path)
+	assert.Equal(t, "metadata", f.query.Get("type"))
+	assert.Contains(t, f.query, "status")
+	assert.Equal(t, "sync", status.Info.Status)
+	if assert.Len(t, status.Markers, 2) {
+		assert.Equal(t, admin.SyncStateIncrementalSync, status.Markers[0].Val.State)
+		assert.Equal(t, 1, status.Markers[1].Shard)
+		assert.Equal(t, admin.SyncStateFullSync, status.Markers[1].Val.State)
+		assert.Equal(t, int64(2), status.Markers[1].Val.Pos)
+	}
+
+	_, err = api.DataSyncStatus(ctx, "z1")
+	assert.NoError(t, err)
+	assert.Equal(t, "data", f.query.Get("type"))
+	assert.Equal(t, "z1", f.query.Get("source-zone"))
+}
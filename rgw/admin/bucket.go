@@ -0,0 +1,146 @@
+package admin
+
+import (
+	"context"
+	"net/url"
+)
+
+// BucketUsage is the usage of a category of the objects of a bucket.
+type BucketUsage struct {
+	Size           uint64 `json:"size"`
+	SizeActual     uint64 `json:"size_actual"`
+	SizeUtilized   uint64 `json:"size_utilized"`
+	SizeKb         uint64 `json:"size_kb"`
+	SizeKbActual   uint64 `json:"size_kb_actual"`
+	SizeKbUtilized uint64 `json:"size_kb_utilized"`
+	NumObjects     uint64 `json:"num_objects"`
+}
+
+// ExplicitPlacement are the pools of a bucket created before placement
+// rules existed.
+type ExplicitPlacement struct {
+	DataPool      string `json:"data_pool"`
+	DataExtraPool string `json:"data_extra_pool"`
+	IndexPool     string `json:"index_pool"`
+}
+
+// Bucket describes a bucket and its usage.
+type Bucket struct {
+	Bucket            string            `json:"bucket"`
+	Tenant            string            `json:"tenant"`
+	Owner             string            `json:"owner"`
+	ID                string            `json:"id"`
+	Marker            string            `json:"marker"`
+	Zonegroup         string            `json:"zonegroup"`
+	PlacementRule     string            `json:"placement_rule"`
+	ExplicitPlacement ExplicitPlacement `json:"explicit_placement"`
+	IndexType         string            `json:"index_type"`
+	// NumShards is the number of shards of the bucket index.
+	NumShards uint64 `json:"num_shards"`
+	// Mtime and CreationTime are in ISO 8601 format.
+	Mtime        string `json:"mtime"`
+	CreationTime string `json:"creation_time"`
+	// Usage maps the categories of objects, like "rgw.main" for the
+	// objects and "rgw.multimeta" for incomplete multipart uploads, to
+	// their usage.
+	Usage       map[string]BucketUsage `json:"usage"`
+	BucketQuota Quota                  `json:"bucket_quota"`
+}
+
+// ListBuckets returns the names of all buckets.
+func (api *API) ListBuckets(ctx context.Context) ([]string, error) {
+	return api.ListUserBuckets(ctx, "")
+}
+
+// ListUserBuckets returns the names of the buckets owned by the user uid.
+func (api *API) ListUserBuckets(ctx context.Context, uid string) ([]string, error) {
+	params := url.Values{}
+	setString(params, "uid", uid)
+	var names []string
+	err := api.call(ctx, request{
+		method:   "GET",
+		resource: "bucket",
+		params:   params,
+	}, &names)
+	if err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// ListBucketsWithStats returns the descriptions and usage of the buckets
+// owned by the user uid, or of all buckets if uid is empty.
+func (api *API) ListBucketsWithStats(ctx context.Context, uid string) ([]Bucket, error) {
+	params := url.Values{"stats": {"true"}}
+	setString(params, "uid", uid)
+	var buckets []Bucket
+	err := api.call(ctx, request{
+		method:   "GET",
+		resource: "bucket",
+		params:   params,
+	}, &buckets)
+	if err != nil {
+		return nil, err
+	}
+	return buckets, nil
+}
+
+// GetBucketInfo returns the description and usage of the bucket.
+func (api *API) GetBucketInfo(ctx context.Context, bucket string) (*Bucket, error) {
+	b := &Bucket{}
+	err := api.call(ctx, request{
+		method:   "GET",
+		resource: "bucket",
+		params: url.Values{
+			"bucket": {bucket},
+			"stats":  {"true"},
+		},
+	}, b)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// BucketLinkInput are the parameters of LinkBucket.
+type BucketLinkInput struct {
+	Bucket string
+	// UID is the user the bucket is linked to.
+	UID string
+	// BucketID is the id of the bucket instance, required when the bucket
+	// has more than one.
+	BucketID string
+	// NewBucketName renames the bucket while linking it.
+	NewBucketName string
+}
+
+// LinkBucket links the bucket to the user, unlinking it from its previous
+// owner. The bucket and its ACL are owned by the user afterwards; the
+// objects keep the owners they were written with, which the Admin Ops API
+// has no call to change, unlike "radosgw-admin bucket chown".
+func (api *API) LinkBucket(ctx context.Context, in BucketLinkInput) error {
+	params := url.Values{
+		"bucket": {in.Bucket},
+		"uid":    {in.UID},
+	}
+	setString(params, "bucket-id", in.BucketID)
+	setString(params, "new-bucket-name", in.NewBucketName)
+	return api.call(ctx, request{
+		method:   "PUT",
+		resource: "bucket",
+		params:   params,
+	}, nil)
+}
+
+// UnlinkBucket removes the bucket from the buckets of the user uid, leaving
+// it without owner until it is linked again.
+func (api *API) UnlinkBucket(ctx context.Context, uid, bucket string) error {
+	return api.call(ctx, request{
+		method:   "POST",
+		resource: "bucket",
+		params: url.Values{
+			"bucket": {bucket},
+			"uid":    {uid},
+		},
+	}, nil)
+}
@@ -0,0 +1,66 @@
+package admin
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+)
+
+// BucketIndexHeader is the usage of a bucket as accounted in the header of
+// its index.
+type BucketIndexHeader struct {
+	Usage map[string]BucketUsage `json:"usage"`
+}
+
+// BucketCheckResult is the result of CheckBucketIndex.
+type BucketCheckResult struct {
+	// InvalidMultipartEntries are the index entries of multipart upload
+	// parts whose upload is gone.
+	InvalidMultipartEntries []string `json:"invalid_multipart_entries"`
+	CheckResult             struct {
+		// ExistingHeader is the usage the index accounts for.
+		ExistingHeader BucketIndexHeader `json:"existing_header"`
+		// CalculatedHeader is the usage of the entries of the index.
+		CalculatedHeader BucketIndexHeader `json:"calculated_header"`
+	} `json:"check_result"`
+}
+
+// Consistent reports whether the check found neither invalid entries nor a
+// mismatch between the accounted and the calculated usage.
+func (r *BucketCheckResult) Consistent() bool {
+	return len(r.InvalidMultipartEntries) == 0 &&
+		reflect.DeepEqual(r.CheckResult.ExistingHeader.Usage,
+			r.CheckResult.CalculatedHeader.Usage)
+}
+
+// BucketCheckInput are the parameters of CheckBucketIndex.
+type BucketCheckInput struct {
+	Bucket string
+	// CheckObjects also checks the index entries of the objects against
+	// the objects stored, which reads all of them.
+	CheckObjects bool
+	// Fix removes the invalid entries and corrects the accounted usage.
+	Fix bool
+}
+
+// CheckBucketIndex checks the consistency of the index of a bucket and, with
+// Fix, repairs it. The result describes the index before the repair.
+func (api *API) CheckBucketIndex(ctx context.Context, in BucketCheckInput) (*BucketCheckResult, error) {
+	params := url.Values{
+		"index":  {""},
+		"bucket": {in.Bucket},
+	}
+	setBool(params, "check-objects", in.CheckObjects)
+	setBool(params, "fix", in.Fix)
+
+	result := &BucketCheckResult{}
+	err := api.call(ctx, request{
+		method:   "GET",
+		resource: "bucket",
+		params:   params,
+	}, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
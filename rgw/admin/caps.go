@@ -0,0 +1,42 @@
+package admin
+
+import (
+	"context"
+	"net/url"
+)
+
+// UserCap is an admin capability of a user, like the type "buckets" with
+// the permission "read". Perm is "read", "write" or "*" for both.
+type UserCap struct {
+	Type string `json:"type"`
+	Perm string `json:"perm"`
+}
+
+func (api *API) modifyCaps(ctx context.Context, method, uid, caps string) ([]UserCap, error) {
+	var userCaps []UserCap
+	err := api.call(ctx, request{
+		method:   method,
+		resource: "user",
+		params: url.Values{
+			"caps":      {""},
+			"uid":       {uid},
+			"user-caps": {caps},
+		},
+	}, &userCaps)
+	if err != nil {
+		return nil, err
+	}
+	return userCaps, nil
+}
+
+// AddUserCaps grants the caps, like "users=read,write;buckets=*", to the
+// user uid and returns all caps of the user afterwards.
+func (api *API) AddUserCaps(ctx context.Context, uid, caps string) ([]UserCap, error) {
+	return api.modifyCaps(ctx, "PUT", uid, caps)
+}
+
+// RemoveUserCaps revokes the caps, in the form of AddUserCaps, from the user
+// uid and returns the caps the user is left with.
+func (api *API) RemoveUserCaps(ctx context.Context, uid, caps string) ([]UserCap, error) {
+	return api.modifyCaps(ctx, "DELETE", uid, caps)
+}
@@ -0,0 +1,77 @@
+package admin
+
+import (
+	"context"
+	"net/url"
+)
+
+// KeyType is the API a key authenticates with.
+type KeyType string
+
+const (
+	KeyTypeS3    = KeyType("s3")
+	KeyTypeSwift = KeyType("swift")
+)
+
+// UserKey is a key of a user or subuser. Swift keys have no AccessKey; they
+// are used with the name of the subuser, as User.
+type UserKey struct {
+	User      string `json:"user"`
+	AccessKey string `json:"access_key"`
+	SecretKey string `json:"secret_key"`
+}
+
+// KeySpec selects a key of the user UID, or of its subuser SubUser.
+type KeySpec struct {
+	UID     string
+	SubUser string
+	KeyType KeyType
+	// AccessKey is the access key of an S3 key. When creating a key, an
+	// empty AccessKey is generated by the RGW.
+	AccessKey string
+	// SecretKey is the secret of a new key, which is generated by the RGW
+	// if it is empty.
+	SecretKey string
+}
+
+func (k KeySpec) params() url.Values {
+	params := url.Values{
+		"key": {""},
+		"uid": {k.UID},
+	}
+	setString(params, "subuser", k.SubUser)
+	setString(params, "key-type", string(k.KeyType))
+	setString(params, "access-key", k.AccessKey)
+	setString(params, "secret-key", k.SecretKey)
+	return params
+}
+
+// CreateKey creates the key of the spec, generating the parts that are left
+// empty, and returns all keys of the same type of the user afterwards.
+// Creating an S3 key with the AccessKey of an existing key replaces its
+// secret.
+func (api *API) CreateKey(ctx context.Context, k KeySpec) ([]UserKey, error) {
+	params := k.params()
+	setBool(params, "generate-key", k.SecretKey == "")
+	var keys []UserKey
+	err := api.call(ctx, request{
+		method:   "PUT",
+		resource: "user",
+		params:   params,
+	}, &keys)
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// RemoveKey removes the key of the spec. S3 keys are selected by AccessKey,
+// Swift keys by SubUser.
+func (api *API) RemoveKey(ctx context.Context, k KeySpec) error {
+	k.SecretKey = ""
+	return api.call(ctx, request{
+		method:   "DELETE",
+		resource: "user",
+		params:   k.params(),
+	}, nil)
+}
@@ -0,0 +1,243 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+)
+
+// Flag is a boolean of the multisite configuration, which older releases
+// report as the strings "true" and "false".
+type Flag bool
+
+// UnmarshalJSON decodes a boolean or a string.
+func (f *Flag) UnmarshalJSON(data []byte) error {
+	var s string
+	if json.Unmarshal(data, &s) == nil {
+		*f = s == "true"
+		return nil
+	}
+	var b bool
+	if err := json.Unmarshal(data, &b); err != nil {
+		return err
+	}
+	*f = Flag(b)
+	return nil
+}
+
+// Realm is the root of a multisite configuration.
+type Realm struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	CurrentPeriod string `json:"current_period"`
+	Epoch         int    `json:"epoch"`
+}
+
+// RealmList are the names of the realms.
+type RealmList struct {
+	// Default is the id of the default realm.
+	Default string   `json:"default_info"`
+	Realms  []string `json:"realms"`
+}
+
+// ZonegroupZone is a zone as described by its zonegroup.
+type ZonegroupZone struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Endpoints   []string `json:"endpoints"`
+	LogMeta     Flag     `json:"log_meta"`
+	LogData     Flag     `json:"log_data"`
+	ReadOnly    Flag     `json:"read_only"`
+	TierType    string   `json:"tier_type"`
+	SyncFromAll Flag     `json:"sync_from_all"`
+	SyncFrom    []string `json:"sync_from"`
+}
+
+// Zonegroup is a group of zones that replicate the buckets of each other.
+type Zonegroup struct {
+	ID               string          `json:"id"`
+	Name             string          `json:"name"`
+	APIName          string          `json:"api_name"`
+	IsMaster         Flag            `json:"is_master"`
+	Endpoints        []string        `json:"endpoints"`
+	Hostnames        []string        `json:"hostnames"`
+	MasterZone       string          `json:"master_zone"`
+	Zones            []ZonegroupZone `json:"zones"`
+	DefaultPlacement string          `json:"default_placement"`
+	RealmID          string          `json:"realm_id"`
+}
+
+// PeriodMap are the zonegroups of a period.
+type PeriodMap struct {
+	ID         string      `json:"id"`
+	Zonegroups []Zonegroup `json:"zonegroups"`
+}
+
+// Period is a version of the multisite configuration of a realm.
+type Period struct {
+	ID              string    `json:"id"`
+	Epoch           int       `json:"epoch"`
+	PredecessorUUID string    `json:"predecessor_uuid"`
+	PeriodMap       PeriodMap `json:"period_map"`
+	MasterZonegroup string    `json:"master_zonegroup"`
+	MasterZone      string    `json:"master_zone"`
+	RealmID         string    `json:"realm_id"`
+	RealmName       string    `json:"realm_name"`
+	RealmEpoch      int       `json:"realm_epoch"`
+}
+
+// ZonePlacementPool are the pools of a placement target of a zone.
+type ZonePlacementPool struct {
+	Key string `json:"key"`
+	Val struct {
+		IndexPool      string `json:"index_pool"`
+		DataExtraPool  string `json:"data_extra_pool"`
+		StorageClasses map[string]struct {
+			DataPool string `json:"data_pool"`
+		} `json:"storage_classes"`
+	} `json:"val"`
+}
+
+// Zone is the configuration of the zone of the RGW.
+type Zone struct {
+	ID             string              `json:"id"`
+	Name           string              `json:"name"`
+	RealmID        string              `json:"realm_id"`
+	DomainRoot     string              `json:"domain_root"`
+	ControlPool    string              `json:"control_pool"`
+	GCPool         string              `json:"gc_pool"`
+	LCPool         string              `json:"lc_pool"`
+	LogPool        string              `json:"log_pool"`
+	UserKeysPool   string              `json:"user_keys_pool"`
+	UserUIDPool    string              `json:"user_uid_pool"`
+	OtpPool        string              `json:"otp_pool"`
+	PlacementPools []ZonePlacementPool `json:"placement_pools"`
+}
+
+// GetRealm returns the realm name.
+func (api *API) GetRealm(ctx context.Context, name string) (*Realm, error) {
+	realm := &Realm{}
+	err := api.call(ctx, request{
+		method:   "GET",
+		resource: "realm",
+		params:   url.Values{"name": {name}},
+	}, realm)
+	if err != nil {
+		return nil, err
+	}
+	return realm, nil
+}
+
+// ListRealms returns the names of the realms.
+func (api *API) ListRealms(ctx context.Context) (*RealmList, error) {
+	list := &RealmList{}
+	err := api.call(ctx, request{
+		method:   "GET",
+		resource: "realm",
+		params:   url.Values{"list": {""}},
+	}, list)
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// GetPeriod returns the period id, or the current period of the default
+// realm if id is empty. The zonegroups and zones of the multisite
+// configuration are those of its PeriodMap.
+func (api *API) GetPeriod(ctx context.Context, id string) (*Period, error) {
+	params := url.Values{}
+	setString(params, "period_id", id)
+	period := &Period{}
+	err := api.call(ctx, request{
+		method:   "GET",
+		resource: "period",
+		params:   params,
+	}, period)
+	if err != nil {
+		return nil, err
+	}
+	return period, nil
+}
+
+// GetZone returns the configuration of the zone of the RGW.
+func (api *API) GetZone(ctx context.Context) (*Zone, error) {
+	zone := &Zone{}
+	err := api.call(ctx, request{
+		method:   "GET",
+		resource: "config",
+		params:   url.Values{"type": {"zone"}},
+	}, zone)
+	if err != nil {
+		return nil, err
+	}
+	return zone, nil
+}
+
+// SyncState is the phase of the sync of a log shard.
+type SyncState int
+
+const (
+	// SyncStateFullSync is the initial full sync of the shard, which is
+	// still in progress.
+	SyncStateFullSync = SyncState(0)
+	// SyncStateIncrementalSync follows the log of the shard once the full
+	// sync is done.
+	SyncStateIncrementalSync = SyncState(1)
+)
+
+// SyncMarker is the progress of the sync of a log shard.
+type SyncMarker struct {
+	Shard int `json:"key"`
+	Val   struct {
+		State          SyncState `json:"state"`
+		Marker         string    `json:"marker"`
+		NextStepMarker string    `json:"next_step_marker"`
+		TotalEntries   int64     `json:"total_entries"`
+		Pos            int64     `json:"pos"`
+		Timestamp      string    `json:"timestamp"`
+	} `json:"val"`
+}
+
+// SyncStatus is the progress of the metadata or data sync of the zone of
+// the RGW.
+type SyncStatus struct {
+	Info struct {
+		// Status is "init", "building-full-sync-maps" or "sync".
+		Status    string `json:"status"`
+		NumShards int    `json:"num_shards"`
+	} `json:"info"`
+	Markers []SyncMarker `json:"markers"`
+}
+
+func (api *API) syncStatus(ctx context.Context, params url.Values) (*SyncStatus, error) {
+	status := &SyncStatus{}
+	err := api.call(ctx, request{
+		method:   "GET",
+		resource: "log",
+		params:   params,
+	}, status)
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// MetadataSyncStatus returns the progress of the sync of the metadata, like
+// users and buckets, from the master zone. It fails on the master zone.
+func (api *API) MetadataSyncStatus(ctx context.Context) (*SyncStatus, error) {
+	return api.syncStatus(ctx, url.Values{
+		"type":   {"metadata"},
+		"status": {""},
+	})
+}
+
+// DataSyncStatus returns the progress of the sync of the objects from the
+// zone with the id sourceZone.
+func (api *API) DataSyncStatus(ctx context.Context, sourceZone string) (*SyncStatus, error) {
+	return api.syncStatus(ctx, url.Values{
+		"type":        {"data"},
+		"status":      {""},
+		"source-zone": {sourceZone},
+	})
+}
@@ -0,0 +1,195 @@
+package admin
+
+import (
+	"context"
+	"encoding/xml"
+	"net/url"
+	"sort"
+	"strconv"
+)
+
+// The topics are managed through the SNS compatible API of the RGW and the
+// notifications of buckets through the S3 API, as the Admin Ops API has no
+// calls for them. Topics belong to the user of the credentials of the API,
+// and the notifications of buckets owned by other users can only be managed
+// with the credentials of a user created with the admin or system flag.
+
+// TopicEndpoint is the endpoint notifications of a topic are pushed to.
+type TopicEndpoint struct {
+	Address         string `xml:"EndpointAddress"`
+	Args            string `xml:"EndpointArgs"`
+	Topic           string `xml:"EndpointTopic"`
+	HasStoredSecret bool   `xml:"HasStoredSecret"`
+	Persistent      bool   `xml:"Persistent"`
+}
+
+// Topic is a topic that bucket notifications are sent to.
+type Topic struct {
+	User       string        `xml:"User"`
+	Name       string        `xml:"Name"`
+	Endpoint   TopicEndpoint `xml:"EndPoint"`
+	ARN        string        `xml:"TopicArn"`
+	OpaqueData string        `xml:"OpaqueData"`
+}
+
+// CreateTopicInput are the parameters of a new topic.
+type CreateTopicInput struct {
+	Name string
+	// PushEndpoint is the URL of the HTTP, AMQP or Kafka endpoint, like
+	// "amqp://rabbit.example.com:5672", that notifications are pushed to.
+	PushEndpoint string
+	// OpaqueData is added to every notification of the topic.
+	OpaqueData string
+	// Persistent queues the notifications for asynchronous delivery
+	// instead of pushing them while the request is served.
+	Persistent bool
+	// Attributes are further attributes of the endpoint, like
+	// "amqp-exchange" or "kafka-ack-level".
+	Attributes map[string]string
+}
+
+// CreateTopic creates a topic, or changes the attributes of the topic of
+// the same name, and returns its ARN.
+func (api *API) CreateTopic(ctx context.Context, in CreateTopicInput) (string, error) {
+	attrs := map[string]string{}
+	for key, value := range in.Attributes {
+		attrs[key] = value
+	}
+	if in.PushEndpoint != "" {
+		attrs["push-endpoint"] = in.PushEndpoint
+	}
+	if in.OpaqueData != "" {
+		attrs["OpaqueData"] = in.OpaqueData
+	}
+	if in.Persistent {
+		attrs["persistent"] = "true"
+	}
+	keys := make([]string, 0, len(attrs))
+	for key := range attrs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	form := url.Values{
+		"Action": {"CreateTopic"},
+		"Name":   {in.Name},
+	}
+	for i, key := range keys {
+		entry := "Attributes.entry." + strconv.Itoa(i+1)
+		form.Set(entry+".key", key)
+		form.Set(entry+".value", attrs[key])
+	}
+
+	var reply struct {
+		ARN string `xml:"CreateTopicResult>TopicArn"`
+	}
+	if err := api.action(ctx, form, &reply); err != nil {
+		return "", err
+	}
+	return reply.ARN, nil
+}
+
+// ListTopics returns the topics of the user.
+func (api *API) ListTopics(ctx context.Context) ([]Topic, error) {
+	var reply struct {
+		Topics []Topic `xml:"ListTopicsResult>Topics>member"`
+	}
+	err := api.action(ctx, url.Values{"Action": {"ListTopics"}}, &reply)
+	if err != nil {
+		return nil, err
+	}
+	return reply.Topics, nil
+}
+
+// GetTopic returns the topic with the ARN.
+func (api *API) GetTopic(ctx context.Context, arn string) (*Topic, error) {
+	var reply struct {
+		Topic Topic `xml:"GetTopicResult>Topic"`
+	}
+	err := api.action(ctx, url.Values{
+		"Action":   {"GetTopic"},
+		"TopicArn": {arn},
+	}, &reply)
+	if err != nil {
+		return nil, err
+	}
+	return &reply.Topic, nil
+}
+
+// DeleteTopic removes the topic with the ARN. Notifications of buckets that
+// refer to it are not sent anymore.
+func (api *API) DeleteTopic(ctx context.Context, arn string) error {
+	return api.action(ctx, url.Values{
+		"Action":   {"DeleteTopic"},
+		"TopicArn": {arn},
+	}, nil)
+}
+
+// FilterRule matches the key of an object by its "prefix", "suffix" or
+// "regex".
+type FilterRule struct {
+	Name  string `xml:"Name"`
+	Value string `xml:"Value"`
+}
+
+// NotificationFilter limits a notification to some objects.
+type NotificationFilter struct {
+	KeyRules []FilterRule `xml:"S3Key>FilterRule,omitempty"`
+}
+
+// TopicConfiguration sends notifications of events of a bucket to a topic.
+type TopicConfiguration struct {
+	ID string `xml:"Id"`
+	// Topic is the ARN of the topic.
+	Topic string `xml:"Topic"`
+	// Events are the types of events, like "s3:ObjectCreated:*" or
+	// "s3:ObjectRemoved:Delete".
+	Events []string            `xml:"Event"`
+	Filter *NotificationFilter `xml:"Filter,omitempty"`
+}
+
+// NotificationConfiguration are the notifications of a bucket.
+type NotificationConfiguration struct {
+	XMLName             xml.Name             `xml:"http://s3.amazonaws.com/doc/2006-03-01/ NotificationConfiguration"`
+	TopicConfigurations []TopicConfiguration `xml:"TopicConfiguration"`
+}
+
+// PutBucketNotification adds the notifications of the configuration to the
+// bucket, replacing those with the same ID.
+func (api *API) PutBucketNotification(ctx context.Context, bucket string, config NotificationConfiguration) error {
+	body, err := xml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	return api.callXML(ctx, request{
+		method:      "PUT",
+		path:        "/" + bucket,
+		params:      url.Values{"notification": {""}},
+		body:        body,
+		contentType: "application/xml",
+	}, nil)
+}
+
+// GetBucketNotification returns the notifications of the bucket.
+func (api *API) GetBucketNotification(ctx context.Context, bucket string) (*NotificationConfiguration, error) {
+	config := &NotificationConfiguration{}
+	err := api.callXML(ctx, request{
+		method: "GET",
+		path:   "/" + bucket,
+		params: url.Values{"notification": {""}},
+	}, config)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// DeleteBucketNotification removes the notification id of the bucket, or
+// all its notifications if id is empty.
+func (api *API) DeleteBucketNotification(ctx context.Context, bucket, id string) error {
+	return api.callXML(ctx, request{
+		method: "DELETE",
+		path:   "/" + bucket,
+		params: url.Values{"notification": {id}},
+	}, nil)
+}
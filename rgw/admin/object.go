@@ -0,0 +1,80 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ObjectInfo describes an object.
+type ObjectInfo struct {
+	Bucket       string
+	Key          string
+	VersionID    string
+	Size         int64
+	ETag         string
+	ContentType  string
+	LastModified time.Time
+	// Metadata is the user metadata of the object, keyed by the lower case
+	// names without the x-amz-meta- prefix.
+	Metadata map[string]string
+}
+
+// GetObjectInfo returns the description of the object key in the bucket,
+// or of its version versionID if that is not empty. The Admin Ops API has
+// no call for it, so it is an S3 HEAD request, which only succeeds for
+// objects of other users if the credentials of the API are those of a user
+// created with the admin or system flag. A missing object fails with the
+// code "Not Found".
+func (api *API) GetObjectInfo(ctx context.Context, bucket, key, versionID string) (*ObjectInfo, error) {
+	params := url.Values{}
+	setString(params, "versionId", versionID)
+	header, _, err := api.send(ctx, request{
+		method: "HEAD",
+		path:   "/" + bucket + "/" + key,
+		params: params,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	info := &ObjectInfo{
+		Bucket:      bucket,
+		Key:         key,
+		VersionID:   header.Get("X-Amz-Version-Id"),
+		ETag:        strings.Trim(header.Get("ETag"), `"`),
+		ContentType: header.Get("Content-Type"),
+		Metadata:    map[string]string{},
+	}
+	if info.Size, err = strconv.ParseInt(header.Get("Content-Length"), 10, 64); err != nil {
+		return nil, err
+	}
+	if lm := header.Get("Last-Modified"); lm != "" {
+		if info.LastModified, err = http.ParseTime(lm); err != nil {
+			return nil, err
+		}
+	}
+	for name, values := range header {
+		name = strings.ToLower(name)
+		if strings.HasPrefix(name, "x-amz-meta-") && len(values) > 0 {
+			info.Metadata[strings.TrimPrefix(name, "x-amz-meta-")] = values[0]
+		}
+	}
+	return info, nil
+}
+
+// RemoveObject removes the object key from the bucket regardless of its
+// owner and of the ACLs of the bucket.
+func (api *API) RemoveObject(ctx context.Context, bucket, key string) error {
+	return api.call(ctx, request{
+		method:   "DELETE",
+		resource: "bucket",
+		params: url.Values{
+			"bucket": {bucket},
+			"object": {key},
+		},
+	}, nil)
+}
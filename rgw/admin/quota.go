@@ -0,0 +1,105 @@
+package admin
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// Unlimited is the value of a quota limit that is not enforced.
+const Unlimited = -1
+
+// Quota limits the size and number of objects of a user or bucket.
+type Quota struct {
+	Enabled bool `json:"enabled"`
+	// CheckOnRaw enforces MaxSize on the raw space used, including the
+	// rounding to allocation units, rather than on the object sizes.
+	CheckOnRaw bool `json:"check_on_raw"`
+	// MaxSize is in bytes, or Unlimited.
+	MaxSize int64 `json:"max_size"`
+	// MaxObjects is the number of objects, or Unlimited.
+	MaxObjects int64 `json:"max_objects"`
+}
+
+func (q Quota) params(params url.Values) url.Values {
+	params.Set("enabled", strconv.FormatBool(q.Enabled))
+	params.Set("check-on-raw", strconv.FormatBool(q.CheckOnRaw))
+	params.Set("max-size", strconv.FormatInt(q.MaxSize, 10))
+	params.Set("max-objects", strconv.FormatInt(q.MaxObjects, 10))
+	return params
+}
+
+func (api *API) getUserQuota(ctx context.Context, uid, quotaType string) (*Quota, error) {
+	q := &Quota{}
+	err := api.call(ctx, request{
+		method:   "GET",
+		resource: "user",
+		params: url.Values{
+			"quota":      {""},
+			"uid":        {uid},
+			"quota-type": {quotaType},
+		},
+	}, q)
+	if err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+func (api *API) setUserQuota(ctx context.Context, uid, quotaType string, q Quota) error {
+	return api.call(ctx, request{
+		method:   "PUT",
+		resource: "user",
+		params: q.params(url.Values{
+			"quota":      {""},
+			"uid":        {uid},
+			"quota-type": {quotaType},
+		}),
+	}, nil)
+}
+
+// GetUserQuota returns the quota of the user uid, which limits all its
+// buckets together.
+func (api *API) GetUserQuota(ctx context.Context, uid string) (*Quota, error) {
+	return api.getUserQuota(ctx, uid, "user")
+}
+
+// SetUserQuota sets the quota of the user uid, which limits all its buckets
+// together.
+func (api *API) SetUserQuota(ctx context.Context, uid string, q Quota) error {
+	return api.setUserQuota(ctx, uid, "user", q)
+}
+
+// GetUserBucketQuota returns the bucket quota of the user uid, which limits
+// each of its buckets that has no quota of its own.
+func (api *API) GetUserBucketQuota(ctx context.Context, uid string) (*Quota, error) {
+	return api.getUserQuota(ctx, uid, "bucket")
+}
+
+// SetUserBucketQuota sets the bucket quota of the user uid, which limits
+// each of its buckets that has no quota of its own.
+func (api *API) SetUserBucketQuota(ctx context.Context, uid string, q Quota) error {
+	return api.setUserQuota(ctx, uid, "bucket", q)
+}
+
+// GetBucketQuota returns the quota of the bucket.
+func (api *API) GetBucketQuota(ctx context.Context, bucket string) (*Quota, error) {
+	b, err := api.GetBucketInfo(ctx, bucket)
+	if err != nil {
+		return nil, err
+	}
+	return &b.BucketQuota, nil
+}
+
+// SetBucketQuota sets the quota of the bucket owned by the user uid.
+func (api *API) SetBucketQuota(ctx context.Context, uid, bucket string, q Quota) error {
+	return api.call(ctx, request{
+		method:   "PUT",
+		resource: "bucket",
+		params: q.params(url.Values{
+			"quota":  {""},
+			"uid":    {uid},
+			"bucket": {bucket},
+		}),
+	}, nil)
+}
@@ -0,0 +1,135 @@
+package admin
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// RateLimit limits the operations and bytes of a user or bucket, per minute
+// and per RGW instance. A limit of 0 is not enforced.
+type RateLimit struct {
+	Enabled       bool  `json:"enabled"`
+	MaxReadOps    int64 `json:"max_read_ops"`
+	MaxWriteOps   int64 `json:"max_write_ops"`
+	MaxReadBytes  int64 `json:"max_read_bytes"`
+	MaxWriteBytes int64 `json:"max_write_bytes"`
+}
+
+func (rl RateLimit) params(params url.Values) url.Values {
+	params.Set("enabled", strconv.FormatBool(rl.Enabled))
+	params.Set("max-read-ops", strconv.FormatInt(rl.MaxReadOps, 10))
+	params.Set("max-write-ops", strconv.FormatInt(rl.MaxWriteOps, 10))
+	params.Set("max-read-bytes", strconv.FormatInt(rl.MaxReadBytes, 10))
+	params.Set("max-write-bytes", strconv.FormatInt(rl.MaxWriteBytes, 10))
+	return params
+}
+
+// GlobalRateLimits are the rate limits that apply to the users, buckets and
+// anonymous requests without rate limits of their own.
+type GlobalRateLimits struct {
+	User      RateLimit `json:"user_ratelimit"`
+	Bucket    RateLimit `json:"bucket_ratelimit"`
+	Anonymous RateLimit `json:"anonymous_ratelimit"`
+}
+
+// RateLimitScope selects the global rate limit set by SetGlobalRateLimit.
+type RateLimitScope string
+
+const (
+	RateLimitUser      = RateLimitScope("user")
+	RateLimitBucket    = RateLimitScope("bucket")
+	RateLimitAnonymous = RateLimitScope("anonymous")
+)
+
+// GetUserRateLimit returns the rate limit of the user uid.
+func (api *API) GetUserRateLimit(ctx context.Context, uid string) (*RateLimit, error) {
+	var reply struct {
+		RateLimit RateLimit `json:"user_ratelimit"`
+	}
+	err := api.call(ctx, request{
+		method:   "GET",
+		resource: "ratelimit",
+		params: url.Values{
+			"ratelimit-scope": {"user"},
+			"uid":             {uid},
+		},
+	}, &reply)
+	if err != nil {
+		return nil, err
+	}
+	return &reply.RateLimit, nil
+}
+
+// SetUserRateLimit sets the rate limit of the user uid, which limits the
+// requests of the user to all its buckets together.
+func (api *API) SetUserRateLimit(ctx context.Context, uid string, rl RateLimit) error {
+	return api.call(ctx, request{
+		method:   "POST",
+		resource: "ratelimit",
+		params: rl.params(url.Values{
+			"ratelimit-scope": {"user"},
+			"uid":             {uid},
+		}),
+	}, nil)
+}
+
+// GetBucketRateLimit returns the rate limit of the bucket.
+func (api *API) GetBucketRateLimit(ctx context.Context, bucket string) (*RateLimit, error) {
+	var reply struct {
+		RateLimit RateLimit `json:"bucket_ratelimit"`
+	}
+	err := api.call(ctx, request{
+		method:   "GET",
+		resource: "ratelimit",
+		params: url.Values{
+			"ratelimit-scope": {"bucket"},
+			"bucket":          {bucket},
+		},
+	}, &reply)
+	if err != nil {
+		return nil, err
+	}
+	return &reply.RateLimit, nil
+}
+
+// SetBucketRateLimit sets the rate limit of the bucket, which limits the
+// requests of all users to it together.
+func (api *API) SetBucketRateLimit(ctx context.Context, bucket string, rl RateLimit) error {
+	return api.call(ctx, request{
+		method:   "POST",
+		resource: "ratelimit",
+		params: rl.params(url.Values{
+			"ratelimit-scope": {"bucket"},
+			"bucket":          {bucket},
+		}),
+	}, nil)
+}
+
+// GetGlobalRateLimits returns the global rate limits.
+func (api *API) GetGlobalRateLimits(ctx context.Context) (*GlobalRateLimits, error) {
+	limits := &GlobalRateLimits{}
+	err := api.call(ctx, request{
+		method:   "GET",
+		resource: "ratelimit",
+		params:   url.Values{"global": {"true"}},
+	}, limits)
+	if err != nil {
+		return nil, err
+	}
+	return limits, nil
+}
+
+// SetGlobalRateLimit sets the global rate limit of the scope. It is stored
+// in the period of the realm, which must be committed for the RGW instances
+// to apply it in multisite configurations.
+func (api *API) SetGlobalRateLimit(ctx context.Context, scope RateLimitScope, rl RateLimit) error {
+	return api.call(ctx, request{
+		method:   "POST",
+		resource: "ratelimit",
+		params: rl.params(url.Values{
+			"global":          {"true"},
+			"ratelimit-scope": {string(scope)},
+		}),
+	}, nil)
+}
@@ -0,0 +1,147 @@
+package admin
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// Roles are managed through the IAM compatible API of the RGW, which needs
+// the "roles" caps on the user of the credentials of the API.
+
+// Role is a role that users can assume with the STS AssumeRole call.
+type Role struct {
+	ID   string `xml:"RoleId"`
+	Name string `xml:"RoleName"`
+	Path string `xml:"Path"`
+	ARN  string `xml:"Arn"`
+	// CreateDate is in ISO 8601 format.
+	CreateDate string `xml:"CreateDate"`
+	// MaxSessionDuration is in seconds.
+	MaxSessionDuration int `xml:"MaxSessionDuration"`
+	// AssumeRolePolicyDocument is the JSON trust policy of the role.
+	AssumeRolePolicyDocument string `xml:"AssumeRolePolicyDocument"`
+}
+
+// CreateRoleInput are the parameters of a new role.
+type CreateRoleInput struct {
+	Name string
+	// Path groups roles, like "/tenant/", by default "/".
+	Path string
+	// AssumeRolePolicyDocument is the JSON trust policy that names the
+	// principals that may assume the role.
+	AssumeRolePolicyDocument string
+	// MaxSessionDuration limits the lifetime of the credentials of an
+	// assumed role, in seconds, by default an hour.
+	MaxSessionDuration int
+}
+
+// CreateRole creates a role.
+func (api *API) CreateRole(ctx context.Context, in CreateRoleInput) (*Role, error) {
+	form := url.Values{
+		"Action":                   {"CreateRole"},
+		"RoleName":                 {in.Name},
+		"AssumeRolePolicyDocument": {in.AssumeRolePolicyDocument},
+	}
+	setString(form, "Path", in.Path)
+	if in.MaxSessionDuration != 0 {
+		form.Set("MaxSessionDuration", strconv.Itoa(in.MaxSessionDuration))
+	}
+
+	var reply struct {
+		Role Role `xml:"CreateRoleResult>Role"`
+	}
+	if err := api.action(ctx, form, &reply); err != nil {
+		return nil, err
+	}
+	return &reply.Role, nil
+}
+
+// GetRole returns the role name.
+func (api *API) GetRole(ctx context.Context, name string) (*Role, error) {
+	var reply struct {
+		Role Role `xml:"GetRoleResult>Role"`
+	}
+	err := api.action(ctx, url.Values{
+		"Action":   {"GetRole"},
+		"RoleName": {name},
+	}, &reply)
+	if err != nil {
+		return nil, err
+	}
+	return &reply.Role, nil
+}
+
+// ListRoles returns the roles whose path starts with pathPrefix, or all
+// roles if it is empty.
+func (api *API) ListRoles(ctx context.Context, pathPrefix string) ([]Role, error) {
+	form := url.Values{"Action": {"ListRoles"}}
+	setString(form, "PathPrefix", pathPrefix)
+	var reply struct {
+		Roles []Role `xml:"ListRolesResult>Roles>member"`
+	}
+	if err := api.action(ctx, form, &reply); err != nil {
+		return nil, err
+	}
+	return reply.Roles, nil
+}
+
+// DeleteRole removes the role name, which must have no policies left.
+func (api *API) DeleteRole(ctx context.Context, name string) error {
+	return api.action(ctx, url.Values{
+		"Action":   {"DeleteRole"},
+		"RoleName": {name},
+	}, nil)
+}
+
+// PutRolePolicy sets the JSON permission policy name of the role, which
+// limits what users that assumed the role may do.
+func (api *API) PutRolePolicy(ctx context.Context, role, name, policy string) error {
+	return api.action(ctx, url.Values{
+		"Action":         {"PutRolePolicy"},
+		"RoleName":       {role},
+		"PolicyName":     {name},
+		"PolicyDocument": {policy},
+	}, nil)
+}
+
+// GetRolePolicy returns the JSON permission policy name of the role.
+func (api *API) GetRolePolicy(ctx context.Context, role, name string) (string, error) {
+	var reply struct {
+		Policy string `xml:"GetRolePolicyResult>PolicyDocument"`
+	}
+	err := api.action(ctx, url.Values{
+		"Action":     {"GetRolePolicy"},
+		"RoleName":   {role},
+		"PolicyName": {name},
+	}, &reply)
+	if err != nil {
+		return "", err
+	}
+	return reply.Policy, nil
+}
+
+// ListRolePolicies returns the names of the permission policies of the
+// role.
+func (api *API) ListRolePolicies(ctx context.Context, role string) ([]string, error) {
+	var reply struct {
+		Names []string `xml:"ListRolePoliciesResult>PolicyNames>member"`
+	}
+	err := api.action(ctx, url.Values{
+		"Action":   {"ListRolePolicies"},
+		"RoleName": {role},
+	}, &reply)
+	if err != nil {
+		return nil, err
+	}
+	return reply.Names, nil
+}
+
+// DeleteRolePolicy removes the permission policy name of the role.
+func (api *API) DeleteRolePolicy(ctx context.Context, role, name string) error {
+	return api.action(ctx, url.Values{
+		"Action":     {"DeleteRolePolicy"},
+		"RoleName":   {role},
+		"PolicyName": {name},
+	}, nil)
+}
@@ -0,0 +1,97 @@
+package admin
+
+import (
+	"context"
+	"net/url"
+)
+
+// SubuserAccess is the Swift access level of a subuser.
+type SubuserAccess string
+
+const (
+	SubuserAccessRead      = SubuserAccess("read")
+	SubuserAccessWrite     = SubuserAccess("write")
+	SubuserAccessReadWrite = SubuserAccess("readwrite")
+	SubuserAccessFull      = SubuserAccess("full")
+)
+
+// Subuser is a subuser as returned by the RGW. Permissions is the access
+// level in the form "full-control", "read-write", "read" or "write".
+type Subuser struct {
+	ID          string `json:"id"`
+	Permissions string `json:"permissions"`
+}
+
+// SubuserSpec are the parameters of a subuser of the user UID. Name is the
+// full name of the subuser, like "alice:swift".
+type SubuserSpec struct {
+	UID    string
+	Name   string
+	Access SubuserAccess
+	// SecretKey is the secret of the Swift key of a new subuser. It is
+	// generated by the RGW if it is empty, unless NoKey is set.
+	SecretKey string
+	NoKey     bool
+}
+
+// params are the parameters of the subuser. The subuser parameter also
+// selects the subuser operations of the user resource.
+func (s SubuserSpec) params() url.Values {
+	params := url.Values{
+		"subuser": {s.Name},
+		"uid":     {s.UID},
+	}
+	setString(params, "access", string(s.Access))
+	return params
+}
+
+func (api *API) modifySubuser(ctx context.Context, method string, params url.Values) ([]Subuser, error) {
+	var subusers []Subuser
+	err := api.call(ctx, request{
+		method:   method,
+		resource: "user",
+		params:   params,
+	}, &subusers)
+	if err != nil {
+		return nil, err
+	}
+	return subusers, nil
+}
+
+// CreateSubuser creates the subuser of the spec with a Swift key and returns
+// all subusers of the user afterwards.
+func (api *API) CreateSubuser(ctx context.Context, s SubuserSpec) ([]Subuser, error) {
+	params := s.params()
+	if !s.NoKey {
+		params.Set("key-type", string(KeyTypeSwift))
+		if s.SecretKey != "" {
+			params.Set("secret-key", s.SecretKey)
+		} else {
+			params.Set("generate-secret", "true")
+		}
+	}
+	return api.modifySubuser(ctx, "PUT", params)
+}
+
+// ModifySubuser changes the access level of the subuser of the spec to its
+// Access and returns all subusers of the user afterwards.
+func (api *API) ModifySubuser(ctx context.Context, s SubuserSpec) ([]Subuser, error) {
+	return api.modifySubuser(ctx, "POST", s.params())
+}
+
+// RemoveSubuser removes the subuser name of the user uid. With keepKeys, the
+// keys of the subuser are kept as keys of the user.
+func (api *API) RemoveSubuser(ctx context.Context, uid, name string, keepKeys bool) error {
+	params := url.Values{
+		"subuser": {name},
+		"uid":     {uid},
+	}
+	if keepKeys {
+		params.Set("purge-keys", "false")
+	}
+	return api.call(ctx, request{
+		method:   "DELETE",
+		resource: "user",
+		params:   params,
+	}, nil)
+}